@@ -2,6 +2,8 @@ package analysis
 
 import (
 	"codeclarity.io/internal/config"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -30,3 +32,18 @@ func getOrgID(cmd *cobra.Command) string {
 	}
 	return cfg.DefaultOrgID
 }
+
+// loadOfflineProject reads a synced project for --offline and tells the user
+// how old it is
+func loadOfflineProject(orgID, projectID string) (*store.ProjectSnapshot, error) {
+	st, err := store.Open()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := st.Project(orgID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	output.Notice("%s", store.Describe(snap.SyncedAt))
+	return snap, nil
+}
@@ -0,0 +1,166 @@
+package analysis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const analysesPath = "/org/" + codeclaritytest.OrgID + "/projects/" + codeclaritytest.ProjectID + "/analyses"
+
+// transitions makes started analyses report statuses on successive fetches
+func transitions(statuses ...codeclarity.AnalysisStatus) func(*testing.T, *clitest.Env) {
+	return func(t *testing.T, env *clitest.Env) {
+		env.Server.Transitions = statuses
+	}
+}
+
+func TestAnalysisCommands(t *testing.T) {
+	start := []string{"analysis", "start", codeclaritytest.ProjectID, "--analyzer", codeclaritytest.AnalyzerID}
+
+	cases := []clitest.Case{
+		{
+			Name:   "list",
+			Args:   []string{"analysis", "list", codeclaritytest.ProjectID},
+			Stdout: []string{"analysis-1", "success", "main"},
+		},
+		{
+			Name:   "get",
+			Args:   []string{"analysis", "get", codeclaritytest.ProjectID, codeclaritytest.AnalysisID},
+			Stdout: []string{"id: analysis-1", "commithash: 0123456789abcdef"},
+		},
+		{
+			Name:   "get unknown analysis",
+			Args:   []string{"analysis", "get", codeclaritytest.ProjectID, "analysis-404"},
+			Stderr: []string{"Failed to get analysis"},
+		},
+		{
+			Name:   "status",
+			Args:   []string{"analysis", "status", codeclaritytest.ProjectID, codeclaritytest.AnalysisID},
+			Stdout: []string{"Status:   success", "Commit:   0123456789abcdef"},
+		},
+		{
+			Name:   "status watch of finished analysis",
+			Args:   []string{"analysis", "status", codeclaritytest.ProjectID, codeclaritytest.AnalysisID, "--watch"},
+			Stdout: []string{"Status: success", "Analysis completed!"},
+		},
+		{
+			Name:   "start requires analyzer",
+			Args:   []string{"analysis", "start", codeclaritytest.ProjectID},
+			Stderr: []string{"Analyzer ID is required"},
+		},
+		{
+			Name:   "start",
+			Args:   start,
+			Stdout: []string{"Analysis started: analysis-new-1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				// Each fetch moves the analysis one step through the transitions
+				for _, want := range []string{"requested", "started", "success", "success"} {
+					res := env.Run("analysis", "status", codeclaritytest.ProjectID, "analysis-new-1")
+					if !strings.Contains(res.Stdout, "Status:   "+want) {
+						t.Errorf("status output does not report %s:\n%s", want, res.Output())
+					}
+				}
+			},
+		},
+		{
+			Name:   "start and watch success",
+			Args:   append(start, "--watch"),
+			Setup:  transitions(codeclarity.StatusSuccess),
+			Stdout: []string{"Status: success", "Analysis completed successfully!"},
+		},
+		{
+			Name:   "start and watch failure",
+			Args:   append(start, "--watch"),
+			Setup:  transitions(codeclarity.StatusFailed),
+			Stdout: []string{"Status: failed"},
+			Stderr: []string{"Analysis failed"},
+		},
+		{
+			Name: "start local",
+			Args: append(start, "--local", "app"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "app/package.json", `{"name": "app", "dependencies": {"minimist": "^1.2.5"}}`)
+				env.WriteFile(t, "app/node_modules/minimist/package.json", `{"name": "minimist"}`)
+			},
+			Stdout: []string{"package.json", "Snapshot uploaded: snapshot-new-1", "Analysis started: analysis-new-2"},
+			Check:  expectSnapshot("snapshot-new-1", "package.json"),
+		},
+		{
+			Name: "start local skips gitignored lockfile",
+			Args: append(start, "--local", "app"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "app/.gitignore", "yarn.lock\n")
+				env.WriteFile(t, "app/package.json", `{"name": "app"}`)
+				env.WriteFile(t, "app/yarn.lock", "# yarn lockfile v1\n")
+			},
+			Stdout: []string{"Snapshot uploaded: snapshot-new-1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				expectSnapshot("snapshot-new-1", "package.json")(t, env, res)
+				if strings.Contains(res.Stdout, "yarn.lock") {
+					t.Errorf("ignored lockfile is listed:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name: "start local resends snapshot when rate limited",
+			Args: append(start, "--local", "app"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "app/package.json", `{"name": "app"}`)
+				env.Server.Inject(codeclaritytest.RateLimited("/org/"+codeclaritytest.OrgID+"/projects/"+codeclaritytest.ProjectID+"/snapshots", 1, 0))
+			},
+			Stdout: []string{"Snapshot uploaded: snapshot-new-1"},
+			Check:  expectSnapshot("snapshot-new-1", "package.json"),
+		},
+		{
+			Name: "start not retried on server error",
+			Args: start,
+			Setup: clitest.Inject(codeclaritytest.Fault{
+				Method: http.MethodPost,
+				Path:   analysesPath,
+				Times:  1,
+				Status: http.StatusServiceUnavailable,
+			}),
+			Stderr: []string{"Failed to start analysis", "ServiceUnavailable"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := env.Count(http.MethodPost, analysesPath); n != 1 {
+					t.Errorf("server received %d starts, want 1", n)
+				}
+			},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		[]string{"analysis", "get", codeclaritytest.ProjectID, codeclaritytest.AnalysisID},
+		analysesPath+"/"+codeclaritytest.AnalysisID,
+		"Failed to get analysis",
+	)...)
+	cases = append(cases, clitest.FaultCases(
+		[]string{"analysis", "list", codeclaritytest.ProjectID},
+		analysesPath,
+		"Failed to list analyses",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
+
+// expectSnapshot checks that the server holds snapshot id with exactly files
+func expectSnapshot(id string, files ...string) func(*testing.T, *clitest.Env, clitest.Result) {
+	return func(t *testing.T, env *clitest.Env, res clitest.Result) {
+		snap, ok := env.Server.Snapshot(id)
+		if !ok {
+			t.Fatalf("snapshot %s was not uploaded", id)
+		}
+		if len(snap.Files) != len(files) {
+			t.Errorf("snapshot holds %d files, want %d", len(snap.Files), len(files))
+		}
+		for _, name := range files {
+			if len(snap.Files[name]) == 0 {
+				t.Errorf("snapshot is missing %s", name)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package analysis
 import (
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -20,16 +21,34 @@ var getCmd = &cobra.Command{
 			return nil
 		}
 
-		client, err := api.NewAuthenticatedClient()
-		if err != nil {
-			output.Error("Authentication required: %v", err)
-			return nil
-		}
+		var analysis *codeclarity.Analysis
+		if api.Offline() {
+			snap, err := loadOfflineProject(orgID, projectID)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			for i := range snap.Analyses {
+				if snap.Analyses[i].ID == analysisID {
+					analysis = &snap.Analyses[i]
+				}
+			}
+			if analysis == nil {
+				output.Error("Analysis %s was not synced: run 'codeclarity sync %s'", analysisID, projectID)
+				return nil
+			}
+		} else {
+			client, err := api.NewAuthenticatedClient()
+			if err != nil {
+				output.Error("Authentication required: %v", err)
+				return nil
+			}
 
-		analysis, err := client.GetAnalysis(orgID, projectID, analysisID)
-		if err != nil {
-			output.Error("Failed to get analysis: %v", err)
-			return nil
+			analysis, err = client.Analyses.Get(cmd.Context(), orgID, projectID, analysisID)
+			if err != nil {
+				output.Error("Failed to get analysis: %v", err)
+				return nil
+			}
 		}
 
 		format, _ := cmd.Root().Flags().GetString("output")
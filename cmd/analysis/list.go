@@ -2,9 +2,12 @@ package analysis
 
 import (
 	"fmt"
+	"time"
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -26,16 +29,26 @@ var listCmd = &cobra.Command{
 			return nil
 		}
 
-		client, err := api.NewAuthenticatedClient()
-		if err != nil {
-			output.Error("Authentication required: %v", err)
-			return nil
-		}
-
-		resp, err := client.ListAnalyses(orgID, projectID, listPage, listPerPage)
-		if err != nil {
-			output.Error("Failed to list analyses: %v", err)
-			return nil
+		var resp *codeclarity.PaginatedResponse[codeclarity.Analysis]
+		if api.Offline() {
+			snap, err := loadOfflineProject(orgID, projectID)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			resp = store.Paginate(snap.Analyses, listPage, listPerPage)
+		} else {
+			client, err := api.NewAuthenticatedClient()
+			if err != nil {
+				output.Error("Authentication required: %v", err)
+				return nil
+			}
+
+			resp, err = client.Analyses.List(cmd.Context(), orgID, projectID, &codeclarity.ListOptions{Page: listPage, PerPage: listPerPage})
+			if err != nil {
+				output.Error("Failed to list analyses: %v", err)
+				return nil
+			}
 		}
 
 		format, _ := cmd.Root().Flags().GetString("output")
@@ -44,7 +57,7 @@ var listCmd = &cobra.Command{
 		}
 
 		// For JSON/YAML output, return the raw API response
-		if format == "json" || format == "yaml" {
+		if output.IsStructuredFormat(format) {
 			formatter := output.NewFormatter(format)
 			return formatter.Print(resp)
 		}
@@ -55,22 +68,40 @@ var listCmd = &cobra.Command{
 		}
 
 		// Table output
-		headers := []string{"ID", "STATUS", "BRANCH", "CREATED", "STAGE"}
+		columns := []output.Column{
+			{Header: "ID"},
+			{Header: "STATUS", Color: output.StatusColor},
+			{Header: "BRANCH"},
+			{Header: "CREATED"},
+			{Header: "STAGE"},
+			{Header: "COMMIT", Wide: true},
+			{Header: "TAG", Wide: true},
+			{Header: "ANALYZER", Wide: true},
+			{Header: "STARTED", Wide: true},
+			{Header: "ENDED", Wide: true},
+		}
 		var rows [][]string
 
 		for _, a := range resp.Data {
 			stage := fmt.Sprintf("%d", a.Stage)
 			rows = append(rows, []string{
 				a.ID,
-				output.StatusColor(string(a.Status)),
+				string(a.Status),
 				a.Branch,
 				a.CreatedOn.Format("2006-01-02 15:04"),
 				stage,
+				a.CommitHash,
+				a.Tag,
+				a.AnalyzerID,
+				formatTime(a.StartedOn),
+				formatTime(a.EndedOn),
 			})
 		}
 
 		formatter := output.NewFormatter(format)
-		formatter.PrintTable(headers, rows)
+		if err := formatter.PrintColumns(columns, rows); err != nil {
+			return err
+		}
 
 		if resp.TotalPages > 1 {
 			fmt.Printf("\nPage %d of %d (total: %d)\n", resp.Page+1, resp.TotalPages, resp.TotalEntries)
@@ -84,3 +115,11 @@ func init() {
 	listCmd.Flags().IntVar(&listPage, "page", 0, "Page number (0-indexed)")
 	listCmd.Flags().IntVar(&listPerPage, "per-page", 20, "Entries per page")
 }
+
+// formatTime formats an optional timestamp for table output
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
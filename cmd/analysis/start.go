@@ -1,11 +1,15 @@
 package analysis
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"time"
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/snapshot"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +19,7 @@ var (
 	startCommit     string
 	startTag        string
 	startWatch      bool
+	startLocal      string
 )
 
 var startCmd = &cobra.Command{
@@ -22,9 +27,16 @@ var startCmd = &cobra.Command{
 	Short: "Start a new analysis",
 	Long: `Start a new security analysis for a project.
 
+With --local, the dependency manifests and lockfiles of a local directory
+(package.json, package-lock.json, yarn.lock, pnpm-lock.yaml, composer.json,
+composer.lock) are uploaded as a snapshot of the project and analyzed instead
+of the repository. Files ignored by .gitignore are left out, as are .git,
+node_modules and vendor directories.
+
 Example:
   codeclarity analysis start <project-id> --analyzer <analyzer-id> --branch main
-  codeclarity analysis start <project-id> --analyzer <analyzer-id> --branch main --watch`,
+  codeclarity analysis start <project-id> --analyzer <analyzer-id> --branch main --watch
+  codeclarity analysis start <project-id> --analyzer <analyzer-id> --local .`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectID := args[0]
@@ -39,6 +51,10 @@ Example:
 			output.Error("Analyzer ID is required. Use --analyzer")
 			return nil
 		}
+		if startLocal != "" && (startCommit != "" || startTag != "") {
+			output.Error("--commit and --tag cannot be used with --local")
+			return nil
+		}
 
 		client, err := api.NewAuthenticatedClient()
 		if err != nil {
@@ -46,7 +62,7 @@ Example:
 			return nil
 		}
 
-		req := api.AnalysisCreateRequest{
+		req := codeclarity.AnalysisCreateRequest{
 			AnalyzerID:   startAnalyzerID,
 			Branch:       startBranch,
 			CommitHash:   startCommit,
@@ -56,7 +72,16 @@ Example:
 			IsActive:     true,
 		}
 
-		analysisID, err := client.StartAnalysis(orgID, projectID, req)
+		if startLocal != "" {
+			snapshotID, err := uploadLocalSnapshot(cmd.Context(), client, orgID, projectID, startLocal)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			req.SnapshotID = snapshotID
+		}
+
+		analysisID, err := client.Analyses.Start(cmd.Context(), orgID, projectID, req)
 		if err != nil {
 			output.Error("Failed to start analysis: %v", err)
 			return nil
@@ -65,14 +90,43 @@ Example:
 		output.Success("Analysis started: %s", analysisID)
 
 		if startWatch {
-			return watchAnalysis(client, orgID, projectID, analysisID)
+			return watchAnalysis(cmd.Context(), client, orgID, projectID, analysisID)
 		}
 
 		return nil
 	},
 }
 
-func watchAnalysis(client *api.Client, orgID, projectID, analysisID string) error {
+// uploadLocalSnapshot packages the manifests and lockfiles of dir and
+// uploads them as a snapshot of the project
+func uploadLocalSnapshot(ctx context.Context, client *codeclarity.Client, orgID, projectID, dir string) (string, error) {
+	files, err := snapshot.Collect(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to collect files from %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no manifests or lockfiles found in %s", dir)
+	}
+
+	output.Info("Packaging %d files from %s:", len(files), dir)
+	for _, f := range files {
+		fmt.Printf("  %s\n", f.Path)
+	}
+
+	var archive bytes.Buffer
+	if err := snapshot.Write(&archive, dir, files); err != nil {
+		return "", fmt.Errorf("failed to package %s: %w", dir, err)
+	}
+	size := archive.Len()
+	snapshotID, err := client.Projects.UploadSnapshot(ctx, orgID, projectID, &archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload snapshot: %w", err)
+	}
+	output.Success("Snapshot uploaded: %s (%.1f KB)", snapshotID, float64(size)/1024)
+	return snapshotID, nil
+}
+
+func watchAnalysis(ctx context.Context, client *codeclarity.Client, orgID, projectID, analysisID string) error {
 	fmt.Println("\nWatching analysis progress...")
 
 	ticker := time.NewTicker(5 * time.Second)
@@ -80,7 +134,7 @@ func watchAnalysis(client *api.Client, orgID, projectID, analysisID string) erro
 
 	lastStatus := ""
 	for {
-		analysis, err := client.GetAnalysis(orgID, projectID, analysisID)
+		analysis, err := client.Analyses.Get(ctx, orgID, projectID, analysisID)
 		if err != nil {
 			output.Error("Failed to get analysis status: %v", err)
 			return nil
@@ -94,10 +148,10 @@ func watchAnalysis(client *api.Client, orgID, projectID, analysisID string) erro
 
 		// Check for terminal states
 		switch analysis.Status {
-		case api.StatusSuccess, api.StatusCompleted:
+		case codeclarity.StatusSuccess, codeclarity.StatusCompleted:
 			output.Success("Analysis completed successfully!")
 			return nil
-		case api.StatusFailed:
+		case codeclarity.StatusFailed:
 			output.Error("Analysis failed")
 			return nil
 		}
@@ -112,4 +166,5 @@ func init() {
 	startCmd.Flags().StringVar(&startCommit, "commit", "", "Specific commit to analyze")
 	startCmd.Flags().StringVar(&startTag, "tag", "", "Git tag to analyze")
 	startCmd.Flags().BoolVarP(&startWatch, "watch", "w", false, "Watch analysis progress")
+	startCmd.Flags().StringVar(&startLocal, "local", "", "Upload the manifests and lockfiles of a local directory and analyze them")
 }
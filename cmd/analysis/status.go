@@ -1,11 +1,13 @@
 package analysis
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -35,10 +37,10 @@ Use --watch to continuously poll for updates until the analysis completes.`,
 		}
 
 		if statusWatch {
-			return watchAnalysisStatus(client, orgID, projectID, analysisID)
+			return watchAnalysisStatus(cmd.Context(), client, orgID, projectID, analysisID)
 		}
 
-		analysis, err := client.GetAnalysis(orgID, projectID, analysisID)
+		analysis, err := client.Analyses.Get(cmd.Context(), orgID, projectID, analysisID)
 		if err != nil {
 			output.Error("Failed to get analysis: %v", err)
 			return nil
@@ -49,7 +51,7 @@ Use --watch to continuously poll for updates until the analysis completes.`,
 	},
 }
 
-func printAnalysisStatus(analysis *api.Analysis) {
+func printAnalysisStatus(analysis *codeclarity.Analysis) {
 	fmt.Printf("Analysis: %s\n", analysis.ID)
 	fmt.Printf("Status:   %s\n", output.StatusColor(string(analysis.Status)))
 	fmt.Printf("Branch:   %s\n", analysis.Branch)
@@ -67,7 +69,7 @@ func printAnalysisStatus(analysis *api.Analysis) {
 	}
 }
 
-func watchAnalysisStatus(client *api.Client, orgID, projectID, analysisID string) error {
+func watchAnalysisStatus(ctx context.Context, client *codeclarity.Client, orgID, projectID, analysisID string) error {
 	fmt.Println("Watching analysis status (Ctrl+C to stop)...")
 
 	ticker := time.NewTicker(5 * time.Second)
@@ -75,7 +77,7 @@ func watchAnalysisStatus(client *api.Client, orgID, projectID, analysisID string
 
 	lastStatus := ""
 	for {
-		analysis, err := client.GetAnalysis(orgID, projectID, analysisID)
+		analysis, err := client.Analyses.Get(ctx, orgID, projectID, analysisID)
 		if err != nil {
 			output.Error("Failed to get analysis: %v", err)
 			return nil
@@ -92,11 +94,11 @@ func watchAnalysisStatus(client *api.Client, orgID, projectID, analysisID string
 
 		// Check for terminal states
 		switch analysis.Status {
-		case api.StatusSuccess, api.StatusCompleted:
+		case codeclarity.StatusSuccess, codeclarity.StatusCompleted:
 			output.Success("Analysis completed!")
 			printAnalysisStatus(analysis)
 			return nil
-		case api.StatusFailed:
+		case codeclarity.StatusFailed:
 			output.Error("Analysis failed")
 			printAnalysisStatus(analysis)
 			return nil
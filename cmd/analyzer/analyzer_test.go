@@ -0,0 +1,83 @@
+package analyzer_test
+
+import (
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const analyzersPath = "/org/" + codeclaritytest.OrgID + "/analyzers"
+
+// expectAnalyzer checks that analyzer id can be fetched and is named name
+func expectAnalyzer(id, name string) func(*testing.T, *clitest.Env, clitest.Result) {
+	return func(t *testing.T, env *clitest.Env, res clitest.Result) {
+		got := env.Run("analyzer", "get", id)
+		if !strings.Contains(got.Stdout, "name: "+name) {
+			t.Errorf("analyzer %s is not named %s:\n%s", id, name, got.Output())
+		}
+	}
+}
+
+func TestAnalyzerCommands(t *testing.T) {
+	cases := []clitest.Case{
+		{
+			Name:   "list",
+			Args:   []string{"analyzer", "list"},
+			Stdout: []string{"analyzer-1", "JS analyzer", "Yes"},
+		},
+		{
+			Name:   "list yaml",
+			Args:   []string{"--output", "yaml", "analyzer", "list"},
+			Stdout: []string{"name: JS analyzer"},
+		},
+		{
+			Name:   "get",
+			Args:   []string{"analyzer", "get", codeclaritytest.AnalyzerID},
+			Stdout: []string{"name: JS analyzer", "name: vuln-finder", "- javascript"},
+		},
+		{
+			Name:   "get unknown analyzer",
+			Args:   []string{"analyzer", "get", "analyzer-404"},
+			Stderr: []string{"Failed to get analyzer"},
+		},
+		{
+			Name:   "create from flags",
+			Args:   []string{"analyzer", "create", "--name", "PHP analyzer", "--description", "Composer projects"},
+			Stdout: []string{"Analyzer created: analyzer-new-1"},
+			Check:  expectAnalyzer("analyzer-new-1", "PHP analyzer"),
+		},
+		{
+			Name: "create from file",
+			Args: []string{"analyzer", "create", "--file", "analyzer.yaml"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "analyzer.yaml", `name: Custom
+description: Only the SBOM
+steps:
+  - - name: js-sbom
+      version: 1.0.0
+`)
+			},
+			Stdout: []string{"Analyzer created: analyzer-new-1"},
+			Check:  expectAnalyzer("analyzer-new-1", "Custom"),
+		},
+		{
+			Name:   "create requires name",
+			Args:   []string{"analyzer", "create", "--description", "Composer projects"},
+			Stderr: []string{"Name is required"},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		[]string{"analyzer", "list"},
+		analyzersPath,
+		"Failed to list analyzers",
+	)...)
+	cases = append(cases, clitest.FaultCases(
+		[]string{"analyzer", "get", codeclaritytest.AnalyzerID},
+		analyzersPath+"/"+codeclaritytest.AnalyzerID,
+		"Failed to get analyzer",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
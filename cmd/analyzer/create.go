@@ -7,6 +7,7 @@ import (
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -40,7 +41,7 @@ Or specify basic options:
 			return nil
 		}
 
-		var req api.AnalyzerCreateRequest
+		var req codeclarity.AnalyzerCreateRequest
 
 		if createFile != "" {
 			// Load from file
@@ -68,14 +69,14 @@ Or specify basic options:
 				return nil
 			}
 
-			req = api.AnalyzerCreateRequest{
+			req = codeclarity.AnalyzerCreateRequest{
 				Name:        createName,
 				Description: createDescription,
-				Steps:       [][]api.Stage{}, // Empty steps - user should use file for complex analyzers
+				Steps:       [][]codeclarity.Stage{}, // Empty steps - user should use file for complex analyzers
 			}
 		}
 
-		id, err := client.CreateAnalyzer(orgID, req)
+		id, err := client.Analyzers.Create(cmd.Context(), orgID, req)
 		if err != nil {
 			output.Error("Failed to create analyzer: %v", err)
 			return nil
@@ -89,7 +90,7 @@ Or specify basic options:
 }
 
 func init() {
-	createCmd.Flags().StringVarP(&createFile, "file", "f", "", "Path to analyzer configuration file (JSON or YAML)")
+	createCmd.Flags().StringVar(&createFile, "file", "", "Path to analyzer configuration file (JSON or YAML)")
 	createCmd.Flags().StringVar(&createName, "name", "", "Analyzer name")
 	createCmd.Flags().StringVar(&createDescription, "description", "", "Analyzer description")
 }
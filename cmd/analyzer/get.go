@@ -25,7 +25,7 @@ var getCmd = &cobra.Command{
 			return nil
 		}
 
-		analyzer, err := client.GetAnalyzer(orgID, analyzerID)
+		analyzer, err := client.Analyzers.Get(cmd.Context(), orgID, analyzerID)
 		if err != nil {
 			output.Error("Failed to get analyzer: %v", err)
 			return nil
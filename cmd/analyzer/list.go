@@ -5,6 +5,7 @@ import (
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -30,7 +31,7 @@ var listCmd = &cobra.Command{
 			return nil
 		}
 
-		resp, err := client.ListAnalyzers(orgID, listPage, listPerPage)
+		resp, err := client.Analyzers.List(cmd.Context(), orgID, &codeclarity.ListOptions{Page: listPage, PerPage: listPerPage})
 		if err != nil {
 			output.Error("Failed to list analyzers: %v", err)
 			return nil
@@ -42,7 +43,7 @@ var listCmd = &cobra.Command{
 		}
 
 		// For JSON/YAML output, return the raw API response
-		if format == "json" || format == "yaml" {
+		if output.IsStructuredFormat(format) {
 			formatter := output.NewFormatter(format)
 			return formatter.Print(resp)
 		}
@@ -53,7 +54,15 @@ var listCmd = &cobra.Command{
 		}
 
 		// Table output
-		headers := []string{"ID", "NAME", "DESCRIPTION", "LANGUAGES", "GLOBAL"}
+		columns := []output.Column{
+			{Header: "ID"},
+			{Header: "NAME"},
+			{Header: "DESCRIPTION", MaxWidth: 40},
+			{Header: "LANGUAGES"},
+			{Header: "GLOBAL"},
+			{Header: "STEPS", Wide: true},
+			{Header: "CREATED", Wide: true},
+		}
 		var rows [][]string
 
 		for _, a := range resp.Data {
@@ -68,14 +77,18 @@ var listCmd = &cobra.Command{
 			rows = append(rows, []string{
 				a.ID,
 				a.Name,
-				truncate(a.Description, 40),
+				a.Description,
 				languages,
 				global,
+				fmt.Sprintf("%d", len(a.Steps)),
+				a.CreatedOn.Format("2006-01-02 15:04"),
 			})
 		}
 
 		formatter := output.NewFormatter(format)
-		formatter.PrintTable(headers, rows)
+		if err := formatter.PrintColumns(columns, rows); err != nil {
+			return err
+		}
 
 		if resp.TotalPages > 1 {
 			fmt.Printf("\nPage %d of %d (total: %d)\n", resp.Page+1, resp.TotalPages, resp.TotalEntries)
@@ -89,10 +102,3 @@ func init() {
 	listCmd.Flags().IntVar(&listPage, "page", 0, "Page number (0-indexed)")
 	listCmd.Flags().IntVar(&listPerPage, "per-page", 20, "Entries per page")
 }
-
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
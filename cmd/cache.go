@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"codeclarity.io/internal/cache"
+	"codeclarity.io/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local response cache",
+	Long: `Manage the local API response cache.
+
+Responses are cached in ~/.codeclarity/cache, grouped by organization.
+Cached entries are revalidated with ETag/Last-Modified when the API supports
+it and otherwise reused for 5 minutes. Results of finished analyses never
+change and are reused without contacting the API.
+
+Use --no-cache on any command to bypass the cache.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache statistics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		stats, err := cache.ReadStats(dir)
+		if err != nil {
+			output.Error("Failed to read cache: %v", err)
+			return nil
+		}
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		if output.IsStructuredFormat(format) {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(stats)
+		}
+
+		fmt.Printf("Cache:     %s\n", stats.Dir)
+		fmt.Printf("Entries:   %d (%d fresh, %d immutable)\n", stats.Entries, stats.Fresh, stats.Immutable)
+		fmt.Printf("Size:      %s\n", formatBytes(stats.Bytes))
+		if stats.Oldest != nil {
+			fmt.Printf("Oldest:    %s\n", stats.Oldest.Format(time.RFC3339))
+		}
+		if len(stats.ByOrg) == 0 {
+			return nil
+		}
+
+		fmt.Println()
+		var rows [][]string
+		for org, n := range stats.ByOrg {
+			rows = append(rows, []string{org, strconv.Itoa(n)})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		return output.NewFormatter("table").PrintTable([]string{"ORGANIZATION", "ENTRIES"}, rows)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [org-id]",
+	Short: "Remove cached responses",
+	Long:  `Remove cached responses, for every organization or only the given one.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orgID := ""
+		if len(args) > 0 {
+			orgID = args[0]
+		}
+
+		dir, err := cache.Dir()
+		if err != nil {
+			return err
+		}
+		if err := cache.Clear(dir, orgID); err != nil {
+			output.Error("Failed to clear cache: %v", err)
+			return nil
+		}
+
+		if orgID != "" {
+			output.Success("Cleared cache for organization %s", orgID)
+		} else {
+			output.Success("Cleared cache")
+		}
+		return nil
+	},
+}
+
+// formatBytes renders a size in human readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
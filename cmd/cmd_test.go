@@ -0,0 +1,126 @@
+package cmd_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+func TestRootCommands(t *testing.T) {
+	cases := []clitest.Case{
+		{
+			Name:   "version",
+			Args:   []string{"version"},
+			Stdout: []string{"codeclarity version dev"},
+		},
+		{
+			Name:   "config view",
+			Args:   []string{"config", "view"},
+			Stdout: []string{"default_org_id: org-1"},
+		},
+		{
+			Name:   "config set",
+			Args:   []string{"config", "set", "org", "org-2"},
+			Stdout: []string{"Set org = org-2"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Expect(t, "org-2", "config", "get", "org")
+			},
+		},
+		{
+			Name:   "login",
+			Args:   []string{"login", "--email", codeclaritytest.Email, "--password", codeclaritytest.Password},
+			Stdout: []string{"Logged in as dev@example.com", "Default organization: org-1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if _, err := os.Stat(env.Path(".codeclarity/credentials.yaml")); err != nil {
+					t.Errorf("credentials were not stored: %v", err)
+				}
+			},
+		},
+		{
+			Name:   "login with wrong password",
+			Args:   []string{"login", "--email", codeclaritytest.Email, "--password", "wrong"},
+			Stderr: []string{"Authentication failed"},
+		},
+		{
+			Name:   "logout",
+			Args:   []string{"logout"},
+			Stdout: []string{"Logged out"},
+		},
+		{
+			Name:   "sync",
+			Args:   []string{"sync", codeclaritytest.ProjectID},
+			Stdout: []string{"Syncing analysis analysis-1", "Synced example/web: 1 analyses, results for 1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Expect(t, "1 already up to date", "sync", codeclaritytest.ProjectID)
+
+				env.Server.Close()
+				res = env.Run("--offline", "result", "summary", codeclaritytest.ProjectID, codeclaritytest.AnalysisID)
+				if !strings.Contains(res.Stdout, "Critical: 1") {
+					t.Errorf("synced results are not served offline:\n%s", res.Output())
+				}
+			},
+		},
+		{
+			Name:   "offline without synced data",
+			Args:   []string{"--offline", "project", "list"},
+			Stderr: []string{"--offline"},
+		},
+		{
+			Name:   "cache stats",
+			Args:   []string{"project", "list"},
+			Stdout: []string{"example/web"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Expect(t, "Entries:   1", "cache", "stats")
+				env.Expect(t, "Cleared cache", "cache", "clear")
+				env.Expect(t, "Entries:   0", "cache", "stats")
+			},
+		},
+		{
+			Name: "record cache hits",
+			Args: []string{"--record", "recording", "result", "summary", codeclaritytest.ProjectID, codeclaritytest.AnalysisID},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				// Warm the cache: results of finished analyses are then served
+				// without a request and the rest is revalidated with a 304
+				env.Expect(t, "Critical: 1", "result", "summary", codeclaritytest.ProjectID, codeclaritytest.AnalysisID)
+			},
+			Stdout: []string{"Critical: 1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				entries, err := os.ReadDir(env.Path("recording"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, e := range entries {
+					data, err := os.ReadFile(env.Path("recording/" + e.Name()))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !strings.Contains(string(data), `"status": 200`) {
+						t.Errorf("%s does not record a served response:\n%s", e.Name(), data)
+					}
+				}
+
+				env.Server.Close()
+				env.Expect(t, "Critical: 1", "--replay", "recording", "result", "summary", codeclaritytest.ProjectID, codeclaritytest.AnalysisID)
+			},
+		},
+		{
+			Name:   "record and replay",
+			Args:   []string{"--record", "recording", "project", "get", codeclaritytest.ProjectID},
+			Stdout: []string{"name: example/web"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Server.Close()
+				env.Expect(t, "name: example/web", "--replay", "recording", "project", "get", codeclaritytest.ProjectID)
+			},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		[]string{"sync", codeclaritytest.ProjectID},
+		"/org/"+codeclaritytest.OrgID+"/projects/"+codeclaritytest.ProjectID,
+		"Failed to get project",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
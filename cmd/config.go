@@ -38,7 +38,7 @@ var configSetCmd = &cobra.Command{
 Available keys:
   api_base_url, url     API base URL
   default_org_id, org   Default organization ID
-  output_format, format Default output format (table, json, yaml)
+  output_format, format Default output format (table, json, yaml, csv, tsv)
   debug                 Enable debug mode (true/false)`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -10,6 +10,7 @@ import (
 	"codeclarity.io/internal/auth"
 	"codeclarity.io/internal/config"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
@@ -72,16 +73,24 @@ variable instead of interactive login.`,
 		}
 
 		// Authenticate
-		client := api.NewClient(cfg.APIBaseURL)
-		resp, err := client.Authenticate(email, password)
+		client, err := api.NewClient(cfg.APIBaseURL)
+		if err != nil {
+			output.Error("Failed to create client: %v", err)
+			return nil
+		}
+		resp, err := client.Auth.Authenticate(cmd.Context(), email, password)
 		if err != nil {
 			output.Error("Authentication failed: %v", err)
 			return nil
 		}
 
 		// Get user info
-		client.SetToken(resp.Token)
-		user, err := client.GetCurrentUser()
+		client, err = api.NewClient(cfg.APIBaseURL, codeclarity.WithToken(resp.Token))
+		if err != nil {
+			output.Error("Failed to create client: %v", err)
+			return nil
+		}
+		user, err := client.Auth.CurrentUser(cmd.Context())
 		if err != nil {
 			output.Error("Failed to get user info: %v", err)
 			return nil
@@ -0,0 +1,29 @@
+package org
+
+import (
+	"codeclarity.io/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// OrgCmd represents the org command group
+var OrgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Organization-wide views",
+	Long:  `Views spanning every project of an organization.`,
+}
+
+func init() {
+	OrgCmd.AddCommand(overviewCmd)
+}
+
+// getOrgID returns the organization ID from flag or config
+func getOrgID(cmd *cobra.Command) string {
+	if orgID := cmd.Root().Flag("org").Value.String(); orgID != "" {
+		return orgID
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.DefaultOrgID
+}
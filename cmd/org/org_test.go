@@ -0,0 +1,72 @@
+package org_test
+
+import (
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const projectsPath = "/org/" + codeclaritytest.OrgID + "/projects"
+
+const ignoreFile = `suppressions:
+  - id: CVE-2021-44906
+    package: minimist
+    justification: Only used by build scripts
+`
+
+func TestOrgCommands(t *testing.T) {
+	cases := []clitest.Case{
+		{
+			Name:   "overview",
+			Args:   []string{"org", "overview"},
+			Stdout: []string{"RANK", "example/web", "stale"},
+		},
+		{
+			Name:   "overview json",
+			Args:   []string{"--output", "json", "org", "overview"},
+			Stdout: []string{`"analysis_id": "analysis-1"`, `"critical": 1`, `"stale": true`},
+		},
+		{
+			Name:   "overview not stale",
+			Args:   []string{"org", "overview", "--stale-after", "1000000h"},
+			Stdout: []string{"ok"},
+		},
+		{
+			Name: "overview with suppressions",
+			Args: []string{"--output", "json", "org", "overview"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, ".codeclarity-ignore.yaml", ignoreFile)
+			},
+			Stdout: []string{`"critical": 0`, `"suppressed": 1`},
+		},
+		{
+			Name: "overview of project without finished analysis",
+			Args: []string{"org", "overview"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.Server.SetAnalysisStatus(codeclaritytest.AnalysisID, codeclarity.StatusStarted)
+			},
+			Stdout: []string{"never scanned"},
+		},
+		{
+			Name:   "overview rejects workers",
+			Args:   []string{"org", "overview", "--workers", "0"},
+			Stderr: []string{"--workers must be at least 1"},
+		},
+		{
+			Name:   "overview with failing project",
+			Args:   []string{"org", "overview"},
+			Setup:  clitest.Inject(codeclaritytest.Unauthorized(projectsPath + "/" + codeclaritytest.ProjectID + "/analyses")),
+			Stdout: []string{"error"},
+			Stderr: []string{"Could not assess 1 projects"},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		[]string{"org", "overview"},
+		projectsPath,
+		"Failed to list projects",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
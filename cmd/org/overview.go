@@ -0,0 +1,278 @@
+package org
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overviewWorkers    int
+	overviewStaleAfter time.Duration
+	overviewTop        int
+	overviewSuppress   suppress.Flags
+)
+
+// projectRisk is one project's standing in the overview
+type projectRisk struct {
+	Rank        int        `json:"rank"`
+	ProjectID   string     `json:"project_id"`
+	Project     string     `json:"project"`
+	AnalysisID  string     `json:"analysis_id,omitempty"`
+	Branch      string     `json:"branch,omitempty"`
+	LastScan    *time.Time `json:"last_scan,omitempty"`
+	AgeDays     int        `json:"age_days"`
+	Stale       bool       `json:"stale"`
+	Total       int        `json:"total"`
+	Critical    int        `json:"critical"`
+	High        int        `json:"high"`
+	Medium      int        `json:"medium"`
+	Low         int        `json:"low"`
+	Suppressed  int        `json:"suppressed"`
+	Error       string     `json:"error,omitempty"`
+	lastScanned time.Time
+}
+
+var overviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Rank projects by risk",
+	Long: `Rank every project of the organization by the results of its latest
+successful analysis: most critical vulnerabilities first, then most high
+ones, then the oldest scans. Projects never analyzed successfully are listed
+last.
+
+Projects are fetched concurrently by a bounded pool of --workers. Scans older
+than --stale-after are flagged as stale. Vulnerabilities suppressed by the
+ignore file are left out of the ranking and counted separately:
+  codeclarity org overview --top 10
+  codeclarity org overview --stale-after 72h --output csv > overview.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+		if overviewWorkers < 1 {
+			output.Error("--workers must be at least 1")
+			return nil
+		}
+
+		suppressions, err := overviewSuppress.Load()
+		if err != nil {
+			output.Error("Failed to load suppressions: %v", err)
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		ctx := cmd.Context()
+		projects, err := client.Projects.All(ctx, orgID)
+		if err != nil {
+			output.Error("Failed to list projects: %v", err)
+			return nil
+		}
+
+		risks := make([]projectRisk, len(projects))
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for range min(overviewWorkers, len(projects)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					risks[i] = assessProject(ctx, client, orgID, projects[i], suppressions)
+				}
+			}()
+		}
+		for i := range projects {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		failed := 0
+		now := time.Now()
+		for i := range risks {
+			r := &risks[i]
+			if r.Error != "" {
+				failed++
+			}
+			if r.LastScan != nil {
+				r.AgeDays = int(now.Sub(*r.LastScan).Hours() / 24)
+				r.Stale = now.Sub(*r.LastScan) > overviewStaleAfter
+			}
+		}
+		rankRisks(risks)
+		if overviewTop > 0 && len(risks) > overviewTop {
+			risks = risks[:overviewTop]
+		}
+		if failed > 0 {
+			output.Notice("Could not assess %d projects", failed)
+		}
+		suppress.Report(suppressions, failed == 0)
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		if output.IsStructuredFormat(format) {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(risks)
+		}
+
+		if len(risks) == 0 {
+			output.Info("No projects found")
+			return nil
+		}
+
+		columns := []output.Column{
+			{Header: "Rank"},
+			{Header: "Project", MaxWidth: 40},
+			{Header: "Critical"},
+			{Header: "High"},
+			{Header: "Medium"},
+			{Header: "Low"},
+			{Header: "Suppressed", Wide: suppressions.Empty()},
+			{Header: "Last Scan"},
+			{Header: "Age"},
+			{Header: "Status", Color: output.StatusColor},
+			{Header: "Analysis", Wide: true},
+			{Header: "Branch", Wide: true},
+			{Header: "Error", Wide: true},
+		}
+		var rows [][]string
+		for _, r := range risks {
+			lastScan, age := "-", "-"
+			if r.LastScan != nil {
+				lastScan = r.LastScan.Local().Format("2006-01-02 15:04")
+				age = store.FormatAge(now.Sub(*r.LastScan))
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", r.Rank),
+				r.Project,
+				fmt.Sprintf("%d", r.Critical),
+				fmt.Sprintf("%d", r.High),
+				fmt.Sprintf("%d", r.Medium),
+				fmt.Sprintf("%d", r.Low),
+				fmt.Sprintf("%d", r.Suppressed),
+				lastScan,
+				age,
+				riskStatus(r),
+				r.AnalysisID,
+				r.Branch,
+				r.Error,
+			})
+		}
+
+		formatter := output.NewFormatter("table")
+		return formatter.PrintColumns(columns, rows)
+	},
+}
+
+// assessProject finds the latest successful analysis of a project and
+// fetches its vulnerability statistics, less the suppressed vulnerabilities
+func assessProject(ctx context.Context, client *codeclarity.Client, orgID string, project codeclarity.Project, suppressions *suppress.Set) projectRisk {
+	r := projectRisk{ProjectID: project.ID, Project: project.Name}
+
+	analyses, err := client.Analyses.All(ctx, orgID, project.ID)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	var latest *codeclarity.Analysis
+	for i, a := range analyses {
+		if a.Status.Succeeded() && (latest == nil || a.CreatedOn.After(latest.CreatedOn)) {
+			latest = &analyses[i]
+		}
+	}
+	if latest == nil {
+		return r
+	}
+
+	scanned := latest.CreatedOn
+	if latest.EndedOn != nil {
+		scanned = *latest.EndedOn
+	}
+	r.AnalysisID = latest.ID
+	r.Branch = latest.Branch
+	r.LastScan = &scanned
+	r.lastScanned = scanned
+
+	stats, err := client.Results.VulnerabilityStats(ctx, orgID, project.ID, latest.ID, "")
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if !suppressions.Empty() {
+		vulns, err := client.Results.AllVulnerabilities(ctx, orgID, project.ID, latest.ID, "")
+		if err != nil {
+			r.Error = err.Error()
+			return r
+		}
+		_, matched := suppressions.Filter(vulns)
+		counts := suppress.Count(matched)
+		stats = counts.Subtract(stats)
+		r.Suppressed = counts.Total
+	}
+	r.Total = stats.Total
+	r.Critical = stats.Critical
+	r.High = stats.High
+	r.Medium = stats.Medium
+	r.Low = stats.Low
+	return r
+}
+
+// rankRisks orders projects by critical then high counts, then by oldest
+// scan, with unscanned projects last, and numbers them
+func rankRisks(risks []projectRisk) {
+	sort.SliceStable(risks, func(i, j int) bool {
+		a, b := risks[i], risks[j]
+		if (a.LastScan == nil) != (b.LastScan == nil) {
+			return a.LastScan != nil
+		}
+		if a.Critical != b.Critical {
+			return a.Critical > b.Critical
+		}
+		if a.High != b.High {
+			return a.High > b.High
+		}
+		if !a.lastScanned.Equal(b.lastScanned) {
+			return a.lastScanned.Before(b.lastScanned)
+		}
+		return a.Project < b.Project
+	})
+	for i := range risks {
+		risks[i].Rank = i + 1
+	}
+}
+
+// riskStatus summarizes whether a project's results can be relied on
+func riskStatus(r projectRisk) string {
+	switch {
+	case r.Error != "":
+		return "error"
+	case r.LastScan == nil:
+		return "never scanned"
+	case r.Stale:
+		return "stale"
+	}
+	return "ok"
+}
+
+func init() {
+	overviewCmd.Flags().IntVar(&overviewWorkers, "workers", 4, "Number of projects fetched concurrently")
+	overviewCmd.Flags().DurationVar(&overviewStaleAfter, "stale-after", 7*24*time.Hour, "Age after which a project's last scan is flagged as stale")
+	overviewCmd.Flags().IntVar(&overviewTop, "top", 0, "Only show the N riskiest projects (0 for all)")
+	overviewSuppress.Register(overviewCmd.Flags())
+}
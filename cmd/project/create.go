@@ -5,6 +5,7 @@ import (
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -45,14 +46,14 @@ Example:
 			return nil
 		}
 
-		req := api.ProjectImportRequest{
+		req := codeclarity.ProjectImportRequest{
 			IntegrationID: createIntegrationID,
 			URL:           createURL,
 			Name:          createName,
 			Description:   createDescription,
 		}
 
-		id, err := client.ImportProject(orgID, req)
+		id, err := client.Projects.Import(cmd.Context(), orgID, req)
 		if err != nil {
 			output.Error("Failed to import project: %v", err)
 			return nil
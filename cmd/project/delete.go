@@ -0,0 +1,78 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var deleteYes bool
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <project-id>",
+	Short: "Delete a project",
+	Long: `Delete a project together with its analyses and results. This cannot be
+undone.
+
+The project is shown and confirmation is asked for unless --yes is given.
+Without a terminal, --yes is required.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		ctx := cmd.Context()
+		project, err := client.Projects.Get(ctx, orgID, projectID)
+		if err != nil {
+			output.Error("Failed to get project: %v", err)
+			return nil
+		}
+
+		if !deleteYes {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				output.Error("Refusing to delete without confirmation. Use --yes")
+				return nil
+			}
+			fmt.Printf("Project:  %s (%s)\n", project.Name, project.ID)
+			fmt.Printf("URL:      %s\n", project.URL)
+			fmt.Print("Delete this project and all of its analyses? [y/N] ")
+			reader := bufio.NewReader(os.Stdin)
+			answer, _ := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+			default:
+				output.Info("Aborted")
+				return nil
+			}
+		}
+
+		if err := client.Projects.Delete(ctx, orgID, projectID); err != nil {
+			output.Error("Failed to delete project: %v", err)
+			return nil
+		}
+
+		output.Success("Project deleted: %s (%s)", project.Name, project.ID)
+		return nil
+	},
+}
+
+func init() {
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Delete without asking for confirmation")
+}
@@ -25,7 +25,7 @@ var getCmd = &cobra.Command{
 			return nil
 		}
 
-		project, err := client.GetProject(orgID, projectID)
+		project, err := client.Projects.Get(cmd.Context(), orgID, projectID)
 		if err != nil {
 			output.Error("Failed to get project: %v", err)
 			return nil
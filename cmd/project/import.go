@@ -0,0 +1,289 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	importFile        string
+	importIntegration string
+	importWorkers     int
+	importDryRun      bool
+)
+
+// importManifest lists repositories to import
+type importManifest struct {
+	// Integration is used by repositories that do not set their own
+	Integration  string       `yaml:"integration"`
+	Repositories []importRepo `yaml:"repositories"`
+}
+
+type importRepo struct {
+	URL         string `yaml:"url"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Integration string `yaml:"integration"`
+}
+
+// importResult is the outcome of importing one repository
+type importResult struct {
+	URL       string `json:"url"`
+	Name      string `json:"name,omitempty"`
+	Status    string `json:"status"`
+	ProjectID string `json:"project_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	importImported = "imported"
+	importSkipped  = "skipped"
+	importFailed   = "failed"
+	importPlanned  = "would import"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --file <repos.yaml>",
+	Short: "Import projects in bulk from a manifest",
+	Long: `Import every repository listed in a YAML manifest. Repositories whose URL
+is already imported are skipped, so the same manifest can be applied again
+after adding entries.
+
+  integration: <integration-id>      # default for all repositories
+  repositories:
+    - url: https://github.com/org/api
+      name: api                       # optional
+      description: Public API         # optional
+    - url: https://gitlab.com/org/web
+      integration: <integration-id>   # overrides the default
+
+Imports run concurrently (--workers) and a summary of failures is printed
+at the end. Use --dry-run to list what would be imported.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+		if importFile == "" {
+			output.Error("A manifest is required. Use --file <repos.yaml>")
+			return nil
+		}
+		if importWorkers < 1 {
+			output.Error("--workers must be at least 1")
+			return nil
+		}
+
+		repos, err := loadImportManifest(importFile, importIntegration)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		ctx := cmd.Context()
+		existing, err := client.Projects.All(ctx, orgID)
+		if err != nil {
+			output.Error("Failed to list projects: %v", err)
+			return nil
+		}
+		imported := map[string]string{}
+		for _, p := range existing {
+			imported[normalizeRepoURL(p.URL)] = p.ID
+		}
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		structured := output.IsStructuredFormat(format)
+
+		results := make([]importResult, len(repos))
+		var pending []int
+		for i, repo := range repos {
+			results[i] = importResult{URL: repo.URL, Name: repo.Name}
+			if id, ok := imported[normalizeRepoURL(repo.URL)]; ok {
+				results[i].Status = importSkipped
+				results[i].ProjectID = id
+				continue
+			}
+			if importDryRun {
+				results[i].Status = importPlanned
+				continue
+			}
+			pending = append(pending, i)
+		}
+
+		var (
+			mu   sync.Mutex
+			done int
+			wg   sync.WaitGroup
+		)
+		jobs := make(chan int)
+		for range min(importWorkers, len(pending)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					result := importRepository(ctx, client, orgID, repos[i])
+
+					mu.Lock()
+					results[i] = result
+					done++
+					if !structured {
+						printImportProgress(done, len(pending), result)
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+		for _, i := range pending {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		if structured {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(results)
+		}
+
+		counts := map[string]int{}
+		var failures [][]string
+		for _, r := range results {
+			counts[r.Status]++
+			if r.Status == importFailed {
+				failures = append(failures, []string{r.URL, r.Error})
+			}
+		}
+
+		if importDryRun {
+			for _, r := range results {
+				if r.Status == importPlanned {
+					fmt.Printf("Would import %s\n", r.URL)
+				}
+			}
+			output.Info("%d to import, %d already imported", counts[importPlanned], counts[importSkipped])
+			return nil
+		}
+
+		if len(pending) > 0 {
+			fmt.Println()
+		}
+		summary := fmt.Sprintf("Imported %d, skipped %d already imported, %d failed",
+			counts[importImported], counts[importSkipped], counts[importFailed])
+		if len(failures) == 0 {
+			output.Success("%s", summary)
+			return nil
+		}
+		output.Warning("%s", summary)
+		fmt.Println()
+		formatter := output.NewFormatter("table")
+		return formatter.PrintColumns([]output.Column{{Header: "URL"}, {Header: "Error", MaxWidth: 60}}, failures)
+	},
+}
+
+// loadImportManifest reads and validates a manifest, filling in the default
+// integration and dropping repeated URLs
+func loadImportManifest(path, defaultIntegration string) ([]importRepo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest importManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if manifest.Integration == "" {
+		manifest.Integration = defaultIntegration
+	}
+	if len(manifest.Repositories) == 0 {
+		return nil, fmt.Errorf("%s lists no repositories", path)
+	}
+
+	seen := map[string]bool{}
+	var repos []importRepo
+	for i, repo := range manifest.Repositories {
+		repo.URL = strings.TrimSpace(repo.URL)
+		if repo.URL == "" {
+			return nil, fmt.Errorf("%s: repository #%d has no url", path, i+1)
+		}
+		if repo.Integration == "" {
+			repo.Integration = manifest.Integration
+		}
+		if repo.Integration == "" {
+			return nil, fmt.Errorf("%s: repository %s has no integration; set one or use --integration", path, repo.URL)
+		}
+		key := normalizeRepoURL(repo.URL)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// importRepository imports one repository, treating a conflict as already
+// imported
+func importRepository(ctx context.Context, client *codeclarity.Client, orgID string, repo importRepo) importResult {
+	result := importResult{URL: repo.URL, Name: repo.Name}
+	id, err := client.Projects.Import(ctx, orgID, codeclarity.ProjectImportRequest{
+		IntegrationID: repo.Integration,
+		URL:           repo.URL,
+		Name:          repo.Name,
+		Description:   repo.Description,
+	})
+	switch {
+	case errors.Is(err, codeclarity.ErrConflict):
+		result.Status = importSkipped
+	case err != nil:
+		result.Status = importFailed
+		result.Error = err.Error()
+	default:
+		result.Status = importImported
+		result.ProjectID = id
+	}
+	return result
+}
+
+func printImportProgress(done, total int, r importResult) {
+	prefix := fmt.Sprintf("[%d/%d]", done, total)
+	switch r.Status {
+	case importImported:
+		output.Success("%s Imported %s (%s)", prefix, r.URL, r.ProjectID)
+	case importSkipped:
+		output.Info("%s Skipped %s: already imported", prefix, r.URL)
+	default:
+		output.Warning("%s Failed %s: %s", prefix, r.URL, r.Error)
+	}
+}
+
+// normalizeRepoURL compares repository URLs regardless of case, trailing
+// slashes and a .git suffix
+func normalizeRepoURL(u string) string {
+	u = strings.ToLower(strings.TrimSpace(u))
+	u = strings.TrimSuffix(u, "/")
+	return strings.TrimSuffix(u, ".git")
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFile, "file", "", "YAML manifest listing the repositories to import (required)")
+	importCmd.Flags().StringVar(&importIntegration, "integration", "", "Integration ID for repositories without one")
+	importCmd.Flags().IntVar(&importWorkers, "workers", 4, "Number of imports run concurrently")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "List the repositories that would be imported without importing them")
+}
@@ -5,6 +5,7 @@ import (
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -31,7 +32,10 @@ var listCmd = &cobra.Command{
 			return nil
 		}
 
-		resp, err := client.ListProjects(orgID, listPage, listPerPage, listSearch)
+		resp, err := client.Projects.List(cmd.Context(), orgID, &codeclarity.ProjectListOptions{
+			ListOptions: codeclarity.ListOptions{Page: listPage, PerPage: listPerPage},
+			Search:      listSearch,
+		})
 		if err != nil {
 			output.Error("Failed to list projects: %v", err)
 			return nil
@@ -43,7 +47,7 @@ var listCmd = &cobra.Command{
 		}
 
 		// For JSON/YAML output, return the raw API response
-		if format == "json" || format == "yaml" {
+		if output.IsStructuredFormat(format) {
 			formatter := output.NewFormatter(format)
 			return formatter.Print(resp)
 		}
@@ -54,21 +58,39 @@ var listCmd = &cobra.Command{
 		}
 
 		// Table output
-		headers := []string{"ID", "NAME", "URL", "BRANCH", "TYPE"}
+		columns := []output.Column{
+			{Header: "ID"},
+			{Header: "NAME"},
+			{Header: "URL", MaxWidth: 50},
+			{Header: "BRANCH"},
+			{Header: "TYPE"},
+			{Header: "INTEGRATION", Wide: true},
+			{Header: "DESCRIPTION", Wide: true},
+			{Header: "DOWNLOADED", Wide: true},
+			{Header: "INVALID", Wide: true},
+			{Header: "ADDED", Wide: true},
+		}
 		var rows [][]string
 
 		for _, p := range resp.Data {
 			rows = append(rows, []string{
 				p.ID,
 				p.Name,
-				truncate(p.URL, 50),
+				p.URL,
 				p.DefaultBranch,
 				p.IntegrationProvider,
+				p.IntegrationType,
+				p.Description,
+				fmt.Sprintf("%t", p.Downloaded),
+				fmt.Sprintf("%t", p.Invalid),
+				p.AddedOn.Format("2006-01-02 15:04"),
 			})
 		}
 
 		formatter := output.NewFormatter(format)
-		formatter.PrintTable(headers, rows)
+		if err := formatter.PrintColumns(columns, rows); err != nil {
+			return err
+		}
 
 		if resp.TotalPages > 1 {
 			fmt.Printf("\nPage %d of %d (total: %d)\n", resp.Page+1, resp.TotalPages, resp.TotalEntries)
@@ -83,10 +105,3 @@ func init() {
 	listCmd.Flags().IntVar(&listPerPage, "per-page", 20, "Entries per page")
 	listCmd.Flags().StringVar(&listSearch, "search", "", "Search filter")
 }
-
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
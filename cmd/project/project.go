@@ -9,13 +9,17 @@ import (
 var ProjectCmd = &cobra.Command{
 	Use:   "project",
 	Short: "Manage projects",
-	Long:  `Create, list, and manage projects.`,
+	Long:  `Create, list, update, refresh and delete projects.`,
 }
 
 func init() {
 	ProjectCmd.AddCommand(listCmd)
 	ProjectCmd.AddCommand(createCmd)
 	ProjectCmd.AddCommand(getCmd)
+	ProjectCmd.AddCommand(importCmd)
+	ProjectCmd.AddCommand(updateCmd)
+	ProjectCmd.AddCommand(deleteCmd)
+	ProjectCmd.AddCommand(refreshCmd)
 }
 
 // getOrgID returns the organization ID from flag or config
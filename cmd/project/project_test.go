@@ -0,0 +1,175 @@
+package project_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const projectPath = "/org/" + codeclaritytest.OrgID + "/projects"
+
+const manifest = `integration: integration-1
+repositories:
+  - url: https://github.com/example/api
+    name: example/api
+  - url: https://github.com/example/web
+`
+
+func TestProjectCommands(t *testing.T) {
+	cases := []clitest.Case{
+		{
+			Name:   "list",
+			Args:   []string{"project", "list"},
+			Stdout: []string{"project-1", "example/web", "https://github.com/example/web"},
+		},
+		{
+			Name:   "list json",
+			Args:   []string{"--output", "json", "project", "list"},
+			Stdout: []string{`"id": "project-1"`},
+		},
+		{
+			Name:   "list columns",
+			Args:   []string{"project", "list", "--columns", "Name,ID", "--no-headers"},
+			Stdout: []string{"example/web", "project-1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if strings.Contains(res.Stdout, "github.com") || strings.Contains(res.Stdout, "NAME") {
+					t.Errorf("unselected column or header printed:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name:   "list unknown column",
+			Args:   []string{"project", "list", "--columns", "Score"},
+			Stderr: []string{`unknown column "Score"`},
+			Fail:   true,
+		},
+		{
+			Name:   "list without matches",
+			Args:   []string{"project", "list", "--search", "nothing"},
+			Stdout: []string{"No projects found"},
+		},
+		{
+			Name:   "get",
+			Args:   []string{"project", "get", codeclaritytest.ProjectID},
+			Stdout: []string{"name: example/web", "downloaded: true"},
+		},
+		{
+			Name:   "get unknown project",
+			Args:   []string{"project", "get", "project-404"},
+			Stderr: []string{"Failed to get project", "EntityNotFound"},
+		},
+		{
+			Name:   "create",
+			Args:   []string{"project", "create", "--url", "https://github.com/example/api", "--integration", "integration-1"},
+			Stdout: []string{"Project imported: project-new-1"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := len(env.Server.Projects()); n != 2 {
+					t.Errorf("server has %d projects, want 2", n)
+				}
+			},
+		},
+		{
+			Name: "create not retried on server error",
+			Args: []string{"project", "create", "--url", "https://github.com/example/api", "--integration", "integration-1"},
+			Setup: clitest.Inject(codeclaritytest.Fault{
+				Method: http.MethodPost,
+				Path:   projectPath,
+				Times:  1,
+				Status: http.StatusBadGateway,
+			}),
+			Stderr: []string{"BadGateway"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := env.Count(http.MethodPost, projectPath); n != 1 {
+					t.Errorf("server received %d imports, want 1", n)
+				}
+			},
+		},
+		{
+			Name:   "update",
+			Args:   []string{"project", "update", codeclaritytest.ProjectID, "--name", "example/site"},
+			Stdout: []string{"Project updated: project-1", "name: example/site"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if name := env.Server.Projects()[0].Name; name != "example/site" {
+					t.Errorf("project name = %q, want example/site", name)
+				}
+			},
+		},
+		{
+			Name:   "delete",
+			Args:   []string{"project", "delete", codeclaritytest.ProjectID, "--yes"},
+			Stdout: []string{"Project deleted: example/web (project-1)"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := len(env.Server.Projects()); n != 0 {
+					t.Errorf("server has %d projects, want 0", n)
+				}
+			},
+		},
+		{
+			Name:   "refresh",
+			Args:   []string{"project", "refresh", codeclaritytest.ProjectID, "--interval", "10ms"},
+			Stdout: []string{"Download state: downloading", "Download state: downloaded", "Repository downloaded"},
+		},
+		{
+			Name: "refresh with a stale first poll",
+			Args: []string{"project", "refresh", codeclaritytest.ProjectID, "--interval", "10ms"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.Server.StalePolls = 1
+			},
+			Stdout: []string{"Download state: downloaded", "Download state: downloading", "Repository downloaded"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if strings.Index(res.Stdout, "Repository downloaded") < strings.Index(res.Stdout, "downloading") {
+					t.Errorf("refresh reported the state from before the refresh:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name: "refresh timing out before the download starts",
+			Args: []string{"project", "refresh", codeclaritytest.ProjectID, "--interval", "10ms", "--timeout", "50ms"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.Server.StalePolls = 1000
+			},
+			Stdout: []string{"Download state: downloaded", "The download did not start within 50ms"},
+		},
+		{
+			Name:   "refresh without waiting",
+			Args:   []string{"project", "refresh", codeclaritytest.ProjectID, "--no-wait"},
+			Stdout: []string{"Download of example/web requested (was downloaded)"},
+		},
+		{
+			Name: "import",
+			Args: []string{"project", "import", "--file", "repos.yaml"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "repos.yaml", manifest)
+			},
+			Stdout: []string{"Imported https://github.com/example/api", "Imported 1, skipped 1 already imported, 0 failed"},
+		},
+		{
+			Name: "import dry run",
+			Args: []string{"project", "import", "--file", "repos.yaml", "--dry-run"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "repos.yaml", manifest)
+			},
+			Stdout: []string{"https://github.com/example/api"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := env.Count(http.MethodPost, projectPath); n != 0 {
+					t.Errorf("dry run sent %d imports", n)
+				}
+			},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		[]string{"project", "get", codeclaritytest.ProjectID},
+		projectPath+"/"+codeclaritytest.ProjectID,
+		"Failed to get project",
+	)...)
+	cases = append(cases, clitest.FaultCases(
+		[]string{"project", "list"},
+		projectPath,
+		"Failed to list projects",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
@@ -0,0 +1,151 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	refreshNoWait   bool
+	refreshInterval time.Duration
+	refreshTimeout  time.Duration
+)
+
+// Download states of a project
+const (
+	stateDownloaded  = "downloaded"
+	stateDownloading = "downloading"
+	stateInvalid     = "invalid"
+)
+
+var refreshCmd = &cobra.Command{
+	Use:   "refresh <project-id>",
+	Short: "Download a project's repository again",
+	Long: `Ask the server to clone a project's repository again, for example after
+fixing access to a repository that was flagged as invalid.
+
+The download state is followed until the repository is downloaded or
+reported invalid, printing every change, unless --no-wait is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		// A stale downloaded state served before the refresh is picked up
+		// would stay in the cache, hiding the download from the watch
+		if !refreshNoWait {
+			api.DisableCache()
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		ctx := cmd.Context()
+		project, err := client.Projects.Get(ctx, orgID, projectID)
+		if err != nil {
+			output.Error("Failed to get project: %v", err)
+			return nil
+		}
+		before := downloadState(project)
+
+		if err := client.Projects.Refresh(ctx, orgID, projectID); err != nil {
+			output.Error("Failed to refresh project: %v", err)
+			return nil
+		}
+		output.Success("Download of %s requested (was %s)", project.Name, before)
+
+		if refreshNoWait {
+			return nil
+		}
+		return watchDownload(ctx, client, orgID, projectID, before)
+	},
+}
+
+// watchDownload polls a project until its repository is downloaded or
+// invalid, printing each state change. The server may still report the
+// state from before the refresh, so a final state only counts once the
+// project was seen downloading or in a state other than before.
+func watchDownload(ctx context.Context, client *codeclarity.Client, orgID, projectID, before string) error {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	lastState := ""
+	started := false
+	timedOut := func() error {
+		if !started {
+			output.Warning("The download did not start within %s", refreshTimeout)
+			return nil
+		}
+		output.Warning("Still %s after %s", lastState, refreshTimeout)
+		return nil
+	}
+	for {
+		project, err := client.Projects.Get(ctx, orgID, projectID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return timedOut()
+			}
+			output.Error("Failed to get project: %v", err)
+			return nil
+		}
+
+		state := downloadState(project)
+		if state != before || state == stateDownloading {
+			started = true
+		}
+		if state != lastState {
+			fmt.Printf("[%s] Download state: %s\n", time.Now().Format("15:04:05"), output.StatusColor(state))
+			lastState = state
+		}
+
+		switch {
+		case !started:
+		case state == stateDownloaded:
+			output.Success("Repository downloaded")
+			return nil
+		case state == stateInvalid:
+			output.Error("Repository could not be downloaded: check the URL and the integration's access")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return timedOut()
+		case <-ticker.C:
+		}
+	}
+}
+
+// downloadState summarizes the Downloaded and Invalid flags of a project
+func downloadState(p *codeclarity.Project) string {
+	switch {
+	case p.Invalid:
+		return stateInvalid
+	case p.Downloaded:
+		return stateDownloaded
+	}
+	return stateDownloading
+}
+
+func init() {
+	refreshCmd.Flags().BoolVar(&refreshNoWait, "no-wait", false, "Return once the download is requested")
+	refreshCmd.Flags().DurationVar(&refreshInterval, "interval", 5*time.Second, "How often to check the download state")
+	refreshCmd.Flags().DurationVar(&refreshTimeout, "timeout", 10*time.Minute, "How long to wait for the download")
+}
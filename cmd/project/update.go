@@ -0,0 +1,81 @@
+package project
+
+import (
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateName          string
+	updateDescription   string
+	updateDefaultBranch string
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update <project-id>",
+	Short: "Update a project",
+	Long: `Update the name, description or default branch of a project. Only the
+flags given are changed.
+
+Example:
+  codeclarity project update <project-id> --default-branch develop`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		var req codeclarity.ProjectUpdateRequest
+		if cmd.Flags().Changed("name") {
+			req.Name = &updateName
+		}
+		if cmd.Flags().Changed("description") {
+			req.Description = &updateDescription
+		}
+		if cmd.Flags().Changed("default-branch") {
+			req.DefaultBranch = &updateDefaultBranch
+		}
+		if req.Name == nil && req.Description == nil && req.DefaultBranch == nil {
+			output.Error("Nothing to update. Use --name, --description or --default-branch")
+			return nil
+		}
+		if req.Name != nil && *req.Name == "" {
+			output.Error("Project name cannot be empty")
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		project, err := client.Projects.Update(cmd.Context(), orgID, projectID, req)
+		if err != nil {
+			output.Error("Failed to update project: %v", err)
+			return nil
+		}
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		if output.IsStructuredFormat(format) {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(project)
+		}
+
+		output.Success("Project updated: %s", project.ID)
+		formatter := output.NewFormatter("yaml")
+		return formatter.Print(project)
+	},
+}
+
+func init() {
+	updateCmd.Flags().StringVar(&updateName, "name", "", "New project name")
+	updateCmd.Flags().StringVar(&updateDescription, "description", "", "New project description")
+	updateCmd.Flags().StringVar(&updateDefaultBranch, "default-branch", "", "New default branch")
+}
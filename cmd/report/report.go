@@ -0,0 +1,141 @@
+package report
+
+import (
+	"bytes"
+	"os"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/config"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/report"
+	"codeclarity.io/internal/suppress"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat    string
+	reportTemplate  string
+	reportOut       string
+	reportWorkspace string
+	reportTop       int
+	reportSuppress  suppress.Flags
+)
+
+// ReportCmd represents the report command
+var ReportCmd = &cobra.Command{
+	Use:   "report <project-id> <analysis-id>",
+	Short: "Generate a shareable analysis report",
+	Long: `Generate a self-contained Markdown or HTML report of an analysis, including
+severity charts, the riskiest packages, license breakdown and vulnerability details.
+
+The layout can be customized with a Go template (--template). Templates receive
+the report data (.Project, .Analysis, .VulnStats, .SBOMStats, .LicenseStats,
+.Vulnerabilities, .TopPackages, .Licenses, .Suppressed, .GeneratedAt) and the
+helpers date, truncate, lower, upper, join, percent, mdEscape, severityColor
+and severityChart.
+
+Example:
+  codeclarity report <project-id> <analysis-id> --format html --out report.html`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+		analysisID := args[1]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		format, err := report.ParseFormat(reportFormat)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+
+		suppressions, err := reportSuppress.Load()
+		if err != nil {
+			output.Error("Failed to load suppressions: %v", err)
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		analysis, err := client.Analyses.Get(cmd.Context(), orgID, projectID, analysisID)
+		if err != nil {
+			output.Error("Failed to get analysis: %v", err)
+			return nil
+		}
+
+		vulns, err := client.Results.AllVulnerabilities(cmd.Context(), orgID, projectID, analysisID, reportWorkspace)
+		if err != nil {
+			output.Error("Failed to get vulnerabilities: %v", err)
+			return nil
+		}
+
+		// Missing statistics are rendered as unavailable rather than failing the report
+		project, _ := client.Projects.Get(cmd.Context(), orgID, projectID)
+		vulnStats, err := client.Results.VulnerabilityStats(cmd.Context(), orgID, projectID, analysisID, reportWorkspace)
+		if err != nil {
+			output.Notice("Could not retrieve vulnerability stats: %v", err)
+		}
+		sbomStats, err := client.Results.SBOMStats(cmd.Context(), orgID, projectID, analysisID, reportWorkspace)
+		if err != nil {
+			output.Notice("Could not retrieve SBOM stats: %v", err)
+		}
+		licenseStats, err := client.Results.LicenseStats(cmd.Context(), orgID, projectID, analysisID, reportWorkspace)
+		if err != nil {
+			output.Notice("Could not retrieve license stats: %v", err)
+		}
+
+		kept, suppressed := suppressions.Filter(vulns)
+		suppress.Report(suppressions, true)
+		vulnStats = suppress.Count(suppressed).Subtract(vulnStats)
+
+		data := report.NewData(project, analysis, vulnStats, sbomStats, licenseStats, kept, reportTop)
+		data.Suppressed = len(suppressed)
+
+		// Render fully before writing so a failing template leaves no partial file
+		var buf bytes.Buffer
+		if err := report.Render(&buf, format, data, reportTemplate); err != nil {
+			output.Error("Failed to render report: %v", err)
+			return nil
+		}
+
+		if reportOut == "" {
+			_, err = os.Stdout.Write(buf.Bytes())
+			return err
+		}
+		if err := os.WriteFile(reportOut, buf.Bytes(), 0644); err != nil {
+			output.Error("Failed to write %s: %v", reportOut, err)
+			return nil
+		}
+		output.Success("Report written to %s", reportOut)
+		return nil
+	},
+}
+
+func init() {
+	ReportCmd.Flags().StringVar(&reportFormat, "format", "markdown", "Report format: markdown, html")
+	ReportCmd.Flags().StringVar(&reportTemplate, "template", "", "Custom Go template file")
+	ReportCmd.Flags().StringVar(&reportOut, "out", "", "Write the report to a file instead of stdout")
+	ReportCmd.Flags().StringVar(&reportWorkspace, "workspace", "", "Filter by workspace")
+	ReportCmd.Flags().IntVar(&reportTop, "top", 10, "Number of risky packages to list")
+	reportSuppress.Register(ReportCmd.Flags())
+}
+
+// getOrgID returns the organization ID from flag or config
+func getOrgID(cmd *cobra.Command) string {
+	if orgID := cmd.Root().Flag("org").Value.String(); orgID != "" {
+		return orgID
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.DefaultOrgID
+}
@@ -0,0 +1,106 @@
+package report_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const resultsPath = "/org/" + codeclaritytest.OrgID + "/projects/" + codeclaritytest.ProjectID + "/analysis/" + codeclaritytest.AnalysisID
+
+func TestReportCommand(t *testing.T) {
+	report := []string{"report", codeclaritytest.ProjectID, codeclaritytest.AnalysisID}
+
+	cases := []clitest.Case{
+		{
+			Name:   "markdown",
+			Args:   report,
+			Stdout: []string{"# Security Report: example/web", "Critical █", "| Critical | 1 |", "| minimist | 1.2.5 |", "| MIT | 100 |"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if strings.Contains(res.Stdout, "data:image") {
+					t.Errorf("markdown report embeds an image:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name:   "html to file",
+			Args:   append(report, "--format", "html", "--out", "report.html"),
+			Stdout: []string{"Report written to report.html"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				data, err := os.ReadFile(env.Path("report.html"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(data), "<html") || !strings.Contains(string(data), "CVE-2021-44906") {
+					t.Errorf("report.html is not an HTML report of the analysis")
+				}
+			},
+		},
+		{
+			Name: "custom template",
+			Args: append(report, "--template", "report.tmpl"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "report.tmpl", "{{.Project.Name}}: {{len .Vulnerabilities}} findings, {{.VulnStats.Critical}} critical\n")
+			},
+			Stdout: []string{"example/web: 3 findings, 1 critical"},
+		},
+		{
+			Name: "suppressed findings",
+			Args: report,
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, ".codeclarity-ignore.yaml", "suppressions:\n  - id: CVE-2021-44906\n    justification: Build scripts only\n")
+			},
+			Stdout: []string{"| Critical | 0 |", "1 finding(s) suppressed"},
+		},
+		{
+			Name: "expired and unused suppressions",
+			Args: report,
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, ".codeclarity-ignore.yaml", `suppressions:
+  - id: CVE-2021-44906
+    justification: Build scripts only
+    expires: 2020-01-01
+  - id: CVE-1999-0001
+    justification: Not reachable
+`)
+			},
+			Stdout: []string{"| Critical | 1 |"},
+			Stderr: []string{"CVE-2021-44906 in .codeclarity-ignore.yaml expired on 2020-01-01", "CVE-1999-0001 in .codeclarity-ignore.yaml no longer matches"},
+		},
+		{
+			Name: "failing template writes no file",
+			Args: append(report, "--template", "report.tmpl", "--out", "report.md"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "report.tmpl", "{{.Project.Name}} {{.Missing}}\n")
+			},
+			Stderr: []string{"Failed to render report"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if _, err := os.Stat(env.Path("report.md")); !os.IsNotExist(err) {
+					t.Errorf("report.md was written despite the template error")
+				}
+			},
+		},
+		{
+			Name:   "invalid format",
+			Args:   append(report, "--format", "pdf"),
+			Stderr: []string{"pdf"},
+		},
+		{
+			Name:   "missing statistics",
+			Args:   report,
+			Setup:  clitest.Inject(codeclaritytest.Unauthorized(resultsPath + "/sbom/stats")),
+			Stdout: []string{"# Security Report: example/web"},
+			Stderr: []string{"Could not retrieve SBOM stats"},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(
+		report,
+		resultsPath+"/vulnerabilities",
+		"Failed to get vulnerabilities",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
@@ -0,0 +1,239 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/attest"
+	"codeclarity.io/internal/bundle"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	attestKey       string
+	attestOut       string
+	attestBundle    string
+	attestWorkspace string
+	verifyKey       string
+	verifyBundle    string
+)
+
+var attestCmd = &cobra.Command{
+	Use:   "attest [<project-id> <analysis-id>]",
+	Short: "Produce a signed attestation of analysis results",
+	Long: `Produce an in-toto attestation over the results of a finished analysis,
+wrapped in a DSSE envelope signed with a local ed25519 or ECDSA key.
+
+The predicate (type ` + attest.PredicateType + `)
+records the analysis ID, commit hash, analyzer, vulnerability counts and the
+SHA-256 digests of the vulnerability list and SBOM. The subject is the
+repository at the analyzed commit.
+
+Results are read from the server, from a bundle written by 'result export'
+with --bundle, or from the local store with --offline:
+  openssl genpkey -algorithm ed25519 -out attest-key.pem
+  openssl pkey -in attest-key.pem -pubout -out attest-key.pub
+  codeclarity result attest <project-id> <analysis-id> --key attest-key.pem --out result.intoto.json
+  codeclarity result verify result.intoto.json --key attest-key.pub
+
+The envelope is written to stdout unless --out is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if attestBundle != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if attestKey == "" {
+			output.Error("A signing key is required. Use --key <private-key.pem>")
+			return nil
+		}
+		key, err := attest.LoadPrivateKey(attestKey)
+		if err != nil {
+			output.Error("Failed to load signing key: %v", err)
+			return nil
+		}
+
+		var projectID, analysisID string
+		if len(args) == 2 {
+			projectID, analysisID = args[0], args[1]
+		}
+		b, analyzer, err := loadResults(cmd, getOrgID(cmd), projectID, analysisID, attestWorkspace, attestBundle)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+		if !b.Analysis.Status.Succeeded() {
+			output.Error("Analysis %s is %s: only finished analyses can be attested", b.Analysis.ID, b.Analysis.Status)
+			return nil
+		}
+
+		statement, err := attest.NewStatement(b, analyzer, api.UserAgent)
+		if err != nil {
+			output.Error("Cannot attest analysis %s: %v", b.Analysis.ID, err)
+			return nil
+		}
+		payload, err := json.Marshal(statement)
+		if err != nil {
+			return err
+		}
+		envelope, err := attest.Sign(attest.PayloadType, payload, key)
+		if err != nil {
+			output.Error("Failed to sign attestation: %v", err)
+			return nil
+		}
+
+		data, err := json.MarshalIndent(envelope, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if attestOut == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(attestOut, data, 0644); err != nil {
+			output.Error("Failed to write attestation: %v", err)
+			return nil
+		}
+		output.Success("Attestation for analysis %s signed with key %s written to %s",
+			b.Analysis.ID, envelope.Signatures[0].KeyID[:16], attestOut)
+		return nil
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <attestation>",
+	Short: "Verify a signed attestation of analysis results",
+	Long: `Verify the signature of an attestation produced by 'result attest' and
+check its digests and vulnerability counts against the analysis results.
+
+Results are read from the server, from a bundle with --bundle, or from the
+local store with --offline. The command exits with an error when the
+signature or any digest does not match.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifyKey == "" {
+			return fmt.Errorf("a public key is required: use --key <public-key.pem>")
+		}
+		pub, err := attest.LoadPublicKey(verifyKey)
+		if err != nil {
+			return fmt.Errorf("failed to load public key: %w", err)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		var envelope attest.Envelope
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return fmt.Errorf("invalid attestation %s: %w", args[0], err)
+		}
+		if envelope.PayloadType != attest.PayloadType {
+			return fmt.Errorf("unsupported payload type %q", envelope.PayloadType)
+		}
+		payload, err := envelope.Verify(pub)
+		if err != nil {
+			return fmt.Errorf("signature verification failed: %w", err)
+		}
+		statement, err := attest.ParseStatement(payload)
+		if err != nil {
+			return err
+		}
+		output.Success("Signature verified")
+
+		p := statement.Predicate
+		orgID := p.Analysis.OrganizationID
+		if orgID == "" {
+			orgID = getOrgID(cmd)
+		}
+		b, _, err := loadResults(cmd, orgID, p.Analysis.ProjectID, p.Analysis.ID, p.Analysis.Workspace, verifyBundle)
+		if err != nil {
+			return err
+		}
+		problems, err := p.Check(b)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				output.Error("%s", problem)
+			}
+			return fmt.Errorf("attestation does not match the results of analysis %s", p.Analysis.ID)
+		}
+
+		output.Success("Digests match the results of analysis %s", p.Analysis.ID)
+		fmt.Printf("  Commit:          %s\n", p.Analysis.CommitHash)
+		fmt.Printf("  Analyzer:        %s\n", p.Analyzer.ID)
+		fmt.Printf("  Vulnerabilities: %d (%d critical, %d high, %d medium, %d low)\n",
+			p.Vulnerabilities.Total, p.Vulnerabilities.Critical, p.Vulnerabilities.High, p.Vulnerabilities.Medium, p.Vulnerabilities.Low)
+		fmt.Printf("  Produced:        %s\n", p.ProducedAt.Local().Format("2006-01-02 15:04:05"))
+		return nil
+	},
+}
+
+// loadResults reads the results of an analysis from a bundle file, the
+// local store with --offline, or the server. The analyzer is only known
+// when reading from the server.
+func loadResults(cmd *cobra.Command, orgID, projectID, analysisID, workspace, bundlePath string) (*bundle.Bundle, *codeclarity.Analyzer, error) {
+	if bundlePath != "" {
+		b, err := openBundle(bundlePath)
+		return b, nil, err
+	}
+
+	if orgID == "" {
+		return nil, nil, fmt.Errorf("organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+	}
+
+	if api.Offline() {
+		snap, err := loadOfflineAnalysis(orgID, projectID, analysisID, workspace)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &bundle.Bundle{
+			Manifest: bundle.Manifest{
+				OrgID:      orgID,
+				ProjectID:  projectID,
+				AnalysisID: analysisID,
+				Workspace:  snap.Workspace,
+			},
+			Analysis:           snap.Analysis,
+			VulnerabilityStats: snap.VulnerabilityStats,
+			SBOMStats:          snap.SBOMStats,
+			LicenseStats:       snap.LicenseStats,
+			Vulnerabilities:    snap.Vulnerabilities,
+			SBOM:               snap.SBOM,
+			Licenses:           snap.Licenses,
+		}, nil, nil
+	}
+
+	client, err := api.NewAuthenticatedClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("authentication required: %w", err)
+	}
+	b, err := fetchBundle(cmd.Context(), client, orgID, projectID, analysisID, workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	analyzer, err := client.Analyzers.Get(cmd.Context(), orgID, b.Analysis.AnalyzerID)
+	if err != nil {
+		output.Notice("Analyzer details not included: %v", err)
+		analyzer = nil
+	}
+	return b, analyzer, nil
+}
+
+func init() {
+	attestCmd.Flags().StringVar(&attestKey, "key", "", "PEM file with the ed25519 or ECDSA private key to sign with")
+	attestCmd.Flags().StringVar(&attestOut, "out", "", "File to write the attestation to (default stdout)")
+	attestCmd.Flags().StringVar(&attestBundle, "bundle", "", "Attest the results in a bundle written by 'result export'")
+	attestCmd.Flags().StringVar(&attestWorkspace, "workspace", "", "Workspace to attest results for")
+
+	verifyCmd.Flags().StringVar(&verifyKey, "key", "", "PEM file with the public key to verify with")
+	verifyCmd.Flags().StringVar(&verifyBundle, "bundle", "", "Check the attestation against a bundle instead of the server")
+}
@@ -0,0 +1,364 @@
+package result
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/bundle"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportOut       string
+	exportWorkspace string
+	showBundle      string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <project-id> <analysis-id>",
+	Short: "Export analysis results to a bundle",
+	Long: `Package an analysis and its results into a gzip-compressed tar bundle for
+auditors or archiving.
+
+The bundle holds the analysis record, the project, the vulnerability, SBOM
+and license statistics, the full vulnerability list, the SBOM dependencies
+and the licenses with the dependencies using them, together with a
+manifest.json listing the SHA-256 checksum of every file. Findings are
+exported as reported by the server: suppression files and VEX documents are
+not applied.
+
+Bundles are read without server access:
+  codeclarity result export <project-id> <analysis-id> --out results.tar.gz
+  codeclarity result show --bundle results.tar.gz
+  codeclarity result import results.tar.gz`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+		analysisID := args[1]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		b, err := fetchBundle(cmd.Context(), client, orgID, projectID, analysisID, exportWorkspace)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+		if !b.Analysis.Status.Succeeded() {
+			output.Warning("Analysis %s is %s: its results may still change", analysisID, b.Analysis.Status)
+		}
+
+		out := exportOut
+		if out == "" {
+			out = "codeclarity-" + analysisID + ".tar.gz"
+		}
+		if err := writeBundle(out, b); err != nil {
+			output.Error("Failed to write bundle: %v", err)
+			return nil
+		}
+
+		output.Success("Exported analysis %s with %d vulnerabilities to %s", analysisID, len(b.Vulnerabilities), out)
+		return nil
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import a results bundle for offline use",
+	Long: `Verify a bundle written by 'codeclarity result export' and add it to the
+local store, so its results are served by commands run with --offline:
+  codeclarity result import results.tar.gz
+  codeclarity --offline result summary <project-id> <analysis-id>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := openBundle(args[0])
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+
+		m := b.Manifest
+		orgID := m.OrgID
+		if orgID == "" {
+			orgID = getOrgID(cmd)
+		}
+		projectID := m.ProjectID
+		if projectID == "" {
+			projectID = b.Analysis.ProjectID
+		}
+
+		st, err := store.Open()
+		if err != nil {
+			return err
+		}
+
+		if err := st.SaveAnalysis(orgID, projectID, &store.AnalysisSnapshot{
+			Analysis:           b.Analysis,
+			Workspace:          m.Workspace,
+			VulnerabilityStats: b.VulnerabilityStats,
+			SBOMStats:          b.SBOMStats,
+			LicenseStats:       b.LicenseStats,
+			Vulnerabilities:    b.Vulnerabilities,
+			SBOM:               b.SBOM,
+			Licenses:           b.Licenses,
+			SyncedAt:           m.CreatedAt,
+		}); err != nil {
+			output.Error("Failed to import bundle: %v", err)
+			return nil
+		}
+
+		// Add the analysis to the stored project, keeping analyses synced earlier
+		snap, err := st.Project(orgID, projectID)
+		if err != nil {
+			snap = &store.ProjectSnapshot{
+				Project:  codeclarity.Project{ID: projectID},
+				SyncedAt: m.CreatedAt,
+			}
+		}
+		if b.Project != nil && snap.Project.Name == "" {
+			snap.Project = *b.Project
+		}
+		replaced := false
+		for i, a := range snap.Analyses {
+			if a.ID == b.Analysis.ID {
+				snap.Analyses[i] = b.Analysis
+				replaced = true
+			}
+		}
+		if !replaced {
+			snap.Analyses = append(snap.Analyses, b.Analysis)
+		}
+		if err := st.SaveProject(orgID, snap); err != nil {
+			output.Error("Failed to import bundle: %v", err)
+			return nil
+		}
+
+		output.Success("Imported analysis %s of project %s (organization %s)", b.Analysis.ID, projectID, orgID)
+		fmt.Printf("View it with: codeclarity --offline --org %s result summary %s %s\n", orgID, projectID, b.Analysis.ID)
+		return nil
+	},
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show --bundle <file>",
+	Short: "Show the results in a bundle",
+	Long: `Verify a bundle written by 'codeclarity result export' and print its
+analysis, statistics and vulnerabilities without contacting the server.
+
+Structured output formats print the whole bundle, including its manifest.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if showBundle == "" {
+			output.Error("A bundle is required. Use --bundle <file>")
+			return nil
+		}
+
+		b, err := openBundle(showBundle)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		if output.IsStructuredFormat(format) {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(b)
+		}
+
+		m := b.Manifest
+		a := b.Analysis
+		fmt.Println(output.Bold("Bundle"))
+		fmt.Printf("  File:       %s\n", showBundle)
+		fmt.Printf("  Exported:   %s by %s\n", m.CreatedAt.Local().Format("2006-01-02 15:04:05"), m.Generator)
+		fmt.Printf("  Checksums:  %d files verified\n", len(m.Files))
+		fmt.Println()
+
+		fmt.Println(output.Bold("Analysis"))
+		if b.Project != nil {
+			fmt.Printf("  Project:    %s (%s)\n", b.Project.Name, b.Project.ID)
+		} else {
+			fmt.Printf("  Project:    %s\n", m.ProjectID)
+		}
+		fmt.Printf("  ID:         %s\n", a.ID)
+		fmt.Printf("  Status:     %s\n", output.StatusColor(string(a.Status)))
+		fmt.Printf("  Branch:     %s\n", a.Branch)
+		if a.CommitHash != "" {
+			fmt.Printf("  Commit:     %s\n", a.CommitHash)
+		}
+		fmt.Printf("  Workspace:  %s\n", m.Workspace)
+		fmt.Printf("  Created:    %s\n", a.CreatedOn.Local().Format("2006-01-02 15:04:05"))
+		fmt.Println()
+
+		if s := b.VulnerabilityStats; s != nil {
+			fmt.Println(output.Bold("Vulnerabilities:"))
+			fmt.Printf("  Total:    %d\n", s.Total)
+			fmt.Printf("  Critical: %s\n", output.ColorBySeverity("critical", fmt.Sprintf("%d", s.Critical)))
+			fmt.Printf("  High:     %s\n", output.ColorBySeverity("high", fmt.Sprintf("%d", s.High)))
+			fmt.Printf("  Medium:   %s\n", output.ColorBySeverity("medium", fmt.Sprintf("%d", s.Medium)))
+			fmt.Printf("  Low:      %s\n", output.ColorBySeverity("low", fmt.Sprintf("%d", s.Low)))
+			fmt.Println()
+		}
+		if s := b.SBOMStats; s != nil {
+			fmt.Println(output.Bold("Dependencies:"))
+			fmt.Printf("  Total:      %d\n", s.TotalDependencies)
+			fmt.Printf("  Direct:     %d\n", s.DirectDependencies)
+			fmt.Printf("  Transitive: %d\n", s.TransitiveDependencies)
+			if b.SBOM != nil {
+				fmt.Printf("  Listed:     %d\n", len(b.SBOM))
+			}
+			fmt.Println()
+		}
+		if s := b.LicenseStats; s != nil {
+			fmt.Println(output.Bold("Licenses:"))
+			fmt.Printf("  Total:      %d\n", s.Total)
+			if len(s.ByLicense) > 0 {
+				fmt.Printf("  Licenses:   %s\n", formatCounts(s.ByLicense))
+			}
+			if b.Licenses != nil {
+				fmt.Printf("  Listed:     %d\n", len(b.Licenses))
+			}
+			fmt.Println()
+		}
+
+		if len(b.Vulnerabilities) == 0 {
+			output.Success("No vulnerabilities found")
+			return nil
+		}
+		fmt.Printf("Found %d vulnerabilities\n\n", len(b.Vulnerabilities))
+		return printVulnerabilityTable(b.Vulnerabilities)
+	},
+}
+
+// fetchBundle downloads an analysis and its results. Parts the server cannot
+// provide are left out with a notice.
+func fetchBundle(ctx context.Context, client *codeclarity.Client, orgID, projectID, analysisID, workspace string) (*bundle.Bundle, error) {
+	analysis, err := client.Analyses.Get(ctx, orgID, projectID, analysisID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis: %w", err)
+	}
+	vulns, err := client.Results.AllVulnerabilities(ctx, orgID, projectID, analysisID, workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vulnerabilities: %w", err)
+	}
+
+	b := &bundle.Bundle{
+		Manifest: bundle.Manifest{
+			Generator:  api.UserAgent,
+			OrgID:      orgID,
+			ProjectID:  projectID,
+			AnalysisID: analysisID,
+			Workspace:  store.NormalizeWorkspace(workspace),
+		},
+		Analysis:        *analysis,
+		Vulnerabilities: vulns,
+	}
+	if project, err := client.Projects.Get(ctx, orgID, projectID); err == nil {
+		b.Project = project
+	} else {
+		output.Notice("Project details not included: %v", err)
+	}
+	if stats, err := client.Results.VulnerabilityStats(ctx, orgID, projectID, analysisID, workspace); err == nil {
+		b.VulnerabilityStats = stats
+	} else {
+		output.Notice("Vulnerability stats not included: %v", err)
+	}
+	if stats, err := client.Results.SBOMStats(ctx, orgID, projectID, analysisID, workspace); err == nil {
+		b.SBOMStats = stats
+	} else {
+		output.Notice("SBOM stats not included: %v", err)
+	}
+	if stats, err := client.Results.LicenseStats(ctx, orgID, projectID, analysisID, workspace); err == nil {
+		b.LicenseStats = stats
+	} else {
+		output.Notice("License stats not included: %v", err)
+	}
+	if deps, err := client.Results.AllDependencies(ctx, orgID, projectID, analysisID, workspace); err == nil {
+		b.SBOM = deps
+	} else {
+		output.Notice("SBOM not included: %v", err)
+	}
+	if licenses, err := client.Results.AllLicenses(ctx, orgID, projectID, analysisID, workspace); err == nil {
+		b.Licenses = licenses
+	} else {
+		output.Notice("License list not included: %v", err)
+	}
+	return b, nil
+}
+
+// writeBundle writes b to path through a temporary file so an interrupted
+// export never leaves a partial bundle behind
+func writeBundle(path string, b *bundle.Bundle) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".codeclarity-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := bundle.Write(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// openBundle reads and verifies the bundle at path
+func openBundle(path string) (*bundle.Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := bundle.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// formatCounts renders counts as "name (n)", most frequent first
+func formatCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "Bundle file to write (default codeclarity-<analysis-id>.tar.gz)")
+	exportCmd.Flags().StringVar(&exportWorkspace, "workspace", "", "Workspace to export results for")
+
+	showCmd.Flags().StringVar(&showBundle, "bundle", "", "Bundle file to show")
+}
@@ -0,0 +1,21 @@
+package result
+
+import (
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+)
+
+// loadOfflineAnalysis reads synced results for --offline and tells the user
+// how old they are
+func loadOfflineAnalysis(orgID, projectID, analysisID, workspace string) (*store.AnalysisSnapshot, error) {
+	st, err := store.Open()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := st.Analysis(orgID, projectID, analysisID, workspace)
+	if err != nil {
+		return nil, err
+	}
+	output.Notice("%s", store.Describe(snap.SyncedAt))
+	return snap, nil
+}
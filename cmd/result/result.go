@@ -2,19 +2,43 @@ package result
 
 import (
 	"codeclarity.io/internal/config"
+	"codeclarity.io/internal/suppress"
 	"github.com/spf13/cobra"
 )
 
+// suppressFlags holds the suppression options shared by the result commands
+var suppressFlags suppress.Flags
+
 // ResultCmd represents the result command group
 var ResultCmd = &cobra.Command{
 	Use:   "result",
 	Short: "View analysis results",
-	Long:  `View vulnerability, SBOM, and license results from analyses.`,
+	Long: `View vulnerability, SBOM, and license results from analyses.
+
+Findings triaged as accepted risks can be listed in a repository-local
+.codeclarity-ignore.yaml file. Suppressed findings are excluded from results
+and counted separately:
+
+  suppressions:
+    - id: CVE-2023-12345
+      package: lodash        # optional scope
+      version: 4.17.20       # optional, requires package
+      justification: Only used at build time
+      expires: 2025-12-31    # optional, YYYY-MM-DD`,
 }
 
 func init() {
 	ResultCmd.AddCommand(summaryCmd)
 	ResultCmd.AddCommand(vulnerabilitiesCmd)
+	ResultCmd.AddCommand(vexCmd)
+	ResultCmd.AddCommand(exportCmd)
+	ResultCmd.AddCommand(importCmd)
+	ResultCmd.AddCommand(showCmd)
+	ResultCmd.AddCommand(attestCmd)
+	ResultCmd.AddCommand(verifyCmd)
+	ResultCmd.AddCommand(trendCmd)
+
+	suppressFlags.Register(ResultCmd.PersistentFlags())
 }
 
 // getOrgID returns the organization ID from flag or config
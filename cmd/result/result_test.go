@@ -0,0 +1,291 @@
+package result_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+	"codeclarity.io/pkg/codeclarity"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+const resultsPath = "/org/" + codeclaritytest.OrgID + "/projects/" + codeclaritytest.ProjectID + "/analysis/" + codeclaritytest.AnalysisID
+
+const ignoreFile = `suppressions:
+  - id: CVE-2021-44906
+    package: minimist
+    justification: Only used by build scripts
+`
+
+const vexDocument = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/1",
+  "author": "security@example.com",
+  "timestamp": "2025-01-02T00:00:00Z",
+  "version": 1,
+  "statements": [{
+    "vulnerability": {"name": "CVE-2022-24999"},
+    "products": [{"@id": "https://github.com/example/web", "subcomponents": [{"@id": "pkg:npm/qs@6.5.2"}]}],
+    "status": "not_affected",
+    "justification": "vulnerable_code_not_in_execute_path"
+  }]
+}
+`
+
+func writeIgnoreFile(t *testing.T, env *clitest.Env) {
+	env.WriteFile(t, ".codeclarity-ignore.yaml", ignoreFile)
+}
+
+// writeKeys writes an ed25519 key pair to key.pem and key.pub
+func writeKeys(t *testing.T, env *clitest.Env) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.WriteFile(t, "key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})))
+	env.WriteFile(t, "key.pub", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})))
+}
+
+func TestResultCommands(t *testing.T) {
+	ids := []string{codeclaritytest.ProjectID, codeclaritytest.AnalysisID}
+	summary := append([]string{"result", "summary"}, ids...)
+	vulns := append([]string{"result", "vulnerabilities"}, ids...)
+
+	cases := []clitest.Case{
+		{
+			Name:   "summary",
+			Args:   summary,
+			Stdout: []string{"Total:    3", "Critical: 1", "Total:      120"},
+		},
+		{
+			Name:   "summary with suppressions",
+			Args:   summary,
+			Setup:  writeIgnoreFile,
+			Stdout: []string{"Total:    2", "Critical: 0"},
+		},
+		{
+			Name:   "summary without suppressions",
+			Args:   append(summary, "--no-ignore"),
+			Setup:  writeIgnoreFile,
+			Stdout: []string{"Critical: 1"},
+		},
+		{
+			Name:   "vulnerabilities",
+			Args:   vulns,
+			Stdout: []string{"Found 3 vulnerabilities", "CVE-2021-44906", "minimist", "CVE-2017-16137"},
+		},
+		{
+			Name:   "vulnerabilities json",
+			Args:   append([]string{"--output", "json"}, vulns...),
+			Stdout: []string{`"server_total_entries": 3`, `"suppressed": 0`},
+		},
+		{
+			Name:   "vulnerabilities suppressed",
+			Args:   append([]string{"--output", "json"}, vulns...),
+			Setup:  writeIgnoreFile,
+			Stdout: []string{`"suppressed": 1`},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if strings.Contains(res.Stdout, "CVE-2021-44906") {
+					t.Errorf("suppressed vulnerability is listed:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name: "vulnerabilities filtered by vex",
+			Args: append(vulns, "--vex", "vex.json"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "vex.json", vexDocument)
+			},
+			Stdout: []string{"Found 2 vulnerabilities"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if strings.Contains(res.Stdout, "CVE-2022-24999") {
+					t.Errorf("not_affected vulnerability is listed:\n%s", res.Stdout)
+				}
+			},
+		},
+		{
+			Name: "vulnerabilities with vex about another product",
+			Args: append(vulns, "--vex", "vex.json", "--product", "pkg:generic/other"),
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "vex.json", vexDocument)
+			},
+			Stdout: []string{"Found 3 vulnerabilities", "CVE-2022-24999"},
+		},
+		{
+			Name:  "vulnerabilities ndjson",
+			Args:  append([]string{"--output", "ndjson", "--per-page", "2"}, vulns...),
+			Setup: writeIgnoreFile,
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				lines := strings.Split(strings.TrimSuffix(res.Stdout, "\n"), "\n")
+				if len(lines) != 2 {
+					t.Fatalf("got %d lines, want one per unsuppressed vulnerability:\n%s", len(lines), res.Stdout)
+				}
+				for _, line := range lines {
+					var v codeclarity.Vulnerability
+					if err := json.Unmarshal([]byte(line), &v); err != nil || v.ID == "" {
+						t.Errorf("line %q is not a vulnerability: %v", line, err)
+					}
+					if v.ID == "CVE-2021-44906" {
+						t.Errorf("suppressed vulnerability is listed: %s", line)
+					}
+				}
+				// Every page is fetched in turn
+				if n := env.Count("GET", resultsPath+"/vulnerabilities"); n != 2 {
+					t.Errorf("server received %d vulnerability requests, want 2", n)
+				}
+			},
+		},
+		{
+			Name:   "vulnerabilities junit",
+			Args:   append([]string{"--output", "junit"}, vulns...),
+			Stdout: []string{`<testsuites name="codeclarity.analysis-1" tests="3" failures="2">`, `name="minimist@1.2.5"`},
+		},
+		{
+			Name:   "vulnerabilities gitlab",
+			Args:   append([]string{"--output", "gitlab"}, vulns...),
+			Stdout: []string{`"type": "dependency_scanning"`, `"value": "CVE-2022-24999"`},
+		},
+		{
+			Name:   "vulnerabilities codeclimate",
+			Args:   append([]string{"--output", "codeclimate"}, vulns...),
+			Stdout: []string{`"check_name": "codeclarity/CVE-2021-44906"`, `"severity": "blocker"`},
+		},
+		{
+			Name:   "vulnerabilities github",
+			Args:   append([]string{"--output", "github"}, vulns...),
+			Stdout: []string{"::error file=package-lock.json", "::notice file=package-lock.json"},
+		},
+		{
+			Name:   "vulnerabilities invalid severity",
+			Args:   append(vulns, "--fail-severity", "severe"),
+			Stderr: []string{`Invalid severity "severe"`},
+		},
+		{
+			Name:   "trend",
+			Args:   []string{"result", "trend", codeclaritytest.ProjectID},
+			Stdout: []string{"analysis-1", "120"},
+		},
+		{
+			Name:   "trend json",
+			Args:   []string{"--output", "json", "result", "trend", codeclaritytest.ProjectID},
+			Stdout: []string{`"critical": 1`, `"suppressed": 0`},
+		},
+		{
+			Name:   "trend skips analyses without results",
+			Args:   []string{"result", "trend", codeclaritytest.ProjectID},
+			Setup:  clitest.Inject(codeclaritytest.ServerError(resultsPath+"/vulnerabilities/stats", http.StatusNotFound, 0)),
+			Stdout: []string{"No finished analyses with results found"},
+			Stderr: []string{"Skipped 1 analyses without results"},
+		},
+		{
+			Name:   "vex",
+			Args:   append([]string{"result", "vex"}, ids...),
+			Stdout: []string{`"author": "dev@example.com"`, "pkg:npm/minimist@1.2.5", `"status": "affected"`},
+		},
+		{
+			Name:   "vex with suppressions",
+			Args:   append([]string{"result", "vex"}, ids...),
+			Setup:  writeIgnoreFile,
+			Stdout: []string{`"status": "not_affected"`},
+		},
+		{
+			Name:   "vex to file",
+			Args:   append(append([]string{"result", "vex"}, ids...), "--out", "vex.json"),
+			Stdout: []string{"Wrote 3 statements to vex.json"},
+		},
+		{
+			Name:   "export, show and import",
+			Args:   append(append([]string{"result", "export"}, ids...), "--out", "bundle.tar.gz"),
+			Stdout: []string{"Exported analysis analysis-1 with 3 vulnerabilities to bundle.tar.gz"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Expect(t, "Checksums:  8 files verified", "result", "show", "--bundle", "bundle.tar.gz")
+				env.Expect(t, `"deps_using_license"`, "--output", "json", "result", "show", "--bundle", "bundle.tar.gz")
+				env.Expect(t, "Imported analysis analysis-1", "result", "import", "bundle.tar.gz")
+				env.Server.Close()
+				env.Expect(t, "Critical: 1", "--offline", "result", "summary", codeclaritytest.ProjectID, codeclaritytest.AnalysisID)
+			},
+		},
+		{
+			Name:   "export without sbom",
+			Args:   append(append([]string{"result", "export"}, ids...), "--out", "bundle.tar.gz"),
+			Setup:  clitest.Inject(codeclaritytest.Unauthorized(resultsPath + "/sbom")),
+			Stdout: []string{"Exported analysis analysis-1"},
+			Stderr: []string{"SBOM not included"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				// The fault also covers sbom/stats
+				env.Expect(t, "Checksums:  6 files verified", "result", "show", "--bundle", "bundle.tar.gz")
+			},
+		},
+		{
+			Name:   "attest and verify",
+			Args:   append(append([]string{"result", "attest"}, ids...), "--key", "key.pem", "--out", "attestation.json"),
+			Setup:  writeKeys,
+			Stdout: []string{"Attestation for analysis analysis-1 signed"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Expect(t, "Digests match the results of analysis analysis-1", "result", "verify", "attestation.json", "--key", "key.pub")
+
+				// Results changed after signing no longer match; finished analyses
+				// are cached for good, so the cache is bypassed
+				env.Server.SetVulnerabilities(codeclaritytest.AnalysisID, nil)
+				res = env.Run("--no-cache", "result", "verify", "attestation.json", "--key", "key.pub")
+				if res.Err == nil {
+					t.Errorf("verify accepted changed results:\n%s", res.Output())
+				}
+			},
+		},
+		{
+			Name:   "verify changed sbom",
+			Args:   append(append([]string{"result", "attest"}, ids...), "--key", "key.pem", "--out", "attestation.json"),
+			Setup:  writeKeys,
+			Stdout: []string{"Attestation for analysis analysis-1 signed"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				env.Server.SetSBOM(codeclaritytest.AnalysisID, []codeclarity.Dependency{{Name: "qs", Version: "6.5.3", Direct: true}})
+				res = env.Run("--no-cache", "result", "verify", "attestation.json", "--key", "key.pub")
+				if res.Err == nil || !strings.Contains(res.Stderr, "sbom digest") {
+					t.Errorf("verify accepted a changed SBOM:\n%s", res.Output())
+				}
+			},
+		},
+		{
+			Name:  "attest to stdout",
+			Args:  append(append([]string{"result", "attest"}, ids...), "--key", "key.pem"),
+			Setup: writeKeys,
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if !strings.Contains(res.Stdout, `"payloadType": "application/vnd.in-toto+json"`) {
+					t.Errorf("attest did not print a DSSE envelope:\n%s", res.Stdout)
+				}
+				if _, err := os.Stat(env.Path("attestation.json")); err == nil {
+					t.Errorf("attest wrote a file without --out")
+				}
+			},
+		},
+	}
+	cases = append(cases, clitest.FaultCases(vulns, resultsPath+"/vulnerabilities", "Failed to get vulnerabilities")...)
+	cases = append(cases, clitest.FaultCases(
+		[]string{"result", "trend", codeclaritytest.ProjectID},
+		"/org/"+codeclaritytest.OrgID+"/projects/"+codeclaritytest.ProjectID+"/analyses",
+		"Failed to list analyses",
+	)...)
+	cases = append(cases, clitest.FaultCases(
+		[]string{"result", "trend", codeclaritytest.ProjectID},
+		resultsPath+"/vulnerabilities/stats",
+		"Failed to get results of analysis analysis-1",
+	)...)
+
+	clitest.RunCases(t, cases)
+}
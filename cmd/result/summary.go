@@ -5,6 +5,9 @@ import (
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
@@ -26,20 +29,66 @@ dependencies, and licenses.`,
 			return nil
 		}
 
-		client, err := api.NewAuthenticatedClient()
+		suppressions, err := suppressFlags.Load()
 		if err != nil {
-			output.Error("Authentication required: %v", err)
+			output.Error("Failed to load suppressions: %v", err)
 			return nil
 		}
 
-		// Get vulnerability stats
-		vulnStats, vulnErr := client.GetVulnerabilityStats(orgID, projectID, analysisID, summaryWorkspace)
+		var (
+			vulnStats *codeclarity.VulnerabilityStats
+			sbomStats *codeclarity.SBOMStats
+			vulnErr   error
+			sbomErr   error
+			allVulns  func() ([]codeclarity.Vulnerability, error)
+		)
+		if api.Offline() {
+			snap, err := loadOfflineAnalysis(orgID, projectID, analysisID, summaryWorkspace)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			vulnStats, sbomStats = snap.VulnerabilityStats, snap.SBOMStats
+			if vulnStats == nil {
+				vulnErr = store.ErrNotSynced
+			}
+			if sbomStats == nil {
+				sbomErr = store.ErrNotSynced
+			}
+			allVulns = func() ([]codeclarity.Vulnerability, error) {
+				return snap.Vulnerabilities, nil
+			}
+		} else {
+			client, err := api.NewAuthenticatedClient()
+			if err != nil {
+				output.Error("Authentication required: %v", err)
+				return nil
+			}
+
+			vulnStats, vulnErr = client.Results.VulnerabilityStats(cmd.Context(), orgID, projectID, analysisID, summaryWorkspace)
+			sbomStats, sbomErr = client.Results.SBOMStats(cmd.Context(), orgID, projectID, analysisID, summaryWorkspace)
+			allVulns = func() ([]codeclarity.Vulnerability, error) {
+				return client.Results.AllVulnerabilities(cmd.Context(), orgID, projectID, analysisID, summaryWorkspace)
+			}
+		}
 
-		// Get SBOM stats
-		sbomStats, sbomErr := client.GetSBOMStats(orgID, projectID, analysisID, summaryWorkspace)
+		// Suppressed findings are counted separately, which needs the full list
+		var suppressed *suppress.Counts
+		if vulnErr == nil && !suppressions.Empty() {
+			vulns, err := allVulns()
+			if err != nil {
+				output.Notice("Could not apply suppressions: %v", err)
+			} else {
+				_, matched := suppressions.Filter(vulns)
+				counts := suppress.Count(matched)
+				suppressed = &counts
+				vulnStats = counts.Subtract(vulnStats)
+				suppress.Report(suppressions, true)
+			}
+		}
 
 		format, _ := cmd.Root().Flags().GetString("output")
-		if format == "json" || format == "yaml" {
+		if output.IsStructuredFormat(format) {
 			// Output as structured data with defaults
 			summary := map[string]interface{}{
 				"vulnerabilities": map[string]int{
@@ -61,6 +110,9 @@ dependencies, and licenses.`,
 			if sbomErr == nil && sbomStats != nil {
 				summary["dependencies"] = sbomStats
 			}
+			if suppressed != nil {
+				summary["suppressed"] = suppressed
+			}
 
 			formatter := output.NewFormatter(format)
 			return formatter.Print(summary)
@@ -76,10 +128,14 @@ dependencies, and licenses.`,
 			output.Warning("  Could not retrieve vulnerability stats: %v", vulnErr)
 		} else if vulnStats != nil {
 			fmt.Printf("  Total:    %d\n", vulnStats.Total)
-			fmt.Printf("  Critical: %s\n", output.SeverityColor(fmt.Sprintf("%d", vulnStats.Critical)))
-			fmt.Printf("  High:     %s\n", output.SeverityColor(fmt.Sprintf("%d high", vulnStats.High)))
-			fmt.Printf("  Medium:   %d\n", vulnStats.Medium)
-			fmt.Printf("  Low:      %d\n", vulnStats.Low)
+			fmt.Printf("  Critical: %s\n", output.ColorBySeverity("critical", fmt.Sprintf("%d", vulnStats.Critical)))
+			fmt.Printf("  High:     %s\n", output.ColorBySeverity("high", fmt.Sprintf("%d", vulnStats.High)))
+			fmt.Printf("  Medium:   %s\n", output.ColorBySeverity("medium", fmt.Sprintf("%d", vulnStats.Medium)))
+			fmt.Printf("  Low:      %s\n", output.ColorBySeverity("low", fmt.Sprintf("%d", vulnStats.Low)))
+			if suppressed != nil {
+				fmt.Printf("  %s\n", output.Dim(fmt.Sprintf("Suppressed: %d (%d critical, %d high, %d medium, %d low)",
+					suppressed.Total, suppressed.Critical, suppressed.High, suppressed.Medium, suppressed.Low)))
+			}
 		}
 		fmt.Println()
 
@@ -0,0 +1,274 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trendBranch    string
+	trendWorkspace string
+	trendLimit     int
+)
+
+// trendPoint is the result of one analysis in a trend
+type trendPoint struct {
+	AnalysisID      string    `json:"analysis_id"`
+	Date            time.Time `json:"date"`
+	Branch          string    `json:"branch"`
+	Commit          string    `json:"commit"`
+	Vulnerabilities int       `json:"vulnerabilities"`
+	Critical        int       `json:"critical"`
+	High            int       `json:"high"`
+	Medium          int       `json:"medium"`
+	Low             int       `json:"low"`
+	Suppressed      int       `json:"suppressed"`
+	Dependencies    int       `json:"dependencies"`
+	Direct          int       `json:"direct"`
+	Transitive      int       `json:"transitive"`
+}
+
+var trendCmd = &cobra.Command{
+	Use:   "trend <project-id>",
+	Short: "Show vulnerability trends across analyses",
+	Long: `Show how vulnerability and dependency counts evolved over a project's
+finished analyses, oldest first, followed by a sparkline per series.
+Vulnerabilities suppressed by the ignore file are left out of the counts and
+reported in their own column.
+
+Table, CSV and JSON output are supported:
+  codeclarity result trend <project-id> --branch main
+  codeclarity result trend <project-id> --limit 0 --output csv > trend.csv
+
+With --offline, analyses synced by 'codeclarity sync' are used.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		suppressions, err := suppressFlags.Load()
+		if err != nil {
+			output.Error("Failed to load suppressions: %v", err)
+			return nil
+		}
+
+		var (
+			analyses []codeclarity.Analysis
+			statsFor func(a codeclarity.Analysis) (*codeclarity.VulnerabilityStats, *codeclarity.SBOMStats, error)
+			vulnsFor func(a codeclarity.Analysis) ([]codeclarity.Vulnerability, error)
+		)
+		if api.Offline() {
+			st, err := store.Open()
+			if err != nil {
+				return err
+			}
+			snap, err := st.Project(orgID, projectID)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			output.Notice("%s", store.Describe(snap.SyncedAt))
+			analyses = snap.Analyses
+			statsFor = func(a codeclarity.Analysis) (*codeclarity.VulnerabilityStats, *codeclarity.SBOMStats, error) {
+				results, err := st.Analysis(orgID, projectID, a.ID, trendWorkspace)
+				if err != nil {
+					return nil, nil, err
+				}
+				return results.VulnerabilityStats, results.SBOMStats, nil
+			}
+			vulnsFor = func(a codeclarity.Analysis) ([]codeclarity.Vulnerability, error) {
+				results, err := st.Analysis(orgID, projectID, a.ID, trendWorkspace)
+				if err != nil {
+					return nil, err
+				}
+				return results.Vulnerabilities, nil
+			}
+		} else {
+			client, err := api.NewAuthenticatedClient()
+			if err != nil {
+				output.Error("Authentication required: %v", err)
+				return nil
+			}
+
+			ctx := cmd.Context()
+			analyses, err = client.Analyses.All(ctx, orgID, projectID)
+			if err != nil {
+				output.Error("Failed to list analyses: %v", err)
+				return nil
+			}
+			statsFor = func(a codeclarity.Analysis) (*codeclarity.VulnerabilityStats, *codeclarity.SBOMStats, error) {
+				vulnStats, err := client.Results.VulnerabilityStats(ctx, orgID, projectID, a.ID, trendWorkspace)
+				if err != nil {
+					return nil, nil, err
+				}
+				sbomStats, err := client.Results.SBOMStats(ctx, orgID, projectID, a.ID, trendWorkspace)
+				if err != nil {
+					return nil, nil, err
+				}
+				return vulnStats, sbomStats, nil
+			}
+			vulnsFor = func(a codeclarity.Analysis) ([]codeclarity.Vulnerability, error) {
+				return client.Results.AllVulnerabilities(ctx, orgID, projectID, a.ID, trendWorkspace)
+			}
+		}
+
+		// Keep the most recent finished analyses, oldest first
+		var runs []codeclarity.Analysis
+		for _, a := range analyses {
+			if a.Status.Succeeded() && (trendBranch == "" || a.Branch == trendBranch) {
+				runs = append(runs, a)
+			}
+		}
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[i].CreatedOn.Before(runs[j].CreatedOn)
+		})
+		if trendLimit > 0 && len(runs) > trendLimit {
+			runs = runs[len(runs)-trendLimit:]
+		}
+
+		points := []trendPoint{}
+		skipped := 0
+		for _, a := range runs {
+			vulnStats, sbomStats, err := statsFor(a)
+			if missingResults(err) || (err == nil && (vulnStats == nil || sbomStats == nil)) {
+				skipped++
+				continue
+			}
+			if err != nil {
+				output.Error("Failed to get results of analysis %s: %v", a.ID, err)
+				return nil
+			}
+			var suppressed suppress.Counts
+			if !suppressions.Empty() {
+				vulns, err := vulnsFor(a)
+				if missingResults(err) {
+					skipped++
+					continue
+				}
+				if err != nil {
+					output.Error("Failed to get vulnerabilities of analysis %s: %v", a.ID, err)
+					return nil
+				}
+				_, matched := suppressions.Filter(vulns)
+				suppressed = suppress.Count(matched)
+				vulnStats = suppressed.Subtract(vulnStats)
+			}
+			points = append(points, trendPoint{
+				AnalysisID:      a.ID,
+				Date:            a.CreatedOn,
+				Branch:          a.Branch,
+				Commit:          a.CommitHash,
+				Vulnerabilities: vulnStats.Total,
+				Critical:        vulnStats.Critical,
+				High:            vulnStats.High,
+				Medium:          vulnStats.Medium,
+				Low:             vulnStats.Low,
+				Suppressed:      suppressed.Total,
+				Dependencies:    sbomStats.TotalDependencies,
+				Direct:          sbomStats.DirectDependencies,
+				Transitive:      sbomStats.TransitiveDependencies,
+			})
+		}
+		if skipped > 0 {
+			output.Notice("Skipped %d analyses without results", skipped)
+		}
+		suppress.Report(suppressions, len(points) > 0)
+
+		format, _ := cmd.Root().Flags().GetString("output")
+		if output.IsStructuredFormat(format) {
+			formatter := output.NewFormatter(format)
+			return formatter.Print(points)
+		}
+
+		if len(points) == 0 {
+			output.Warning("No finished analyses with results found")
+			return nil
+		}
+
+		columns := []output.Column{
+			{Header: "Date"},
+			{Header: "Analysis"},
+			{Header: "Branch"},
+			{Header: "Commit", Wide: true},
+			{Header: "Total"},
+			{Header: "Critical"},
+			{Header: "High"},
+			{Header: "Medium"},
+			{Header: "Low"},
+			{Header: "Suppressed", Wide: suppressions.Empty()},
+			{Header: "Dependencies"},
+		}
+		var rows [][]string
+		for _, p := range points {
+			rows = append(rows, []string{
+				p.Date.Local().Format("2006-01-02 15:04"),
+				p.AnalysisID,
+				p.Branch,
+				p.Commit,
+				fmt.Sprintf("%d", p.Vulnerabilities),
+				fmt.Sprintf("%d", p.Critical),
+				fmt.Sprintf("%d", p.High),
+				fmt.Sprintf("%d", p.Medium),
+				fmt.Sprintf("%d", p.Low),
+				fmt.Sprintf("%d", p.Suppressed),
+				fmt.Sprintf("%d", p.Dependencies),
+			})
+		}
+		formatter := output.NewFormatter("table")
+		if err := formatter.PrintColumns(columns, rows); err != nil {
+			return err
+		}
+
+		if len(points) < 2 {
+			return nil
+		}
+		series := []struct {
+			name  string
+			value func(p trendPoint) int
+		}{
+			{"Total", func(p trendPoint) int { return p.Vulnerabilities }},
+			{"Critical", func(p trendPoint) int { return p.Critical }},
+			{"High", func(p trendPoint) int { return p.High }},
+			{"Medium", func(p trendPoint) int { return p.Medium }},
+			{"Low", func(p trendPoint) int { return p.Low }},
+			{"Dependencies", func(p trendPoint) int { return p.Dependencies }},
+		}
+		fmt.Println()
+		fmt.Println(output.Bold(fmt.Sprintf("Trend over %d analyses:", len(points))))
+		for _, s := range series {
+			values := make([]int, len(points))
+			for i, p := range points {
+				values[i] = s.value(p)
+			}
+			first, last := values[0], values[len(values)-1]
+			fmt.Printf("  %-13s %s  %d → %d (%+d)\n", s.name, output.Sparkline(values), first, last, last-first)
+		}
+		return nil
+	},
+}
+
+// missingResults reports whether err means an analysis has no results, as
+// opposed to a failure that must not be hidden by skipping the analysis
+func missingResults(err error) bool {
+	return errors.Is(err, codeclarity.ErrNotFound) || errors.Is(err, store.ErrNotSynced)
+}
+
+func init() {
+	trendCmd.Flags().StringVar(&trendBranch, "branch", "", "Only include analyses of this branch")
+	trendCmd.Flags().StringVar(&trendWorkspace, "workspace", "", "Workspace to collect results for")
+	trendCmd.Flags().IntVar(&trendLimit, "limit", 20, "Number of most recent finished analyses to include (0 for all)")
+}
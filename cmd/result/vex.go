@@ -0,0 +1,224 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/internal/vex"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vexWorkspace     string
+	vexTriageFile    string
+	vexAuthor        string
+	vexProduct       string
+	vexDefaultStatus string
+	vexOut           string
+)
+
+var vexCmd = &cobra.Command{
+	Use:   "vex <project-id> <analysis-id>",
+	Short: "Generate an OpenVEX document",
+	Long: `Generate an OpenVEX document describing how the vulnerabilities found in
+an analysis affect the project.
+
+The status of each finding is derived, in order of precedence, from:
+  1. a triage file (--triage) with explicit decisions
+  2. the suppression file, whose entries become not_affected
+  3. the default status (--default-status)
+
+Triage file format:
+  triage:
+    - id: CVE-2023-12345
+      package: lodash       # optional scope
+      status: not_affected
+      justification: vulnerable_code_not_in_execute_path
+
+Example:
+  codeclarity result vex <project-id> <analysis-id> --out vex.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+		analysisID := args[1]
+
+		orgID := getOrgID(cmd)
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		defaultStatus := vex.Status(vexDefaultStatus)
+		if !defaultStatus.Valid() {
+			output.Error("Invalid default status %q. Use affected, not_affected, fixed or under_investigation", vexDefaultStatus)
+			return nil
+		}
+
+		var triage *vex.Triage
+		if vexTriageFile != "" {
+			var err error
+			triage, err = vex.LoadTriage(vexTriageFile)
+			if err != nil {
+				output.Error("Failed to load triage file: %v", err)
+				return nil
+			}
+		}
+
+		suppressions, err := suppressFlags.Load()
+		if err != nil {
+			output.Error("Failed to load suppressions: %v", err)
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		vulns, err := client.Results.AllVulnerabilities(cmd.Context(), orgID, projectID, analysisID, vexWorkspace)
+		if err != nil {
+			output.Error("Failed to get vulnerabilities: %v", err)
+			return nil
+		}
+
+		product := vexProduct
+		if product == "" {
+			product = projectPURL(projectID)
+			if project, err := client.Projects.Get(cmd.Context(), orgID, projectID); err == nil && project.URL != "" {
+				product = project.URL
+			}
+		}
+
+		author := vexAuthor
+		if author == "" {
+			author = "CodeClarity"
+			if user, err := client.Auth.CurrentUser(cmd.Context()); err == nil && user.Email != "" {
+				author = user.Email
+			}
+		}
+
+		doc := vex.NewDocument(author)
+		for _, v := range vulns {
+			doc.Statements = append(doc.Statements, vexStatements(v, product, triage, suppressions, defaultStatus)...)
+		}
+		doc.Finalize()
+		suppress.Report(suppressions, true)
+
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if vexOut == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(vexOut, data, 0644); err != nil {
+			output.Error("Failed to write %s: %v", vexOut, err)
+			return nil
+		}
+		output.Success("Wrote %d statements to %s", len(doc.Statements), vexOut)
+		return nil
+	},
+}
+
+// projectPURL is the product identifier of a project without a URL
+func projectPURL(projectID string) string {
+	return "pkg:generic/" + projectID
+}
+
+// vexProducts returns the product identifiers a VEX statement may name to
+// apply to a project: the --product value if given, else the project URL and
+// its generic package URL
+func vexProducts(product, projectID, projectURL string) []string {
+	if product != "" {
+		return []string{product}
+	}
+	products := []string{projectPURL(projectID)}
+	if projectURL != "" {
+		products = append(products, projectURL)
+	}
+	return products
+}
+
+// vexStatements builds one statement per affected dependency of a vulnerability
+func vexStatements(v codeclarity.Vulnerability, product string, triage *vex.Triage, suppressions *suppress.Set, defaultStatus vex.Status) []vex.Statement {
+	vuln := vex.Vulnerability{Name: v.ID, Description: v.Description}
+	seen := map[string]bool{v.ID: true}
+	for _, a := range v.Affected {
+		if a.VulnerabilityId != "" && !seen[a.VulnerabilityId] {
+			seen[a.VulnerabilityId] = true
+			vuln.Aliases = append(vuln.Aliases, a.VulnerabilityId)
+		}
+	}
+
+	affected := v.Affected
+	if len(affected) == 0 {
+		// Without dependency details the statement covers the whole product
+		affected = []codeclarity.AffectedVuln{{}}
+	}
+
+	var statements []vex.Statement
+	for _, a := range affected {
+		stmt := vex.Statement{
+			Vulnerability: vuln,
+			Products:      []vex.Product{{ID: product}},
+			Status:        defaultStatus,
+		}
+		if a.AffectedDependency != "" {
+			stmt.Products[0].Subcomponents = []vex.Component{{ID: vex.PackageURL(a.AffectedDependency, a.AffectedVersion)}}
+		}
+
+		// Match the suppression against this dependency only
+		scoped := v
+		if a.AffectedDependency != "" {
+			scoped.Affected = []codeclarity.AffectedVuln{a}
+		}
+
+		if entry := triage.Lookup(vuln, a.AffectedDependency); entry != nil {
+			stmt.Status = entry.Status
+			stmt.Justification = entry.Justification
+			stmt.ImpactStatement = entry.ImpactStatement
+			stmt.ActionStatement = entry.ActionStatement
+		} else if rule := suppressions.Match(&scoped); rule != nil {
+			stmt.Status = vex.StatusNotAffected
+			stmt.ImpactStatement = rule.Justification
+		}
+
+		// OpenVEX requires an impact statement or justification for not_affected
+		// and an action statement for affected
+		switch stmt.Status {
+		case vex.StatusNotAffected:
+			if stmt.Justification == "" && stmt.ImpactStatement == "" {
+				stmt.ImpactStatement = "Triaged as not affected"
+			}
+		case vex.StatusAffected:
+			if stmt.ActionStatement == "" {
+				if a.AffectedDependency != "" {
+					stmt.ActionStatement = fmt.Sprintf("Upgrade %s to a version that is not affected", a.AffectedDependency)
+				} else {
+					stmt.ActionStatement = "Upgrade the affected dependency to a version that is not affected"
+				}
+			}
+		}
+
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+func init() {
+	vexCmd.Flags().StringVar(&vexWorkspace, "workspace", "", "Filter by workspace")
+	vexCmd.Flags().StringVar(&vexTriageFile, "triage", "", "Triage file with explicit statuses (YAML or JSON)")
+	vexCmd.Flags().StringVar(&vexAuthor, "author", "", "Document author (defaults to the logged-in user)")
+	vexCmd.Flags().StringVar(&vexProduct, "product", "", "Product identifier (defaults to the project URL)")
+	vexCmd.Flags().StringVar(&vexDefaultStatus, "default-status", string(vex.StatusAffected), "Status for findings without triage or suppression")
+	vexCmd.Flags().StringVar(&vexOut, "out", "", "Write the document to a file instead of stdout")
+}
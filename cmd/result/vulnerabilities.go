@@ -1,96 +1,332 @@
 package result
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/internal/vex"
+	"codeclarity.io/pkg/codeclarity"
 	"github.com/spf13/cobra"
 )
 
 var vulnsWorkspace string
 var vulnsPage int
 var vulnsPerPage int
+var vulnsVEXFiles []string
+var vulnsProduct string
+var vulnsFailSeverity string
+var vulnsLockFile string
+
+// vulnerabilityPage is the structured output of the vulnerabilities command.
+// Suppressed and VEX-resolved vulnerabilities are left out of Data and
+// counted separately.
+type vulnerabilityPage struct {
+	Data           []codeclarity.Vulnerability `json:"data"`
+	Page           int                         `json:"page"`
+	EntryCount     int                         `json:"entry_count"`
+	EntriesPerPage int                         `json:"entries_per_page"`
+	TotalPages     int                         `json:"total_pages"`
+	// ServerTotalEntries is the number of vulnerabilities reported by the
+	// server, before suppressions and VEX statements are applied
+	ServerTotalEntries int `json:"server_total_entries"`
+	// Suppressed and VEXResolved count the vulnerabilities of this page
+	// hidden by the suppression file and by VEX documents
+	Suppressed  int `json:"suppressed"`
+	VEXResolved int `json:"vex_resolved"`
+}
 
 var vulnerabilitiesCmd = &cobra.Command{
 	Use:   "vulnerabilities <project-id> <analysis-id>",
 	Short: "List vulnerabilities",
-	Long:  `List vulnerabilities found in an analysis.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `List vulnerabilities found in an analysis.
+
+With --output junit, every affected dependency becomes a test case that fails
+when it has vulnerabilities at or above --fail-severity, so results show up
+in CI test report widgets:
+  codeclarity result vulnerabilities <project-id> <analysis-id> --output junit > codeclarity.xml
+
+GitLab merge request widgets are supported with --output gitlab (dependency
+scanning report) and --output codeclimate (code quality report):
+  codeclarity result vulnerabilities <project-id> <analysis-id> --output gitlab > gl-dependency-scanning-report.json
+
+With --output github, findings are emitted as workflow command annotations
+and a markdown summary is appended to $GITHUB_STEP_SUMMARY. This mode is
+enabled automatically when GITHUB_ACTIONS=true and no --output is given.
+
+With --output ndjson, every page is fetched in turn and each vulnerability is
+written as one line of JSON as soon as its page arrives:
+  codeclarity result vulnerabilities <project-id> <analysis-id> --output ndjson | jq -c .
+
+With --vex, findings resolved as not_affected or fixed by OpenVEX statements
+are hidden. Statements apply when one of their products is the project URL,
+pkg:generic/<project-id>, or the --product identifier:
+  codeclarity result vulnerabilities <project-id> <analysis-id> --vex vex.json
+
+With --offline, results saved by 'codeclarity sync' are shown instead.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		projectID := args[0]
 		analysisID := args[1]
 
+		if !output.ValidSeverity(vulnsFailSeverity) {
+			output.Error("Invalid severity %q. Use critical, high, medium, low or none", vulnsFailSeverity)
+			return nil
+		}
+
 		orgID := getOrgID(cmd)
 		if orgID == "" {
 			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
 			return nil
 		}
 
-		client, err := api.NewAuthenticatedClient()
+		suppressions, err := suppressFlags.Load()
 		if err != nil {
-			output.Error("Authentication required: %v", err)
+			output.Error("Failed to load suppressions: %v", err)
 			return nil
 		}
 
-		vulns, err := client.GetVulnerabilities(orgID, projectID, analysisID, vulnsWorkspace, vulnsPage, vulnsPerPage)
-		if err != nil {
-			output.Error("Failed to get vulnerabilities: %v", err)
-			return nil
+		var vexDocs []*vex.Document
+		for _, path := range vulnsVEXFiles {
+			doc, err := vex.Load(path)
+			if err != nil {
+				output.Error("Failed to load VEX document: %v", err)
+				return nil
+			}
+			vexDocs = append(vexDocs, doc)
 		}
+		var vexIndex *vex.Index
 
 		format, _ := cmd.Root().Flags().GetString("output")
-		if format == "json" || format == "yaml" {
+		if format == "" && output.GitHubActions() {
+			format = string(output.FormatGitHub)
+		}
+
+		var vulns *codeclarity.PaginatedResponse[codeclarity.Vulnerability]
+		if api.Offline() {
+			snap, err := loadOfflineAnalysis(orgID, projectID, analysisID, vulnsWorkspace)
+			if err != nil {
+				output.Error("%v", err)
+				return nil
+			}
+			if len(vexDocs) > 0 {
+				var projectURL string
+				if st, err := store.Open(); err == nil {
+					if project, err := st.Project(orgID, projectID); err == nil {
+						projectURL = project.Project.URL
+					}
+				}
+				vexIndex = vex.NewIndex(vexProducts(vulnsProduct, projectID, projectURL), vexDocs...)
+			}
+			if output.IsReportFormat(format) || output.Format(format) == output.FormatNDJSON {
+				vulns = store.Paginate(snap.Vulnerabilities, 0, 0)
+			} else {
+				vulns = store.Paginate(snap.Vulnerabilities, vulnsPage, vulnsPerPage)
+			}
+		} else {
+			client, err := api.NewAuthenticatedClient()
+			if err != nil {
+				output.Error("Authentication required: %v", err)
+				return nil
+			}
+
+			if len(vexDocs) > 0 {
+				var projectURL string
+				if vulnsProduct == "" {
+					if project, err := client.Projects.Get(cmd.Context(), orgID, projectID); err == nil {
+						projectURL = project.URL
+					}
+				}
+				vexIndex = vex.NewIndex(vexProducts(vulnsProduct, projectID, projectURL), vexDocs...)
+			}
+
+			if output.Format(format) == output.FormatNDJSON {
+				return streamVulnerabilities(cmd.Context(), client, orgID, projectID, analysisID, suppressions, vexIndex)
+			}
+
+			if output.IsReportFormat(format) {
+				// Report formats describe the whole analysis, not a single page
+				all, err := client.Results.AllVulnerabilities(cmd.Context(), orgID, projectID, analysisID, vulnsWorkspace)
+				if err != nil {
+					output.Error("Failed to get vulnerabilities: %v", err)
+					return nil
+				}
+				vulns = &codeclarity.PaginatedResponse[codeclarity.Vulnerability]{Data: all, TotalEntries: len(all), TotalPages: 1}
+			} else {
+				vulns, err = client.Results.Vulnerabilities(cmd.Context(), orgID, projectID, analysisID, &codeclarity.VulnerabilityListOptions{
+					ListOptions: codeclarity.ListOptions{Page: vulnsPage, PerPage: vulnsPerPage},
+					Workspace:   vulnsWorkspace,
+				})
+				if err != nil {
+					output.Error("Failed to get vulnerabilities: %v", err)
+					return nil
+				}
+			}
+		}
+
+		kept, suppressed := suppressions.Filter(vulns.Data)
+		var resolved []codeclarity.Vulnerability
+		if vexIndex != nil {
+			kept, resolved = vexIndex.Filter(kept)
+		}
+		vulns.Data = kept
+		// Unused suppressions can only be detected when every vulnerability was seen
+		suppress.Report(suppressions, vulns.TotalPages <= 1)
+
+		switch output.Format(format) {
+		case output.FormatJUnit:
+			formatter := output.NewFormatter(format)
+			return formatter.PrintJUnit(vulns.Data, output.JUnitOptions{
+				SuiteName:    "codeclarity." + analysisID,
+				FailSeverity: vulnsFailSeverity,
+			})
+		case output.FormatGitLab:
+			formatter := output.NewFormatter(format)
+			return formatter.PrintGitLab(vulns.Data, output.GitLabOptions{LockFile: vulnsLockFile})
+		case output.FormatCodeClimate:
+			formatter := output.NewFormatter(format)
+			return formatter.PrintCodeClimate(vulns.Data, output.CodeClimateOptions{LockFile: vulnsLockFile})
+		case output.FormatGitHub:
+			formatter := output.NewFormatter(format)
+			return formatter.PrintGitHub(vulns.Data, output.GitHubOptions{
+				Title:    "CodeClarity vulnerabilities (" + analysisID + ")",
+				LockFile: vulnsLockFile,
+			})
+		}
+
+		if output.IsStructuredFormat(format) {
 			formatter := output.NewFormatter(format)
-			return formatter.Print(vulns)
+			return formatter.Print(vulnerabilityPage{
+				Data:               vulns.Data,
+				Page:               vulns.Page,
+				EntryCount:         len(vulns.Data),
+				EntriesPerPage:     vulns.EntriesPerPage,
+				TotalPages:         vulns.TotalPages,
+				ServerTotalEntries: vulns.TotalEntries,
+				Suppressed:         len(suppressed),
+				VEXResolved:        len(resolved),
+			})
 		}
 
 		// Table output
 		if len(vulns.Data) == 0 {
 			output.Success("No vulnerabilities found")
+			if len(suppressed) > 0 {
+				fmt.Printf("%d suppressed by %s\n", len(suppressed), suppressions.Path)
+			}
+			if len(resolved) > 0 {
+				fmt.Printf("%d resolved as not_affected or fixed by VEX\n", len(resolved))
+			}
 			return nil
 		}
 
-		fmt.Printf("Found %d vulnerabilities (page %d of %d)\n\n", vulns.TotalEntries, vulns.Page+1, vulns.TotalPages)
+		if vulns.TotalPages <= 1 {
+			fmt.Printf("Found %d vulnerabilities\n", len(vulns.Data))
+		} else {
+			fmt.Printf("Found %d vulnerabilities on the server (page %d of %d)\n", vulns.TotalEntries, vulns.Page+1, vulns.TotalPages)
+		}
+		if len(suppressed) > 0 {
+			fmt.Printf("%d on this page suppressed by %s\n", len(suppressed), suppressions.Path)
+		}
+		if len(resolved) > 0 {
+			fmt.Printf("%d on this page resolved as not_affected or fixed by VEX\n", len(resolved))
+		}
+		fmt.Println()
+
+		return printVulnerabilityTable(vulns.Data)
+	},
+}
+
+// printVulnerabilityTable prints vulnerabilities as a table
+func printVulnerabilityTable(vulns []codeclarity.Vulnerability) error {
+	columns := []output.Column{
+		{Header: "ID"},
+		{Header: "Severity", Color: output.SeverityColor},
+		{Header: "CVSS"},
+		{Header: "Package"},
+		{Header: "Version"},
+		{Header: "Description", MaxWidth: 60},
+		{Header: "EPSS", Wide: true},
+		{Header: "Vector", Wide: true},
+		{Header: "Affected", Wide: true},
+	}
+	var rows [][]string
 
-		headers := []string{"ID", "Severity", "CVSS", "Package", "Version", "Description"}
-		var rows [][]string
+	for _, v := range vulns {
+		// Get first affected package info
+		pkgName := "-"
+		pkgVersion := "-"
+		if len(v.Affected) > 0 {
+			pkgName = v.Affected[0].AffectedDependency
+			pkgVersion = v.Affected[0].AffectedVersion
+		}
 
-		for _, v := range vulns.Data {
-			// Get first affected package info
-			pkgName := "-"
-			pkgVersion := "-"
-			if len(v.Affected) > 0 {
-				pkgName = v.Affected[0].AffectedDependency
-				pkgVersion = v.Affected[0].AffectedVersion
-			}
+		epss := "-"
+		if v.EPSS != nil {
+			epss = fmt.Sprintf("%.4f", v.EPSS.Score)
+		}
 
-			// Truncate description
-			desc := v.Description
-			if len(desc) > 60 {
-				desc = desc[:57] + "..."
-			}
+		var affected []string
+		for _, a := range v.Affected {
+			affected = append(affected, a.AffectedDependency+"@"+a.AffectedVersion)
+		}
 
-			row := []string{
-				v.ID,
-				output.SeverityColor(v.Severity.SeverityClass),
-				fmt.Sprintf("%.1f", v.Severity.Severity),
-				pkgName,
-				pkgVersion,
-				desc,
-			}
-			rows = append(rows, row)
+		row := []string{
+			v.ID,
+			v.Severity.SeverityClass,
+			fmt.Sprintf("%.1f", v.Severity.Severity),
+			pkgName,
+			pkgVersion,
+			v.Description,
+			epss,
+			v.Severity.Vector,
+			strings.Join(affected, ", "),
 		}
+		rows = append(rows, row)
+	}
 
-		formatter := output.NewFormatter("table")
-		formatter.PrintTable(headers, rows)
+	formatter := output.NewFormatter("table")
+	return formatter.PrintColumns(columns, rows)
+}
 
+// streamVulnerabilities prints every vulnerability as one JSON line per record,
+// page by page, without pagination metadata
+func streamVulnerabilities(ctx context.Context, client *codeclarity.Client, orgID, projectID, analysisID string, suppressions *suppress.Set, vexIndex *vex.Index) error {
+	formatter := output.NewFormatter(string(output.FormatNDJSON))
+	opts := &codeclarity.VulnerabilityListOptions{
+		ListOptions: codeclarity.ListOptions{PerPage: vulnsPerPage},
+		Workspace:   vulnsWorkspace,
+	}
+	err := client.Results.EachVulnerabilityPage(ctx, orgID, projectID, analysisID, opts, func(page *codeclarity.PaginatedResponse[codeclarity.Vulnerability]) error {
+		kept, _ := suppressions.Filter(page.Data)
+		if vexIndex != nil {
+			kept, _ = vexIndex.Filter(kept)
+		}
+		for _, v := range kept {
+			if err := formatter.PrintRecord(v); err != nil {
+				return err
+			}
+		}
 		return nil
-	},
+	})
+	if err != nil {
+		output.Error("Failed to get vulnerabilities: %v", err)
+		return nil
+	}
+	suppress.Report(suppressions, true)
+	return nil
 }
 
 func init() {
 	vulnerabilitiesCmd.Flags().StringVar(&vulnsWorkspace, "workspace", "", "Filter by workspace")
 	vulnerabilitiesCmd.Flags().IntVar(&vulnsPage, "page", 0, "Page number (0-indexed)")
 	vulnerabilitiesCmd.Flags().IntVar(&vulnsPerPage, "per-page", 20, "Results per page")
+	vulnerabilitiesCmd.Flags().StringVar(&vulnsFailSeverity, "fail-severity", "high", "Minimum severity failing a dependency test case in --output junit")
+	vulnerabilitiesCmd.Flags().StringVar(&vulnsLockFile, "lockfile", "", "File findings are attributed to in gitlab, codeclimate and github reports")
+	vulnerabilitiesCmd.Flags().StringSliceVar(&vulnsVEXFiles, "vex", nil, "OpenVEX document(s) hiding not_affected and fixed findings")
+	vulnerabilitiesCmd.Flags().StringVar(&vulnsProduct, "product", "", "Product identifier VEX statements must name (defaults to the project URL)")
 }
@@ -6,9 +6,14 @@ import (
 
 	"codeclarity.io/cmd/analysis"
 	"codeclarity.io/cmd/analyzer"
+	"codeclarity.io/cmd/org"
 	"codeclarity.io/cmd/project"
+	"codeclarity.io/cmd/report"
 	"codeclarity.io/cmd/result"
+	"codeclarity.io/cmd/scan"
+	"codeclarity.io/internal/api"
 	"codeclarity.io/internal/config"
+	"codeclarity.io/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +26,15 @@ var (
 	outputFormat string
 	debug        bool
 	apiURL       string
+	columns      []string
+	wide         bool
+	sortBy       string
+	noHeaders    bool
+	colorMode    string
+	recordDir    string
+	replayDir    string
+	noCache      bool
+	offline      bool
 
 	// Config
 	cfg *config.Config
@@ -39,6 +53,36 @@ Get started:
   codeclarity project list       # List your projects
   codeclarity analysis start     # Start a new analysis`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments are validated before this runs; later errors, such as an
+		// unknown --columns name, are not helped by printing the usage
+		cmd.SilenceUsage = true
+
+		if err := output.SetColorMode(colorMode); err != nil {
+			return err
+		}
+		output.SetDefaults(output.Options{
+			Columns:   columns,
+			Wide:      wide,
+			SortBy:    sortBy,
+			NoHeaders: noHeaders,
+		})
+
+		if recordDir != "" && replayDir != "" {
+			return fmt.Errorf("--record and --replay cannot be used together")
+		}
+		if recordDir != "" {
+			api.Record(recordDir)
+		}
+		if replayDir != "" {
+			api.Replay(replayDir)
+		}
+		if noCache {
+			api.DisableCache()
+		}
+		if offline {
+			api.GoOffline()
+		}
+
 		// Skip config loading for certain commands
 		if cmd.Name() == "login" || cmd.Name() == "version" || cmd.Name() == "help" {
 			return nil
@@ -72,17 +116,34 @@ func Execute() {
 	}
 }
 
+// Root returns the root command, e.g. to run the CLI in-process
+func Root() *cobra.Command {
+	return rootCmd
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&orgID, "org", "o", "", "Organization ID (overrides default)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "f", "", "Output format: table, json, yaml")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "f", "", "Output format: table, json, yaml, ndjson, csv, tsv, junit, gitlab, codeclimate, github, go-template=..., template-file=..., jsonpath=...")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Enable debug output")
 	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "API base URL (overrides config)")
+	rootCmd.PersistentFlags().StringSliceVar(&columns, "columns", nil, "Comma-separated columns to include in table, csv and tsv output")
+	rootCmd.PersistentFlags().BoolVar(&wide, "wide", false, "Show extra columns and disable truncation in tables")
+	rootCmd.PersistentFlags().StringVar(&sortBy, "sort-by", "", "Sort table rows by column (prefix with - or suffix with :desc for descending)")
+	rootCmd.PersistentFlags().BoolVar(&noHeaders, "no-headers", false, "Omit the header row in table, csv and tsv output")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", output.ColorAuto, "Colorize output: auto, always, never (auto honors NO_COLOR and TTY detection)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save sanitized API requests and responses to this directory")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Serve analysis and result commands from data saved by 'codeclarity sync'")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Bypass the local response cache")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Answer API requests from a --record directory instead of the network")
 
 	// Add subcommands
 	rootCmd.AddCommand(analyzer.AnalyzerCmd)
 	rootCmd.AddCommand(project.ProjectCmd)
 	rootCmd.AddCommand(analysis.AnalysisCmd)
 	rootCmd.AddCommand(result.ResultCmd)
+	rootCmd.AddCommand(report.ReportCmd)
+	rootCmd.AddCommand(org.OrgCmd)
+	rootCmd.AddCommand(scan.ScanCmd)
 }
 
 // GetOrgID returns the organization ID from flags or config
@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/sbom"
+	"github.com/spf13/cobra"
+)
+
+var sbomOut string
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [dir]",
+	Short: "Generate a CycloneDX SBOM from local lockfiles",
+	Long: `Generate a CycloneDX SBOM of a local project without uploading anything.
+
+Every package-lock.json, yarn.lock (classic and berry), pnpm-lock.yaml and
+composer.lock under dir is parsed into a dependency graph. Files ignored by
+.gitignore are skipped, as are .git, node_modules and vendor directories.
+Components record whether they are direct or transitive dependencies and
+whether they are only needed for development; workspaces are listed as
+application components.
+
+The SBOM is written to stdout, or to --out with a summary of each lockfile:
+  codeclarity scan sbom . > bom.json
+  codeclarity scan sbom ./app --out bom.cdx.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		graphs, err := sbom.Scan(dir)
+		if err != nil {
+			output.Error("%v", err)
+			return nil
+		}
+		if len(graphs) == 0 {
+			output.Error("No lockfiles found in %s", dir)
+			return nil
+		}
+
+		bom := sbom.NewBOM(filepath.Base(abs), graphs, api.UserAgent)
+		data, err := json.MarshalIndent(bom, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+		if sbomOut == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(sbomOut, data, 0644); err != nil {
+			output.Error("Failed to write SBOM: %v", err)
+			return nil
+		}
+
+		columns := []output.Column{
+			{Header: "Lockfile"},
+			{Header: "Ecosystem"},
+			{Header: "Workspaces"},
+			{Header: "Packages"},
+			{Header: "Direct"},
+			{Header: "Transitive"},
+			{Header: "Dev"},
+		}
+		var rows [][]string
+		for _, g := range graphs {
+			stats := g.Stats()
+			var workspaces []string
+			for _, imp := range g.Importers {
+				if imp.Path != "." {
+					workspaces = append(workspaces, imp.Path)
+				}
+			}
+			ws := "-"
+			if len(workspaces) > 0 {
+				ws = strings.Join(workspaces, ", ")
+			}
+			rows = append(rows, []string{
+				g.Lockfile,
+				string(g.Ecosystem),
+				ws,
+				fmt.Sprintf("%d", stats.Packages),
+				fmt.Sprintf("%d", stats.Direct),
+				fmt.Sprintf("%d", stats.Transitive),
+				fmt.Sprintf("%d", stats.Dev),
+			})
+		}
+		formatter := output.NewFormatter("table")
+		if err := formatter.PrintColumns(columns, rows); err != nil {
+			return err
+		}
+		fmt.Println()
+		output.Success("SBOM with %d components written to %s", len(bom.Components), sbomOut)
+		return nil
+	},
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomOut, "out", "", "File to write the SBOM to (default stdout)")
+}
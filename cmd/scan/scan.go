@@ -0,0 +1,16 @@
+package scan
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// ScanCmd represents the scan command group
+var ScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Inspect local projects",
+	Long:  `Inspect a local source tree without the CodeClarity server.`,
+}
+
+func init() {
+	ScanCmd.AddCommand(sbomCmd)
+}
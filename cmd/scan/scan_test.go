@@ -0,0 +1,81 @@
+package scan_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"codeclarity.io/internal/clitest"
+)
+
+const packageLock = `{
+  "name": "app",
+  "version": "1.0.0",
+  "lockfileVersion": 3,
+  "packages": {
+    "": {
+      "name": "app",
+      "version": "1.0.0",
+      "dependencies": {"qs": "^6.5.0"},
+      "devDependencies": {"debug": "^2.6.0"}
+    },
+    "node_modules/qs": {"version": "6.5.2"},
+    "node_modules/debug": {"version": "2.6.8", "dev": true, "dependencies": {"ms": "2.0.0"}},
+    "node_modules/ms": {"version": "2.0.0", "dev": true}
+  }
+}
+`
+
+func writeLockfile(t *testing.T, env *clitest.Env) {
+	env.WriteFile(t, "app/package.json", `{"name": "app", "version": "1.0.0"}`)
+	env.WriteFile(t, "app/package-lock.json", packageLock)
+}
+
+func TestScanCommands(t *testing.T) {
+	cases := []clitest.Case{
+		{
+			Name:   "sbom to stdout",
+			Args:   []string{"scan", "sbom", "app"},
+			Setup:  writeLockfile,
+			Stdout: []string{`"bomFormat": "CycloneDX"`, "pkg:npm/qs@6.5.2", "pkg:npm/ms@2.0.0"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				if n := env.Count("", ""); n != 0 {
+					t.Errorf("scan sent %d requests to the API", n)
+				}
+			},
+		},
+		{
+			Name:   "sbom to file",
+			Args:   []string{"scan", "sbom", "app", "--out", "bom.json"},
+			Setup:  writeLockfile,
+			Stdout: []string{"package-lock.json", "npm", "SBOM with 3 components written to bom.json"},
+			Check: func(t *testing.T, env *clitest.Env, res clitest.Result) {
+				data, err := os.ReadFile(env.Path("bom.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var bom struct {
+					Components []struct {
+						Name string `json:"name"`
+					} `json:"components"`
+				}
+				if err := json.Unmarshal(data, &bom); err != nil {
+					t.Fatalf("bom.json is not JSON: %v", err)
+				}
+				if len(bom.Components) != 3 {
+					t.Errorf("bom.json has %d components, want 3", len(bom.Components))
+				}
+			},
+		},
+		{
+			Name: "sbom without lockfiles",
+			Args: []string{"scan", "sbom", "app"},
+			Setup: func(t *testing.T, env *clitest.Env) {
+				env.WriteFile(t, "app/package.json", `{"name": "app"}`)
+			},
+			Stderr: []string{"No lockfiles found in app"},
+		},
+	}
+
+	clitest.RunCases(t, cases)
+}
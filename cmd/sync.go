@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/output"
+	"codeclarity.io/internal/store"
+	"codeclarity.io/pkg/codeclarity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncWorkspace string
+	syncLimit     int
+	syncForce     bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <project-id>",
+	Short: "Download project results for offline use",
+	Long: `Download a project's analyses together with the vulnerability, SBOM and
+license statistics and the full vulnerability list of its most recent
+finished analyses into ~/.codeclarity/store.
+
+Synced data is served by 'analysis list/get' and 'result summary/vulnerabilities'
+when --offline is given:
+  codeclarity sync <project-id>
+  codeclarity --offline result summary <project-id> <analysis-id>
+
+Results of finished analyses never change, so analyses already in the store
+are skipped unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectID := args[0]
+
+		orgID := GetOrgID()
+		if orgID == "" {
+			output.Error("Organization ID required. Use --org flag or set default with 'codeclarity config set org <id>'")
+			return nil
+		}
+
+		client, err := api.NewAuthenticatedClient()
+		if err != nil {
+			output.Error("Authentication required: %v", err)
+			return nil
+		}
+
+		st, err := store.Open()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		project, err := client.Projects.Get(ctx, orgID, projectID)
+		if err != nil {
+			output.Error("Failed to get project: %v", err)
+			return nil
+		}
+
+		analyses, err := client.Analyses.All(ctx, orgID, projectID)
+		if err != nil {
+			output.Error("Failed to list analyses: %v", err)
+			return nil
+		}
+
+		// Results are synced for the most recent finished analyses
+		var finished []codeclarity.Analysis
+		for _, a := range analyses {
+			if a.Status.Succeeded() {
+				finished = append(finished, a)
+			}
+		}
+		sort.SliceStable(finished, func(i, j int) bool {
+			return finished[i].CreatedOn.After(finished[j].CreatedOn)
+		})
+		if syncLimit > 0 && len(finished) > syncLimit {
+			finished = finished[:syncLimit]
+		}
+
+		synced, skipped := 0, 0
+		for _, a := range finished {
+			if !syncForce && st.HasAnalysis(orgID, projectID, a.ID, syncWorkspace) {
+				skipped++
+				continue
+			}
+
+			output.Info("Syncing analysis %s (%s)", a.ID, a.CreatedOn.Format("2006-01-02 15:04"))
+			snap, err := fetchAnalysisSnapshot(ctx, client, orgID, projectID, a)
+			if err != nil {
+				output.Error("Failed to sync analysis %s: %v", a.ID, err)
+				return nil
+			}
+			if err := st.SaveAnalysis(orgID, projectID, snap); err != nil {
+				return err
+			}
+			synced++
+		}
+
+		if err := st.SaveProject(orgID, &store.ProjectSnapshot{
+			Project:  *project,
+			Analyses: analyses,
+			SyncedAt: time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+
+		output.Success("Synced %s: %d analyses, results for %d (%d already up to date)",
+			project.Name, len(analyses), synced, skipped)
+		return nil
+	},
+}
+
+// fetchAnalysisSnapshot downloads the results of a finished analysis.
+// Statistics, dependencies and licenses the server cannot provide are left
+// empty.
+func fetchAnalysisSnapshot(ctx context.Context, client *codeclarity.Client, orgID, projectID string, a codeclarity.Analysis) (*store.AnalysisSnapshot, error) {
+	vulns, err := client.Results.AllVulnerabilities(ctx, orgID, projectID, a.ID, syncWorkspace)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &store.AnalysisSnapshot{
+		Analysis:        a,
+		Workspace:       syncWorkspace,
+		Vulnerabilities: vulns,
+		SyncedAt:        time.Now().UTC(),
+	}
+	if stats, err := client.Results.VulnerabilityStats(ctx, orgID, projectID, a.ID, syncWorkspace); err == nil {
+		snap.VulnerabilityStats = stats
+	} else {
+		output.Notice("No vulnerability stats for %s: %v", a.ID, err)
+	}
+	if stats, err := client.Results.SBOMStats(ctx, orgID, projectID, a.ID, syncWorkspace); err == nil {
+		snap.SBOMStats = stats
+	} else {
+		output.Notice("No SBOM stats for %s: %v", a.ID, err)
+	}
+	if stats, err := client.Results.LicenseStats(ctx, orgID, projectID, a.ID, syncWorkspace); err == nil {
+		snap.LicenseStats = stats
+	}
+	if deps, err := client.Results.AllDependencies(ctx, orgID, projectID, a.ID, syncWorkspace); err == nil {
+		snap.SBOM = deps
+	} else {
+		output.Notice("No SBOM for %s: %v", a.ID, err)
+	}
+	if licenses, err := client.Results.AllLicenses(ctx, orgID, projectID, a.ID, syncWorkspace); err == nil {
+		snap.Licenses = licenses
+	}
+	return snap, nil
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncWorkspace, "workspace", "", "Workspace to sync results for")
+	syncCmd.Flags().IntVar(&syncLimit, "limit", 5, "Number of most recent finished analyses to sync results for (0 for all)")
+	syncCmd.Flags().BoolVar(&syncForce, "force", false, "Download results again even if already synced")
+	rootCmd.AddCommand(syncCmd)
+}
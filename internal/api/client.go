@@ -1,404 +1,198 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"os"
-	"strings"
-	"time"
 
 	"codeclarity.io/internal/auth"
+	"codeclarity.io/internal/cache"
 	"codeclarity.io/internal/config"
+	"codeclarity.io/internal/recording"
+	"codeclarity.io/pkg/codeclarity"
 )
 
-// Client is the API client for CodeClarity
-type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	token      string
-}
-
-// NewClient creates a new API client
-func NewClient(baseURL string) *Client {
-	// Allow insecure TLS for local development
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: os.Getenv("CODECLARITY_ALLOW_INSECURE") == "true",
-		},
-	}
+// UserAgent identifies CLI requests to the API
+const UserAgent = "codeclarity-cli"
+
+var (
+	recordDir string
+	replayDir string
+	noCache   bool
+	offline   bool
+	// transport is shared by every client so recordings, replays and the
+	// cache keep a single state across a session
+	transport http.RoundTripper
+)
 
-	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout:   30 * time.Second,
-			Transport: transport,
-		},
-	}
+// Record makes clients save sanitized interactions to dir
+func Record(dir string) {
+	recordDir = dir
 }
 
-// NewAuthenticatedClient creates a new authenticated API client
-func NewAuthenticatedClient() (*Client, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, err
-	}
-
-	client := NewClient(cfg.APIBaseURL)
-
-	token, err := auth.GetAuthToken()
-	if err != nil {
-		// Check if we need to refresh
-		if auth.NeedsRefresh() {
-			refreshToken, err := auth.GetRefreshToken()
-			if err != nil {
-				return nil, err
-			}
-			newTokens, err := client.RefreshToken(refreshToken)
-			if err != nil {
-				return nil, fmt.Errorf("failed to refresh token: %w", err)
-			}
-			// Save the new tokens
-			tokens, _ := auth.LoadTokens()
-			tokens.AccessToken = newTokens.Token
-			tokens.RefreshToken = newTokens.RefreshToken
-			tokens.TokenExpiry = newTokens.TokenExpiry
-			tokens.RefreshTokenExpiry = newTokens.RefreshTokenExpiry
-			if err := auth.SaveTokens(tokens); err != nil {
-				return nil, err
-			}
-			token = newTokens.Token
-		} else {
-			return nil, err
-		}
-	}
-
-	client.SetToken(token)
-	return client, nil
+// Replay makes clients answer from interactions recorded in dir instead of
+// the network. Stored credentials are not needed while replaying.
+func Replay(dir string) {
+	replayDir = dir
 }
 
-// SetToken sets the authentication token
-func (c *Client) SetToken(token string) {
-	c.token = token
+// Replaying reports whether clients serve recorded interactions
+func Replaying() bool {
+	return replayDir != ""
 }
 
-// apiResponseWrapper is used to unwrap the standard API response format
-type apiResponseWrapper struct {
-	StatusCode int             `json:"status_code"`
-	Status     string          `json:"status"`
-	Data       json.RawMessage `json:"data"`
+// GoOffline makes every attempt to create a client fail, so commands serve
+// synced data or report that they need the server
+func GoOffline() {
+	offline = true
 }
 
-// doRequest performs an HTTP request
-func (c *Client) doRequest(method, path string, body interface{}, result interface{}) error {
-	var bodyReader io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		bodyReader = bytes.NewReader(jsonBody)
-	}
-
-	// Split path and query string to avoid encoding the query string
-	basePath := path
-	queryString := ""
-	if idx := strings.Index(path, "?"); idx != -1 {
-		basePath = path[:idx]
-		queryString = path[idx:]
-	}
-
-	reqURL, err := url.JoinPath(c.baseURL, basePath)
-	if err != nil {
-		return fmt.Errorf("failed to build URL: %w", err)
-	}
-	reqURL += queryString
-
-	req, err := http.NewRequest(method, reqURL, bodyReader)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// Offline reports whether --offline is in effect
+func Offline() bool {
+	return offline
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+// ErrOffline is returned when a client is requested in offline mode
+var ErrOffline = errors.New("the CodeClarity API is not reachable with --offline")
 
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
+// DisableCache makes clients bypass the on-disk response cache
+func DisableCache() {
+	noCache = true
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
+// Reset restores the defaults changed by Record, Replay, DisableCache and
+// GoOffline and drops the shared transport, so that several commands can run
+// in one process
+func Reset() {
+	recordDir = ""
+	replayDir = ""
+	noCache = false
+	offline = false
+	transport = nil
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+// transportFor wraps the CLI's HTTP transport with the response cache and
+// recording or replay. The recorder sits outside the cache so it saves the
+// responses commands are served, including cache hits, rather than the
+// conditional requests the cache makes.
+func transportFor(baseURL string, next http.RoundTripper) (http.RoundTripper, error) {
+	if transport != nil {
+		return transport, nil
 	}
 
-	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+	if replayDir != "" {
+		replayer, err := recording.LoadReplayer(replayDir, baseURL)
+		if err != nil {
+			return nil, err
 		}
-		apiErr.StatusCode = resp.StatusCode
-		return fmt.Errorf("API error: %s", apiErr.String())
+		transport = replayer
+		return transport, nil
 	}
 
-	if result != nil && len(respBody) > 0 {
-		// Check if response is wrapped in standard API format {"status_code":..., "data":...}
-		var wrapper apiResponseWrapper
-		if err := json.Unmarshal(respBody, &wrapper); err == nil && wrapper.Data != nil {
-			// Check if data is an array (paginated response) or object (single item)
-			// For paginated responses, the wrapper fields are at the same level as pagination
-			// For single item responses, data contains the actual item
-			trimmed := bytes.TrimSpace(wrapper.Data)
-			if len(trimmed) > 0 && trimmed[0] == '[' {
-				// Data is an array - this is a paginated response, parse directly
-				if err := json.Unmarshal(respBody, result); err != nil {
-					return fmt.Errorf("failed to parse paginated response: %w", err)
-				}
-			} else {
-				// Data is an object - unwrap it
-				if err := json.Unmarshal(wrapper.Data, result); err != nil {
-					return fmt.Errorf("failed to parse response data: %w", err)
-				}
-			}
-		} else {
-			// Try direct parsing (for responses without wrapper)
-			if err := json.Unmarshal(respBody, result); err != nil {
-				return fmt.Errorf("failed to parse response: %w", err)
-			}
+	if !noCache {
+		if dir, err := cache.Dir(); err == nil {
+			next = cache.NewTransport(dir, next)
 		}
 	}
-
-	return nil
-}
-
-// Auth endpoints
-
-// Authenticate authenticates with email and password
-func (c *Client) Authenticate(email, password string) (*AuthResponse, error) {
-	req := AuthRequest{
-		Email:    email,
-		Password: password,
-	}
-
-	var resp AuthResponse
-	if err := c.doRequest("POST", "/auth/authenticate", req, &resp); err != nil {
-		return nil, err
+	if recordDir != "" {
+		recorder, err := recording.NewRecorder(recordDir, baseURL, next)
+		if err != nil {
+			return nil, err
+		}
+		next = recorder
 	}
-
-	return &resp, nil
+	transport = next
+	return transport, nil
 }
 
-// RefreshToken refreshes the access token
-func (c *Client) RefreshToken(refreshToken string) (*AuthResponse, error) {
-	req := RefreshRequest{
-		RefreshToken: refreshToken,
+// NewClient creates an SDK client for baseURL configured the way the CLI
+// expects. Extra options are applied last.
+func NewClient(baseURL string, opts ...codeclarity.Option) (*codeclarity.Client, error) {
+	if offline {
+		return nil, ErrOffline
 	}
 
-	var resp AuthResponse
-	if err := c.doRequest("POST", "/auth/refresh", req, &resp); err != nil {
+	// Allow insecure TLS for local development
+	transport, err := transportFor(baseURL, &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: os.Getenv("CODECLARITY_ALLOW_INSECURE") == "true",
+		},
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return &resp, nil
-}
-
-// GetCurrentUser returns the current authenticated user
-func (c *Client) GetCurrentUser() (*User, error) {
-	var user User
-	if err := c.doRequest("GET", "/auth/user", nil, &user); err != nil {
-		return nil, err
+	base := []codeclarity.Option{
+		codeclarity.WithBaseURL(baseURL),
+		codeclarity.WithUserAgent(UserAgent),
+		codeclarity.WithRetries(codeclarity.DefaultRetryPolicy),
+		codeclarity.WithHTTPClient(&http.Client{
+			Timeout:   codeclarity.DefaultTimeout,
+			Transport: transport,
+		}),
 	}
-	return &user, nil
+	return codeclarity.New(append(base, opts...)...), nil
 }
 
-// Analyzer endpoints
-
-// ListAnalyzers lists analyzers for an organization
-func (c *Client) ListAnalyzers(orgID string, page, perPage int) (*PaginatedResponse[Analyzer], error) {
-	path := fmt.Sprintf("/org/%s/analyzers?page=%d&entries_per_page=%d", orgID, page, perPage)
-
-	var resp PaginatedResponse[Analyzer]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
+// NewAuthenticatedClient creates a client using the stored credentials or
+// CODECLARITY_API_KEY, refreshing an expired session when possible
+func NewAuthenticatedClient() (*codeclarity.Client, error) {
+	if offline {
+		return nil, ErrOffline
 	}
 
-	return &resp, nil
-}
-
-// GetAnalyzer gets an analyzer by ID
-func (c *Client) GetAnalyzer(orgID, analyzerID string) (*Analyzer, error) {
-	path := fmt.Sprintf("/org/%s/analyzers/%s", orgID, analyzerID)
-
-	var resp SingleResponse[Analyzer]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
+	cfg, err := config.Load()
+	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
-}
-
-// CreateAnalyzer creates a new analyzer
-func (c *Client) CreateAnalyzer(orgID string, req AnalyzerCreateRequest) (string, error) {
-	path := fmt.Sprintf("/org/%s/analyzers", orgID)
-
-	var resp CreatedResponse
-	if err := c.doRequest("POST", path, req, &resp); err != nil {
-		return "", err
-	}
-
-	return resp.ID, nil
-}
-
-// Project endpoints
-
-// ListProjects lists projects for an organization
-func (c *Client) ListProjects(orgID string, page, perPage int, search string) (*PaginatedResponse[Project], error) {
-	path := fmt.Sprintf("/org/%s/projects?page=%d&entries_per_page=%d", orgID, page, perPage)
-	if search != "" {
-		path += "&search_key=" + url.QueryEscape(search)
+	if Replaying() {
+		return NewClient(cfg.APIBaseURL)
 	}
 
-	var resp PaginatedResponse[Project]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
-}
-
-// GetProject gets a project by ID
-func (c *Client) GetProject(orgID, projectID string) (*Project, error) {
-	path := fmt.Sprintf("/org/%s/projects/%s", orgID, projectID)
-
-	var resp SingleResponse[Project]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
+	token, err := auth.GetAuthToken()
+	if err != nil {
+		// Check if we need to refresh
+		if !auth.NeedsRefresh() {
+			return nil, err
+		}
+		token, err = refreshToken(cfg.APIBaseURL)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &resp.Data, nil
+	return NewClient(cfg.APIBaseURL, codeclarity.WithToken(token))
 }
 
-// ImportProject imports a new project
-func (c *Client) ImportProject(orgID string, req ProjectImportRequest) (string, error) {
-	path := fmt.Sprintf("/org/%s/projects", orgID)
-
-	var resp CreatedResponse
-	if err := c.doRequest("POST", path, req, &resp); err != nil {
+// refreshToken exchanges the stored refresh token for a new session and
+// saves it
+func refreshToken(baseURL string) (string, error) {
+	refresh, err := auth.GetRefreshToken()
+	if err != nil {
 		return "", err
 	}
 
-	return resp.ID, nil
-}
-
-// Analysis endpoints
-
-// ListAnalyses lists analyses for a project
-func (c *Client) ListAnalyses(orgID, projectID string, page, perPage int) (*PaginatedResponse[Analysis], error) {
-	path := fmt.Sprintf("/org/%s/projects/%s/analyses?page=%d&entries_per_page=%d", orgID, projectID, page, perPage)
-
-	var resp PaginatedResponse[Analysis]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
-}
-
-// GetAnalysis gets an analysis by ID
-func (c *Client) GetAnalysis(orgID, projectID, analysisID string) (*Analysis, error) {
-	path := fmt.Sprintf("/org/%s/projects/%s/analyses/%s", orgID, projectID, analysisID)
-
-	var resp SingleResponse[Analysis]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp.Data, nil
-}
-
-// StartAnalysis starts a new analysis
-func (c *Client) StartAnalysis(orgID, projectID string, req AnalysisCreateRequest) (string, error) {
-	path := fmt.Sprintf("/org/%s/projects/%s/analyses", orgID, projectID)
-
-	var resp CreatedResponse
-	if err := c.doRequest("POST", path, req, &resp); err != nil {
+	client, err := NewClient(baseURL)
+	if err != nil {
 		return "", err
 	}
-
-	return resp.ID, nil
-}
-
-// Results endpoints
-
-// GetVulnerabilityStats gets vulnerability statistics for an analysis
-func (c *Client) GetVulnerabilityStats(orgID, projectID, analysisID, workspace string) (*VulnerabilityStats, error) {
-	// Default workspace to "." (root) if not specified
-	if workspace == "" {
-		workspace = "."
-	}
-	path := fmt.Sprintf("/org/%s/projects/%s/analysis/%s/vulnerabilities/stats?workspace=%s", orgID, projectID, analysisID, url.QueryEscape(workspace))
-
-	// doRequest already unwraps the "data" field, so parse directly into stats
-	var resp VulnerabilityStats
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
-}
-
-// GetSBOMStats gets SBOM statistics for an analysis
-func (c *Client) GetSBOMStats(orgID, projectID, analysisID, workspace string) (*SBOMStats, error) {
-	// Default workspace to "." (root) if not specified
-	if workspace == "" {
-		workspace = "."
-	}
-	path := fmt.Sprintf("/org/%s/projects/%s/analysis/%s/sbom/stats?workspace=%s", orgID, projectID, analysisID, url.QueryEscape(workspace))
-
-	// doRequest already unwraps the "data" field, so parse directly into stats
-	var resp SBOMStats
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
-}
-
-// GetLicenseStats gets license statistics for an analysis
-func (c *Client) GetLicenseStats(orgID, projectID, analysisID, workspace string) (*LicenseStats, error) {
-	path := fmt.Sprintf("/org/%s/projects/%s/analysis/%s/licenses/stats", orgID, projectID, analysisID)
-	if workspace != "" {
-		path += "?workspace=" + url.QueryEscape(workspace)
-	}
-
-	var resp SingleResponse[LicenseStats]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp.Data, nil
-}
-
-// GetVulnerabilities gets the list of vulnerabilities for an analysis
-func (c *Client) GetVulnerabilities(orgID, projectID, analysisID, workspace string, page, perPage int) (*PaginatedResponse[Vulnerability], error) {
-	path := fmt.Sprintf("/org/%s/projects/%s/analysis/%s/vulnerabilities?page=%d&entries_per_page=%d", orgID, projectID, analysisID, page, perPage)
-	if workspace != "" {
-		path += "&workspace=" + url.QueryEscape(workspace)
+	newTokens, err := client.Auth.Refresh(context.Background(), refresh)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh token: %w", err)
 	}
 
-	var resp PaginatedResponse[Vulnerability]
-	if err := c.doRequest("GET", path, nil, &resp); err != nil {
-		return nil, err
+	// Save the new tokens
+	tokens, _ := auth.LoadTokens()
+	tokens.AccessToken = newTokens.Token
+	tokens.RefreshToken = newTokens.RefreshToken
+	tokens.TokenExpiry = newTokens.TokenExpiry
+	tokens.RefreshTokenExpiry = newTokens.RefreshTokenExpiry
+	if err := auth.SaveTokens(tokens); err != nil {
+		return "", err
 	}
-
-	return &resp, nil
+	return newTokens.Token, nil
 }
@@ -0,0 +1,201 @@
+// Package attest produces and verifies in-toto attestations over analysis
+// results, wrapped in DSSE envelopes signed with ed25519 or ECDSA keys.
+package attest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoValidSignature is returned when no signature of an envelope verifies
+var ErrNoValidSignature = errors.New("no valid signature")
+
+// Envelope is a DSSE envelope
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a DSSE signature
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// PAE returns the DSSE pre-authentication encoding signed for a payload
+func PAE(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+// Sign wraps payload in an envelope signed by key
+func Sign(payloadType string, payload []byte, key crypto.Signer) (*Envelope, error) {
+	sig, err := signMessage(key, PAE(payloadType, payload))
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := KeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks that a signature of env was made by pub and returns the
+// decoded payload
+func (env *Envelope) Verify(pub crypto.PublicKey) ([]byte, error) {
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	msg := PAE(env.PayloadType, payload)
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		if verifyMessage(pub, msg, sig) {
+			return payload, nil
+		}
+	}
+	return nil, ErrNoValidSignature
+}
+
+func signMessage(key crypto.Signer, msg []byte) ([]byte, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, msg), nil
+	case *ecdsa.PrivateKey:
+		digest, err := ecdsaDigest(k.Curve, msg)
+		if err != nil {
+			return nil, err
+		}
+		return ecdsa.SignASN1(rand.Reader, k, digest)
+	}
+	return nil, fmt.Errorf("unsupported key type %T: use an ed25519 or ECDSA key", key)
+}
+
+func verifyMessage(pub crypto.PublicKey, msg, sig []byte) bool {
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, msg, sig)
+	case *ecdsa.PublicKey:
+		digest, err := ecdsaDigest(k.Curve, msg)
+		if err != nil {
+			return false
+		}
+		return ecdsa.VerifyASN1(k, digest, sig)
+	}
+	return false
+}
+
+// ecdsaDigest hashes msg with the hash matching the curve size
+func ecdsaDigest(curve elliptic.Curve, msg []byte) ([]byte, error) {
+	switch curve {
+	case elliptic.P256():
+		sum := sha256.Sum256(msg)
+		return sum[:], nil
+	case elliptic.P384():
+		sum := sha512.Sum384(msg)
+		return sum[:], nil
+	case elliptic.P521():
+		sum := sha512.Sum512(msg)
+		return sum[:], nil
+	}
+	return nil, fmt.Errorf("unsupported ECDSA curve %s", curve.Params().Name)
+}
+
+// KeyID identifies a public key by the SHA-256 of its PKIX encoding
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadPrivateKey reads an ed25519 or ECDSA private key from a PEM file in
+// PKCS #8 or SEC 1 form
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key in %s: %w", path, err)
+		}
+		switch k := key.(type) {
+		case ed25519.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
+		}
+		return nil, fmt.Errorf("unsupported key type %T in %s: use an ed25519 or ECDSA key", key, path)
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key in %s: %w", path, err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("%s holds a %s, not a private key", path, block.Type)
+}
+
+// LoadPublicKey reads an ed25519 or ECDSA public key from a PEM file. A
+// private key file is accepted too and its public half returned.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type != "PUBLIC KEY" {
+		key, err := LoadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		return key.Public(), nil
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key in %s: %w", path, err)
+	}
+	switch pub.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return pub, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T in %s: use an ed25519 or ECDSA key", pub, path)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
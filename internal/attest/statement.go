@@ -0,0 +1,256 @@
+package attest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codeclarity.io/internal/bundle"
+	"codeclarity.io/internal/suppress"
+	"codeclarity.io/pkg/codeclarity"
+)
+
+const (
+	// PayloadType is the DSSE payload type of in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+	// StatementType is the in-toto statement version written
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType identifies CodeClarity scan result predicates
+	PredicateType = "https://codeclarity.io/attestation/scan-result/v1"
+)
+
+// Statement is an in-toto statement about a scanned source
+type Statement struct {
+	Type          string        `json:"_type"`
+	Subject       []Subject     `json:"subject"`
+	PredicateType string        `json:"predicateType"`
+	Predicate     ScanPredicate `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// ScanPredicate records the outcome of an analysis
+type ScanPredicate struct {
+	Scanner         Scanner           `json:"scanner"`
+	Analysis        AnalysisInfo      `json:"analysis"`
+	Analyzer        AnalyzerInfo      `json:"analyzer"`
+	Vulnerabilities VulnerabilityInfo `json:"vulnerabilities"`
+	SBOM            SBOMInfo          `json:"sbom"`
+	ProducedAt      time.Time         `json:"produced_at"`
+}
+
+// Scanner names the tool producing the attestation
+type Scanner struct {
+	Name string `json:"name"`
+}
+
+// AnalysisInfo identifies the attested analysis
+type AnalysisInfo struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+	ProjectID      string     `json:"project_id"`
+	Workspace      string     `json:"workspace"`
+	Branch         string     `json:"branch,omitempty"`
+	CommitHash     string     `json:"commit_hash,omitempty"`
+	Status         string     `json:"status"`
+	CreatedOn      time.Time  `json:"created_on"`
+	EndedOn        *time.Time `json:"ended_on,omitempty"`
+}
+
+// AnalyzerInfo identifies the analyzer that produced the results
+type AnalyzerInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// VulnerabilityInfo holds vulnerability counts and the digest of the full
+// vulnerability list
+type VulnerabilityInfo struct {
+	suppress.Counts
+	Digest map[string]string `json:"digest"`
+}
+
+// SBOMInfo holds dependency counts and the digest of the dependency list
+type SBOMInfo struct {
+	Dependencies int               `json:"dependencies"`
+	Direct       int               `json:"direct"`
+	Transitive   int               `json:"transitive"`
+	Digest       map[string]string `json:"digest"`
+}
+
+// NewStatement builds a statement over the results in b. The subject is the
+// scanned repository at the analyzed commit, or the vulnerability list when
+// the commit is unknown.
+func NewStatement(b *bundle.Bundle, analyzer *codeclarity.Analyzer, scanner string) (*Statement, error) {
+	if b.SBOM == nil {
+		return nil, errors.New("the SBOM of the analysis is not available")
+	}
+
+	vulnDigest, err := VulnerabilitiesDigest(b.Vulnerabilities)
+	if err != nil {
+		return nil, err
+	}
+	sbomDigest, err := SBOMDigest(b.SBOM)
+	if err != nil {
+		return nil, err
+	}
+
+	sbom := SBOMInfo{Dependencies: len(b.SBOM), Digest: sbomDigest}
+	for _, d := range b.SBOM {
+		if d.Direct {
+			sbom.Direct++
+		}
+		if d.Transitive {
+			sbom.Transitive++
+		}
+	}
+
+	a := b.Analysis
+	predicate := ScanPredicate{
+		Scanner: Scanner{Name: scanner},
+		Analysis: AnalysisInfo{
+			ID:             a.ID,
+			OrganizationID: b.Manifest.OrgID,
+			ProjectID:      b.Manifest.ProjectID,
+			Workspace:      b.Manifest.Workspace,
+			Branch:         a.Branch,
+			CommitHash:     a.CommitHash,
+			Status:         string(a.Status),
+			CreatedOn:      a.CreatedOn,
+			EndedOn:        a.EndedOn,
+		},
+		Analyzer: AnalyzerInfo{ID: a.AnalyzerID},
+		Vulnerabilities: VulnerabilityInfo{
+			Counts: suppress.Count(b.Vulnerabilities),
+			Digest: vulnDigest,
+		},
+		SBOM:       sbom,
+		ProducedAt: time.Now().UTC(),
+	}
+	if analyzer != nil {
+		predicate.Analyzer.Name = analyzer.Name
+	}
+
+	subject := Subject{Name: "codeclarity:analysis/" + a.ID, Digest: vulnDigest}
+	if a.CommitHash != "" {
+		name := b.Manifest.ProjectID
+		if b.Project != nil && b.Project.URL != "" {
+			name = b.Project.URL
+		}
+		subject = Subject{Name: name, Digest: map[string]string{"gitCommit": a.CommitHash}}
+	}
+
+	return &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{subject},
+		PredicateType: PredicateType,
+		Predicate:     predicate,
+	}, nil
+}
+
+// ParseStatement decodes a verified payload, rejecting other statement and
+// predicate types
+func ParseStatement(payload []byte) (*Statement, error) {
+	var s Statement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, fmt.Errorf("invalid statement: %w", err)
+	}
+	if s.Type != StatementType {
+		return nil, fmt.Errorf("unsupported statement type %q", s.Type)
+	}
+	if s.PredicateType != PredicateType {
+		return nil, fmt.Errorf("unsupported predicate type %q", s.PredicateType)
+	}
+	return &s, nil
+}
+
+// Check compares the predicate with the results in b and describes every
+// difference
+func (p *ScanPredicate) Check(b *bundle.Bundle) ([]string, error) {
+	var problems []string
+	mismatch := func(what string, want, got interface{}) {
+		if want != got {
+			problems = append(problems, fmt.Sprintf("%s: attested %v, found %v", what, want, got))
+		}
+	}
+
+	mismatch("analysis", p.Analysis.ID, b.Analysis.ID)
+	mismatch("commit", p.Analysis.CommitHash, b.Analysis.CommitHash)
+	mismatch("analyzer", p.Analyzer.ID, b.Analysis.AnalyzerID)
+
+	vulnDigest, err := VulnerabilitiesDigest(b.Vulnerabilities)
+	if err != nil {
+		return nil, err
+	}
+	mismatch("vulnerabilities digest", formatDigest(p.Vulnerabilities.Digest), formatDigest(vulnDigest))
+
+	counts := suppress.Count(b.Vulnerabilities)
+	mismatch("vulnerability count", p.Vulnerabilities.Total, counts.Total)
+	mismatch("critical vulnerabilities", p.Vulnerabilities.Critical, counts.Critical)
+	mismatch("high vulnerabilities", p.Vulnerabilities.High, counts.High)
+	mismatch("medium vulnerabilities", p.Vulnerabilities.Medium, counts.Medium)
+	mismatch("low vulnerabilities", p.Vulnerabilities.Low, counts.Low)
+
+	if b.SBOM == nil {
+		problems = append(problems, "sbom: not available")
+	} else {
+		sbomDigest, err := SBOMDigest(b.SBOM)
+		if err != nil {
+			return nil, err
+		}
+		mismatch("sbom digest", formatDigest(p.SBOM.Digest), formatDigest(sbomDigest))
+	}
+	return problems, nil
+}
+
+// VulnerabilitiesDigest hashes the JSON encoding of vulns ordered by ID, so
+// the digest does not depend on the order the API returned them in
+func VulnerabilitiesDigest(vulns []codeclarity.Vulnerability) (map[string]string, error) {
+	sorted := append([]codeclarity.Vulnerability{}, vulns...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return digest(sorted)
+}
+
+// SBOMDigest hashes the JSON encoding of the SBOM dependencies ordered by
+// name and version, so the digest does not depend on the API's order
+func SBOMDigest(deps []codeclarity.Dependency) (map[string]string, error) {
+	sorted := append([]codeclarity.Dependency{}, deps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return digest(sorted)
+}
+
+func digest(v interface{}) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return map[string]string{"sha256": hex.EncodeToString(sum[:])}, nil
+}
+
+func formatDigest(d map[string]string) string {
+	algs := make([]string, 0, len(d))
+	for alg := range d {
+		algs = append(algs, alg)
+	}
+	sort.Strings(algs)
+	parts := make([]string, len(algs))
+	for i, alg := range algs {
+		parts[i] = alg + ":" + d[alg]
+	}
+	return strings.Join(parts, ",")
+}
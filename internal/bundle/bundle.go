@@ -0,0 +1,299 @@
+// Package bundle reads and writes portable archives of analysis results.
+//
+// A bundle is a gzip-compressed tar file holding one JSON document per part
+// of the result and a manifest.json listing every file with its size and
+// SHA-256 checksum. Reading a bundle verifies the checksums.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+const (
+	// FormatVersion is the bundle layout version written to manifests
+	FormatVersion = 1
+	// ManifestName is the name of the manifest inside the archive
+	ManifestName = "manifest.json"
+
+	// maxFileSize bounds each decompressed file when reading
+	maxFileSize = 512 << 20
+)
+
+// File names of the parts of a bundle
+const (
+	ProjectFile            = "project.json"
+	AnalysisFile           = "analysis.json"
+	VulnerabilityStatsFile = "vulnerability_stats.json"
+	SBOMStatsFile          = "sbom_stats.json"
+	LicenseStatsFile       = "license_stats.json"
+	VulnerabilitiesFile    = "vulnerabilities.json"
+	SBOMFile               = "sbom.json"
+	LicensesFile           = "licenses.json"
+)
+
+// Manifest describes a bundle and checksums its files
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	Generator     string    `json:"generator"`
+	CreatedAt     time.Time `json:"created_at"`
+	OrgID         string    `json:"org_id"`
+	ProjectID     string    `json:"project_id"`
+	AnalysisID    string    `json:"analysis_id"`
+	Workspace     string    `json:"workspace"`
+	Files         []File    `json:"files"`
+}
+
+// File is a manifest entry
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Bundle is the content of a result archive. Optional parts are nil when
+// the server could not provide them.
+type Bundle struct {
+	Manifest           Manifest                        `json:"manifest"`
+	Project            *codeclarity.Project            `json:"project,omitempty"`
+	Analysis           codeclarity.Analysis            `json:"analysis"`
+	VulnerabilityStats *codeclarity.VulnerabilityStats `json:"vulnerability_stats,omitempty"`
+	SBOMStats          *codeclarity.SBOMStats          `json:"sbom_stats,omitempty"`
+	LicenseStats       *codeclarity.LicenseStats       `json:"license_stats,omitempty"`
+	Vulnerabilities    []codeclarity.Vulnerability     `json:"vulnerabilities"`
+	SBOM               []codeclarity.Dependency        `json:"sbom,omitempty"`
+	Licenses           []codeclarity.License           `json:"licenses,omitempty"`
+}
+
+// parts lists the bundle's documents by file name, omitting missing ones
+func (b *Bundle) parts() map[string]interface{} {
+	parts := map[string]interface{}{
+		AnalysisFile:        b.Analysis,
+		VulnerabilitiesFile: b.Vulnerabilities,
+	}
+	if b.Project != nil {
+		parts[ProjectFile] = b.Project
+	}
+	if b.VulnerabilityStats != nil {
+		parts[VulnerabilityStatsFile] = b.VulnerabilityStats
+	}
+	if b.SBOMStats != nil {
+		parts[SBOMStatsFile] = b.SBOMStats
+	}
+	if b.LicenseStats != nil {
+		parts[LicenseStatsFile] = b.LicenseStats
+	}
+	if b.SBOM != nil {
+		parts[SBOMFile] = b.SBOM
+	}
+	if b.Licenses != nil {
+		parts[LicensesFile] = b.Licenses
+	}
+	return parts
+}
+
+// Write archives b to w, filling in the manifest's file list. The manifest
+// is written last so it can checksum every other file.
+func Write(w io.Writer, b *Bundle) error {
+	if b.Vulnerabilities == nil {
+		b.Vulnerabilities = []codeclarity.Vulnerability{}
+	}
+	b.Manifest.FormatVersion = FormatVersion
+	if b.Manifest.CreatedAt.IsZero() {
+		b.Manifest.CreatedAt = time.Now().UTC()
+	}
+
+	parts := b.parts()
+	names := make([]string, 0, len(parts))
+	for name := range parts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	b.Manifest.Files = nil
+	for _, name := range names {
+		data, err := json.MarshalIndent(parts[name], "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		b.Manifest.Files = append(b.Manifest.Files, File{
+			Name:   name,
+			Size:   int64(len(data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		if err := writeFile(tw, name, data, b.Manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(tw, ManifestName, manifest, b.Manifest.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// Read extracts a bundle from r and verifies every file against the manifest
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("corrupt bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %q in bundle", hdr.Name)
+		}
+		if hdr.Name != path.Base(hdr.Name) {
+			return nil, fmt.Errorf("unexpected path %q in bundle", hdr.Name)
+		}
+		if _, dup := files[hdr.Name]; dup {
+			return nil, fmt.Errorf("duplicate entry %q in bundle", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("corrupt bundle: %w", err)
+		}
+		if len(data) > maxFileSize {
+			return nil, fmt.Errorf("%s exceeds %d bytes", hdr.Name, maxFileSize)
+		}
+		files[hdr.Name] = data
+	}
+
+	b := &Bundle{}
+	manifest, ok := files[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", ManifestName)
+	}
+	if err := json.Unmarshal(manifest, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestName, err)
+	}
+	if b.Manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported bundle format version %d", b.Manifest.FormatVersion)
+	}
+	if err := verify(&b.Manifest, files); err != nil {
+		return nil, err
+	}
+
+	decode := func(name string, v interface{}) (bool, error) {
+		data, ok := files[name]
+		if !ok {
+			return false, nil
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return false, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return true, nil
+	}
+
+	if ok, err := decode(AnalysisFile, &b.Analysis); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("bundle has no %s", AnalysisFile)
+	}
+	if _, err := decode(VulnerabilitiesFile, &b.Vulnerabilities); err != nil {
+		return nil, err
+	}
+
+	var project codeclarity.Project
+	if ok, err := decode(ProjectFile, &project); err != nil {
+		return nil, err
+	} else if ok {
+		b.Project = &project
+	}
+	var vulnStats codeclarity.VulnerabilityStats
+	if ok, err := decode(VulnerabilityStatsFile, &vulnStats); err != nil {
+		return nil, err
+	} else if ok {
+		b.VulnerabilityStats = &vulnStats
+	}
+	var sbomStats codeclarity.SBOMStats
+	if ok, err := decode(SBOMStatsFile, &sbomStats); err != nil {
+		return nil, err
+	} else if ok {
+		b.SBOMStats = &sbomStats
+	}
+	var licenseStats codeclarity.LicenseStats
+	if ok, err := decode(LicenseStatsFile, &licenseStats); err != nil {
+		return nil, err
+	} else if ok {
+		b.LicenseStats = &licenseStats
+	}
+	if _, err := decode(SBOMFile, &b.SBOM); err != nil {
+		return nil, err
+	}
+	if _, err := decode(LicensesFile, &b.Licenses); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// verify checks that the archive holds exactly the files in the manifest
+// with matching sizes and checksums
+func verify(m *Manifest, files map[string][]byte) error {
+	listed := map[string]bool{ManifestName: true}
+	for _, f := range m.Files {
+		data, ok := files[f.Name]
+		if !ok {
+			return fmt.Errorf("bundle is missing %s", f.Name)
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != f.Size || hex.EncodeToString(sum[:]) != f.SHA256 {
+			return fmt.Errorf("checksum mismatch for %s: bundle was modified", f.Name)
+		}
+		listed[f.Name] = true
+	}
+	for name := range files {
+		if !listed[name] {
+			return fmt.Errorf("%s is not listed in the manifest", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,419 @@
+// Package cache stores API responses on disk and revalidates them with
+// conditional requests.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"codeclarity.io/internal/config"
+)
+
+const (
+	// DirName is the cache directory inside the config directory
+	DirName = "cache"
+	// DefaultTTL is how long responses without validators are served
+	// without contacting the API
+	DefaultTTL = 5 * time.Minute
+
+	globalOrg    = "_global"
+	finishedFile = "finished-analyses.json"
+	filePerms    = 0600
+)
+
+// finishedStatuses are analysis states whose results can no longer change
+var finishedStatuses = map[string]bool{
+	"success":   true,
+	"completed": true,
+	"finished":  true,
+	"failed":    true,
+}
+
+// Entry is a cached response
+type Entry struct {
+	URL          string      `json:"url"`
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	// Immutable entries belong to finished analyses and are never revalidated
+	Immutable bool `json:"immutable,omitempty"`
+}
+
+// fresh reports whether the entry can be served without contacting the API
+func (e *Entry) fresh(now time.Time) bool {
+	return e.Immutable || now.Before(e.ExpiresAt)
+}
+
+// response builds an HTTP response from the entry
+func (e *Entry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("X-Codeclarity-Cache", "hit")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Dir returns the cache directory
+func Dir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, DirName), nil
+}
+
+// Transport is an http.RoundTripper caching GET responses in Dir.
+// Entries are grouped by organization and keyed by URL.
+type Transport struct {
+	Dir  string
+	TTL  time.Duration
+	Next http.RoundTripper
+
+	mu       sync.Mutex
+	finished map[string]bool
+	now      func() time.Time
+}
+
+// NewTransport returns a caching transport storing entries under dir
+func NewTransport(dir string, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &Transport{
+		Dir:      dir,
+		TTL:      DefaultTTL,
+		Next:     next,
+		finished: map[string]bool{},
+		now:      time.Now,
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, finishedFile)); err == nil {
+		var ids []string
+		if json.Unmarshal(data, &ids) == nil {
+			for _, id := range ids {
+				t.finished[id] = true
+			}
+		}
+	}
+	return t
+}
+
+// RoundTrip serves fresh entries from disk, revalidates stale ones and
+// stores successful responses
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/auth/") {
+		return t.Next.RoundTrip(req)
+	}
+	if req.Method != http.MethodGet {
+		resp, err := t.Next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 400 {
+			// Writes may change any listing of the organization
+			os.RemoveAll(filepath.Dir(t.entryPath(req)))
+		}
+		return resp, err
+	}
+
+	path := t.entryPath(req)
+	cached := t.load(path)
+	now := t.now()
+	if cached != nil && cached.fresh(now) {
+		return cached.response(req), nil
+	}
+
+	if cached != nil {
+		// Clone so the caller's request is left untouched
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		cached.StoredAt = now
+		cached.ExpiresAt = now.Add(t.ttl(cached.Body))
+		t.store(path, cached)
+		return cached.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &Entry{
+		URL:          req.URL.String(),
+		Status:       resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     now,
+		ExpiresAt:    now.Add(t.ttl(body)),
+		Immutable:    t.immutable(req.URL.Path),
+	}
+	if strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store") {
+		return resp, nil
+	}
+	t.store(path, entry)
+	return resp, nil
+}
+
+// ttl returns how long a response may be served without revalidation.
+// Responses describing unfinished analyses or projects being downloaded are
+// always revalidated so watch loops see progress; finished analyses are
+// remembered so their results become immutable.
+func (t *Transport) ttl(body []byte) time.Duration {
+	finished, unfinished := scanAnalyses(body)
+	if len(finished) > 0 {
+		t.markFinished(finished)
+	}
+	if unfinished {
+		return 0
+	}
+	return t.TTL
+}
+
+// immutable reports whether path is a result of a finished analysis
+func (t *Transport) immutable(path string) bool {
+	_, rest, ok := strings.Cut(path, "/analysis/")
+	if !ok {
+		return false
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finished[id]
+}
+
+func (t *Transport) markFinished(ids []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	changed := false
+	for _, id := range ids {
+		if !t.finished[id] {
+			t.finished[id] = true
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	all := make([]string, 0, len(t.finished))
+	for id := range t.finished {
+		all = append(all, id)
+	}
+	if data, err := json.Marshal(all); err == nil && os.MkdirAll(t.Dir, 0700) == nil {
+		os.WriteFile(filepath.Join(t.Dir, finishedFile), data, filePerms)
+	}
+}
+
+// entryPath returns the file caching req, under a directory per organization
+func (t *Transport) entryPath(req *http.Request) string {
+	org := globalOrg
+	if _, rest, ok := strings.Cut(req.URL.Path, "/org/"); ok {
+		org, _, _ = strings.Cut(rest, "/")
+	}
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return filepath.Join(t.Dir, org, hex.EncodeToString(sum[:])+".json")
+}
+
+func (t *Transport) load(path string) *Entry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil
+	}
+	return &e
+}
+
+// store writes an entry, ignoring failures: the cache is best effort
+func (t *Transport) store(path string, e *Entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePerms); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
+
+// analysisRecord picks the fields identifying an analysis or a project in a
+// response
+type analysisRecord struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	AnalyzerID string `json:"analyzerId"`
+	Downloaded *bool  `json:"downloaded"`
+	Invalid    bool   `json:"invalid"`
+}
+
+// scanAnalyses finds analyses in a response body, returning the IDs of
+// finished ones and whether any is still running or any project is still
+// being downloaded
+func scanAnalyses(body []byte) (finished []string, unfinished bool) {
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Data == nil {
+		return nil, false
+	}
+
+	var records []analysisRecord
+	data := bytes.TrimSpace(envelope.Data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		json.Unmarshal(data, &records)
+	default:
+		// Single entities may be nested in a second "data" object
+		var nested struct {
+			Data *analysisRecord `json:"data"`
+		}
+		if json.Unmarshal(data, &nested) == nil && nested.Data != nil {
+			records = append(records, *nested.Data)
+		} else {
+			var r analysisRecord
+			if json.Unmarshal(data, &r) == nil {
+				records = append(records, r)
+			}
+		}
+	}
+
+	for _, r := range records {
+		if r.ID != "" && r.Downloaded != nil && !*r.Downloaded && !r.Invalid {
+			unfinished = true
+			continue
+		}
+		if r.ID == "" || r.AnalyzerID == "" {
+			continue
+		}
+		if finishedStatuses[r.Status] {
+			finished = append(finished, r.ID)
+		} else {
+			unfinished = true
+		}
+	}
+	return finished, unfinished
+}
+
+// Stats summarizes the cache contents
+type Stats struct {
+	Dir       string         `json:"dir"`
+	Entries   int            `json:"entries"`
+	Bytes     int64          `json:"bytes"`
+	Immutable int            `json:"immutable"`
+	Fresh     int            `json:"fresh"`
+	ByOrg     map[string]int `json:"by_org"`
+	Oldest    *time.Time     `json:"oldest,omitempty"`
+}
+
+// ReadStats walks dir and summarizes its entries
+func ReadStats(dir string) (*Stats, error) {
+	stats := &Stats{Dir: dir, ByOrg: map[string]int{}}
+	now := time.Now()
+
+	orgs, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return nil, err
+	}
+	for _, org := range orgs {
+		if !org.IsDir() {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(dir, org.Name(), "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			var e Entry
+			if json.Unmarshal(data, &e) != nil {
+				continue
+			}
+
+			stats.Entries++
+			stats.Bytes += info.Size()
+			stats.ByOrg[org.Name()]++
+			if e.Immutable {
+				stats.Immutable++
+			}
+			if e.fresh(now) {
+				stats.Fresh++
+			}
+			if stats.Oldest == nil || e.StoredAt.Before(*stats.Oldest) {
+				stored := e.StoredAt
+				stats.Oldest = &stored
+			}
+		}
+	}
+	return stats, nil
+}
+
+// Clear removes cached entries. With an organization ID only that
+// organization's entries are removed.
+func Clear(dir, orgID string) error {
+	if orgID != "" {
+		if orgID == "." || orgID == ".." || strings.ContainsAny(orgID, `/\`) {
+			return fmt.Errorf("invalid organization ID %q", orgID)
+		}
+		return os.RemoveAll(filepath.Join(dir, orgID))
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,285 @@
+// Package clitest runs CLI commands in-process against a fake CodeClarity
+// API, for end-to-end tests of the cmd packages.
+//
+//	env := clitest.New(t, codeclaritytest.DefaultFixtures())
+//	res := env.Run("project", "list")
+//	if res.Err != nil || !strings.Contains(res.Stdout, "example/web") { ... }
+//
+// Commands share process-wide state such as os.Stdout and flag values, so
+// tests using this package must not run in parallel.
+package clitest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"codeclarity.io/cmd"
+	"codeclarity.io/internal/api"
+	"codeclarity.io/internal/auth"
+	"codeclarity.io/internal/config"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Env is a CLI environment pointed at a fake server
+type Env struct {
+	Server *codeclaritytest.Server
+	// Dir is the home and working directory commands run in
+	Dir string
+}
+
+// Result is the outcome of running a command
+type Result struct {
+	Stdout string
+	Stderr string
+	Err    error
+}
+
+// Output returns stdout followed by stderr
+func (r Result) Output() string {
+	return r.Stdout + r.Stderr
+}
+
+// New starts a fake server seeded with fixtures and a temporary home
+// directory whose config targets it, authenticated through
+// CODECLARITY_API_KEY. Both are removed when the test ends.
+func New(t *testing.T, fixtures codeclaritytest.Fixtures) *Env {
+	t.Helper()
+
+	srv := codeclaritytest.NewServer(fixtures)
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv(auth.APIKeyEnvVar, srv.Token)
+	t.Setenv("NO_COLOR", "1")
+	t.Chdir(dir)
+
+	cfg := config.DefaultConfig()
+	cfg.APIBaseURL = srv.BaseURL()
+	cfg.DefaultOrgID = codeclaritytest.OrgID
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	return &Env{Server: srv, Dir: dir}
+}
+
+// Path returns the path of name inside the environment's directory
+func (e *Env) Path(name string) string {
+	return filepath.Join(e.Dir, name)
+}
+
+// WriteFile creates name inside the environment's directory
+func (e *Env) WriteFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := e.Path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Run executes the CLI with args and captures what it prints. Runs in the
+// same environment share its response cache and synced results.
+func (e *Env) Run(args ...string) Result {
+	root := cmd.Root()
+	resetFlags(root)
+	api.Reset()
+	root.SetArgs(args)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	outR, outW, _ := os.Pipe()
+	errR, errW, _ := os.Pipe()
+	os.Stdout, os.Stderr = outW, errW
+
+	outC, errC := drain(outR), drain(errR)
+	err := root.ExecuteContext(context.Background())
+
+	os.Stdout, os.Stderr = stdout, stderr
+	outW.Close()
+	errW.Close()
+	return Result{Stdout: <-outC, Stderr: <-errC, Err: err}
+}
+
+// Expect runs a command, typically from a Case.Check, and fails the test
+// unless it succeeds and prints want
+func (e *Env) Expect(t *testing.T, want string, args ...string) {
+	t.Helper()
+	res := e.Run(args...)
+	if res.Err != nil || !strings.Contains(res.Stdout, want) {
+		t.Fatalf("codeclarity %s did not print %q:\n%s", strings.Join(args, " "), want, res.Output())
+	}
+}
+
+// drain reads r in the background so writers never block on a full pipe
+func drain(r *os.File) <-chan string {
+	c := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		r.Close()
+		c <- buf.String()
+	}()
+	return c
+}
+
+// resetFlags restores every flag of the command tree to its default, since
+// cobra keeps flag values from one execution to the next
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			s.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// Case is one row of a table-driven command test
+type Case struct {
+	Name string
+	Args []string
+	// Setup prepares the server, e.g. by injecting faults, or local files
+	Setup func(t *testing.T, env *Env)
+	// Stdout and Stderr list text the command must print. A case without
+	// Stderr expects nothing on stderr.
+	Stdout []string
+	Stderr []string
+	// Fail expects the command to return an error, i.e. exit non-zero
+	Fail bool
+	// Check makes further assertions on the outcome
+	Check func(t *testing.T, env *Env, res Result)
+}
+
+// RunCases runs every case as a subtest in a fresh environment seeded with
+// codeclaritytest.DefaultFixtures
+func RunCases(t *testing.T, cases []Case) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			env := New(t, codeclaritytest.DefaultFixtures())
+			if tc.Setup != nil {
+				tc.Setup(t, env)
+			}
+
+			res := env.Run(tc.Args...)
+			if res.Err != nil && !tc.Fail {
+				t.Fatalf("codeclarity %s: %v\n%s", strings.Join(tc.Args, " "), res.Err, res.Output())
+			}
+			if res.Err == nil && tc.Fail {
+				t.Errorf("codeclarity %s succeeded, want an error", strings.Join(tc.Args, " "))
+			}
+			for _, want := range tc.Stdout {
+				if !strings.Contains(res.Stdout, want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, res.Stdout)
+				}
+			}
+			for _, want := range tc.Stderr {
+				if !strings.Contains(res.Stderr, want) {
+					t.Errorf("stderr does not contain %q:\n%s", want, res.Stderr)
+				}
+			}
+			if len(tc.Stderr) == 0 && res.Stderr != "" {
+				t.Errorf("unexpected stderr:\n%s", res.Stderr)
+			}
+			if tc.Check != nil {
+				tc.Check(t, env, res)
+			}
+		})
+	}
+}
+
+// FaultCases returns cases running a read-only command while the endpoints
+// under path are slow, reject the token, rate limit or fail. failure is the
+// message the command prints when its request fails. Rate limits and server
+// errors that clear up are retried transparently.
+func FaultCases(args []string, path, failure string) []Case {
+	cases := []Case{
+		{
+			Name:  "latency",
+			Args:  args,
+			Setup: Inject(codeclaritytest.Latency(path, 100*time.Millisecond)),
+		},
+		{
+			Name:   "unauthorized",
+			Args:   args,
+			Setup:  Inject(codeclaritytest.Unauthorized(path)),
+			Stderr: []string{failure, "Unauthorized"},
+		},
+		{
+			Name:  "rate limited once",
+			Args:  args,
+			Setup: Inject(codeclaritytest.RateLimited(path, 1, 0)),
+			Check: expectRetry(path),
+		},
+		{
+			Name:   "rate limited beyond max wait",
+			Args:   args,
+			Setup:  Inject(codeclaritytest.RateLimited(path, 0, 3600)),
+			Stderr: []string{failure, "TooManyRequests"},
+		},
+		{
+			Name:  "server error once",
+			Args:  args,
+			Setup: Inject(codeclaritytest.ServerError(path, http.StatusServiceUnavailable, 1)),
+			Check: expectRetry(path),
+		},
+		{
+			Name:   "server error",
+			Args:   args,
+			Setup:  Inject(codeclaritytest.ServerError(path, http.StatusInternalServerError, 0)),
+			Stderr: []string{failure, "InternalServerError"},
+		},
+	}
+	for i := range cases {
+		cases[i].Name = strings.Join(args, " ") + ": " + cases[i].Name
+	}
+	return cases
+}
+
+// Inject returns a Case.Setup injecting f into the server
+func Inject(f codeclaritytest.Fault) func(*testing.T, *Env) {
+	return func(t *testing.T, env *Env) {
+		env.Server.Inject(f)
+	}
+}
+
+// expectRetry checks that a request under path was sent again
+func expectRetry(path string) func(*testing.T, *Env, Result) {
+	return func(t *testing.T, env *Env, res Result) {
+		t.Helper()
+		if n := env.Count("", path); n < 2 {
+			t.Errorf("server received %d requests under %s, want a retry", n, path)
+		}
+	}
+}
+
+// Count returns how many requests with method, or any method when empty,
+// the server received under path, relative to the API base path
+func (e *Env) Count(method, path string) int {
+	n := 0
+	for _, r := range e.Server.Requests() {
+		if (method == "" || r.Method == method) && strings.HasPrefix(r.Path, codeclaritytest.BasePath+path) {
+			n++
+		}
+	}
+	return n
+}
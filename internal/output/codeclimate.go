@@ -0,0 +1,145 @@
+package output
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// CodeClimateOptions configures the Code Climate formatter
+type CodeClimateOptions struct {
+	// LockFile overrides the file issues are attributed to
+	LockFile string
+}
+
+// CodeClimateIssue is an issue in the Code Climate JSON format, as consumed by
+// GitLab code quality reports
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Content     *CodeClimateContent `json:"content,omitempty"`
+	Categories  []string            `json:"categories"`
+	Location    CodeClimateLocation `json:"location"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+}
+
+// CodeClimateContent holds the markdown body of an issue
+type CodeClimateContent struct {
+	Body string `json:"body"`
+}
+
+// CodeClimateLocation identifies the file an issue refers to
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+// CodeClimateLines is the line range of an issue
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// codeClimateSeverity maps a severity class to the Code Climate severity enum
+func codeClimateSeverity(class string) string {
+	switch strings.ToLower(class) {
+	case "critical":
+		return "blocker"
+	case "high":
+		return "critical"
+	case "medium":
+		return "major"
+	case "low":
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+// BuildCodeClimate converts vulnerabilities into Code Climate issues, with one
+// issue per affected dependency. A dependency version reported more than once
+// for the same vulnerability, as happens across workspaces, yields one issue.
+func BuildCodeClimate(vulns []codeclarity.Vulnerability, opts CodeClimateOptions) []CodeClimateIssue {
+	issues := []CodeClimateIssue{}
+	seen := make(map[string]bool)
+	for _, v := range vulns {
+		for _, a := range affectedOrUnknown(v) {
+			sum := md5.Sum([]byte(strings.Join([]string{v.ID, a.AffectedDependency, a.AffectedVersion}, "\x00")))
+			fingerprint := hex.EncodeToString(sum[:])
+			if seen[fingerprint] {
+				continue
+			}
+			seen[fingerprint] = true
+
+			file := opts.LockFile
+			if file == "" {
+				file = LockFileFor(a.AffectedDependency)
+			}
+
+			body := v.Description
+			if v.Severity.Vector != "" {
+				body += fmt.Sprintf("\n\nCVSS %.1f (%s)", v.Severity.Severity, v.Severity.Vector)
+			}
+
+			description := fmt.Sprintf("%s: %s@%s is vulnerable (%s)", v.ID, a.AffectedDependency, a.AffectedVersion, firstLine(v.Description))
+			if a.AffectedDependency == "" {
+				description = fmt.Sprintf("%s: affected dependency unknown (%s)", v.ID, firstLine(v.Description))
+			}
+
+			issues = append(issues, CodeClimateIssue{
+				Type:        "issue",
+				CheckName:   "codeclarity/" + v.ID,
+				Description: description,
+				Content:     &CodeClimateContent{Body: body},
+				Categories:  []string{"Security"},
+				Location: CodeClimateLocation{
+					Path:  file,
+					Lines: CodeClimateLines{Begin: 1, End: 1},
+				},
+				Severity:    codeClimateSeverity(v.Severity.SeverityClass),
+				Fingerprint: fingerprint,
+			})
+		}
+	}
+	return issues
+}
+
+// ValidateCodeClimate checks issues against the constraints of the Code Climate spec
+func ValidateCodeClimate(issues []CodeClimateIssue) error {
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.Type != "issue" {
+			return fmt.Errorf("codeclimate: invalid issue type %q", issue.Type)
+		}
+		if issue.CheckName == "" || issue.Description == "" || issue.Location.Path == "" {
+			return fmt.Errorf("codeclimate: issue %s is missing required fields", issue.Fingerprint)
+		}
+		switch issue.Severity {
+		case "info", "minor", "major", "critical", "blocker":
+		default:
+			return fmt.Errorf("codeclimate: issue %s has invalid severity %q", issue.Fingerprint, issue.Severity)
+		}
+		if seen[issue.Fingerprint] {
+			return fmt.Errorf("codeclimate: duplicate fingerprint %s", issue.Fingerprint)
+		}
+		seen[issue.Fingerprint] = true
+	}
+	return nil
+}
+
+// PrintCodeClimate outputs vulnerabilities as a Code Climate JSON report
+func (f *Formatter) PrintCodeClimate(vulns []codeclarity.Vulnerability, opts CodeClimateOptions) error {
+	issues := BuildCodeClimate(vulns, opts)
+	if err := ValidateCodeClimate(issues); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f.writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
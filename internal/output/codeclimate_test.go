@@ -0,0 +1,67 @@
+package output
+
+import (
+	"testing"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func TestBuildCodeClimate(t *testing.T) {
+	minimist := testVulnerability("CVE-2021-44906", "minimist", "1.2.5", "CRITICAL")
+	// The same dependency version found in two workspaces
+	twice := minimist
+	twice.Affected = append(append([]codeclarity.AffectedVuln{}, minimist.Affected...), minimist.Affected...)
+
+	issues := BuildCodeClimate([]codeclarity.Vulnerability{
+		twice,
+		minimist,
+		testVulnerability("CVE-2022-24999", "qs", "6.5.2", "HIGH"),
+		testVulnerability("CODECLARITY-1", "", "", "LOW"),
+	}, CodeClimateOptions{})
+
+	if err := ValidateCodeClimate(issues); err != nil {
+		t.Fatalf("ValidateCodeClimate() = %v", err)
+	}
+	tests := []struct {
+		check, severity, description string
+	}{
+		{"codeclarity/CVE-2021-44906", "blocker", "CVE-2021-44906: minimist@1.2.5 is vulnerable (CVE-2021-44906 description)"},
+		{"codeclarity/CVE-2022-24999", "critical", "CVE-2022-24999: qs@6.5.2 is vulnerable (CVE-2022-24999 description)"},
+		{"codeclarity/CODECLARITY-1", "minor", "CODECLARITY-1: affected dependency unknown (CODECLARITY-1 description)"},
+	}
+	if len(issues) != len(tests) {
+		t.Fatalf("got %d issues, want %d", len(issues), len(tests))
+	}
+	for i, tt := range tests {
+		got := issues[i]
+		if got.CheckName != tt.check || got.Severity != tt.severity || got.Description != tt.description {
+			t.Errorf("issue %d = %s %s %q, want %s %s %q", i, got.CheckName, got.Severity, got.Description, tt.check, tt.severity, tt.description)
+		}
+		if got.Location.Path != "package-lock.json" || got.Location.Lines.Begin != 1 {
+			t.Errorf("issue %d: location = %+v", i, got.Location)
+		}
+	}
+}
+
+func TestValidateCodeClimate(t *testing.T) {
+	valid := func() []CodeClimateIssue {
+		return BuildCodeClimate([]codeclarity.Vulnerability{testVulnerability("CVE-2022-24999", "qs", "6.5.2", "HIGH")}, CodeClimateOptions{})
+	}
+	tests := []struct {
+		name   string
+		modify func(issues []CodeClimateIssue) []CodeClimateIssue
+	}{
+		{"wrong type", func(i []CodeClimateIssue) []CodeClimateIssue { i[0].Type = "finding"; return i }},
+		{"no check name", func(i []CodeClimateIssue) []CodeClimateIssue { i[0].CheckName = ""; return i }},
+		{"no path", func(i []CodeClimateIssue) []CodeClimateIssue { i[0].Location.Path = ""; return i }},
+		{"bad severity", func(i []CodeClimateIssue) []CodeClimateIssue { i[0].Severity = "high"; return i }},
+		{"duplicate fingerprint", func(i []CodeClimateIssue) []CodeClimateIssue { return append(i, i[0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCodeClimate(tt.modify(valid())); err == nil {
+				t.Error("ValidateCodeClimate() = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// Color modes accepted by --color
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// SetColorMode decides whether output is colored. In auto mode colors are
+// disabled when NO_COLOR is set, TERM is dumb or stdout is not a terminal.
+func SetColorMode(mode string) error {
+	switch strings.ToLower(mode) {
+	case "", ColorAuto:
+		color.NoColor = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" ||
+			!term.IsTerminal(int(os.Stdout.Fd()))
+	case ColorAlways:
+		color.NoColor = false
+	case ColorNever:
+		color.NoColor = true
+	default:
+		return fmt.Errorf("invalid color mode %q (use auto, always or never)", mode)
+	}
+	return nil
+}
+
+// ColorEnabled reports whether colored output is enabled
+func ColorEnabled() bool {
+	return !color.NoColor
+}
+
+// TerminalWidth returns the width of the terminal stdout is attached to,
+// falling back to $COLUMNS, or 0 when unknown
+func TerminalWidth() int {
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
+
+// ColorBySeverity colors text according to a severity class
+func ColorBySeverity(severity, text string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return color.New(color.FgRed, color.Bold).Sprint(text)
+	case "high":
+		return color.RedString("%s", text)
+	case "medium":
+		return color.YellowString("%s", text)
+	case "low":
+		return color.BlueString("%s", text)
+	default:
+		return text
+	}
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestSetColorMode(t *testing.T) {
+	saved := color.NoColor
+	t.Cleanup(func() { color.NoColor = saved })
+
+	tests := []struct {
+		mode    string
+		noColor string
+		want    bool
+	}{
+		{ColorAlways, "", true},
+		{ColorAlways, "1", true},
+		{ColorNever, "", false},
+		{"NEVER", "", false},
+		{ColorAuto, "1", false},
+		// Test output is not a terminal
+		{ColorAuto, "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		if err := SetColorMode(tt.mode); err != nil {
+			t.Fatalf("SetColorMode(%q) = %v", tt.mode, err)
+		}
+		if got := ColorEnabled(); got != tt.want {
+			t.Errorf("SetColorMode(%q) with NO_COLOR=%q: ColorEnabled() = %v, want %v", tt.mode, tt.noColor, got, tt.want)
+		}
+	}
+
+	if err := SetColorMode("sometimes"); err == nil {
+		t.Error("SetColorMode(sometimes) = nil, want an error")
+	}
+}
+
+func TestColumnColors(t *testing.T) {
+	saved := color.NoColor
+	t.Cleanup(func() { color.NoColor = saved })
+
+	columns := []Column{{Header: "ID"}, {Header: "Severity", Color: SeverityColor}}
+	rows := [][]string{{"CVE-2021-44906", "CRITICAL"}}
+	tests := []struct {
+		mode   string
+		format Format
+		want   bool
+	}{
+		{ColorAlways, FormatTable, true},
+		{ColorNever, FormatTable, false},
+		// Structured formats carry raw values whatever the mode
+		{ColorAlways, FormatCSV, false},
+		{ColorAlways, FormatTSV, false},
+		{ColorAlways, FormatJSON, false},
+	}
+	for _, tt := range tests {
+		if err := SetColorMode(tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		f := &Formatter{format: tt.format, writer: &buf}
+		if err := f.PrintColumns(columns, rows); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(buf.String(), "\x1b["); got != tt.want {
+			t.Errorf("%s with --color %s: colored = %v, want %v:\n%q", tt.format, tt.mode, got, tt.want, buf.String())
+		}
+		if !strings.Contains(buf.String(), "CRITICAL") {
+			t.Errorf("%s: severity missing:\n%s", tt.format, buf.String())
+		}
+	}
+}
+
+func TestStatusColor(t *testing.T) {
+	saved := color.NoColor
+	t.Cleanup(func() { color.NoColor = saved })
+	color.NoColor = false
+
+	tests := []struct {
+		status string
+		want   color.Attribute
+	}{
+		{"success", color.FgGreen},
+		{"ok", color.FgGreen},
+		{"failed", color.FgRed},
+		{"error", color.FgRed},
+		{"started", color.FgYellow},
+		{"stale", color.FgYellow},
+		{"never scanned", color.FgYellow},
+		{"downloaded", color.FgGreen},
+		{"invalid", color.FgRed},
+		{"downloading", color.FgYellow},
+	}
+	for _, tt := range tests {
+		if got, want := StatusColor(tt.status), color.New(tt.want).Sprint(tt.status); got != want {
+			t.Errorf("StatusColor(%s) = %q, want %q", tt.status, got, want)
+		}
+	}
+	if got := StatusColor("unknown"); got != "unknown" {
+		t.Errorf("StatusColor(unknown) = %q, want it uncolored", got)
+	}
+}
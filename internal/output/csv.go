@@ -0,0 +1,346 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// listSeparator joins the values of flattened list fields in a single cell
+const listSeparator = "; "
+
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes terminal color codes from a cell
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+// flatRecord is a single row of flattened fields in discovery order
+type flatRecord struct {
+	keys   []string
+	values map[string][]string
+}
+
+func newFlatRecord() *flatRecord {
+	return &flatRecord{values: make(map[string][]string)}
+}
+
+// register adds a column without a value
+func (r *flatRecord) register(key string) {
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+		r.values[key] = nil
+	}
+}
+
+// add appends a value to a column
+func (r *flatRecord) add(key, value string) {
+	r.register(key)
+	r.values[key] = append(r.values[key], value)
+}
+
+// get returns the cell value for a column
+func (r *flatRecord) get(key string) string {
+	values := r.values[key]
+	for _, v := range values {
+		if v != "" {
+			return strings.Join(values, listSeparator)
+		}
+	}
+	return ""
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldName returns the column name for a struct field, or "" if it is skipped
+func fieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name := field.Name
+	if tag := field.Tag.Get("json"); tag != "" {
+		tagName, _, _ := strings.Cut(tag, ",")
+		if tagName == "-" {
+			return ""
+		}
+		if tagName != "" {
+			name = tagName
+		}
+	}
+	return name
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// registerType adds the columns of a type without values, so empty lists still
+// produce stable headers
+func registerType(t reflect.Type, prefix string, r *flatRecord) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		r.register(prefix)
+	case t.Kind() == reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if name := fieldName(t.Field(i)); name != "" {
+				registerType(t.Field(i).Type, joinKey(prefix, name), r)
+			}
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		registerType(t.Elem(), prefix, r)
+	case t.Kind() == reflect.Map || t.Kind() == reflect.Interface:
+		// Columns depend on the values
+		if prefix != "" && t.Kind() == reflect.Interface {
+			r.register(prefix)
+		}
+	default:
+		r.register(prefix)
+	}
+}
+
+// flattenValue adds the fields of a value to a record, nesting struct and map
+// fields with dotted names and joining list elements in one cell
+func flattenValue(v reflect.Value, prefix string, r *flatRecord) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			registerType(v.Type(), prefix, r)
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			r.add(prefix, "")
+		} else {
+			r.add(prefix, t.Format(time.RFC3339))
+		}
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name := fieldName(t.Field(i)); name != "" {
+				flattenValue(v.Field(i), joinKey(prefix, name), r)
+			}
+		}
+	case v.Kind() == reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			flattenValue(v.MapIndex(k), joinKey(prefix, fmt.Sprint(k.Interface())), r)
+		}
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		if v.Len() == 0 {
+			registerType(v.Type().Elem(), prefix, r)
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			flattenValue(v.Index(i), prefix, r)
+		}
+	default:
+		r.add(prefix, scalarString(v))
+	}
+}
+
+// scalarString formats a scalar value for a cell
+func scalarString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// records returns the rows represented by data: the elements of a list, the
+// "data" field of a paginated response, or the value itself
+func records(data interface{}) []reflect.Value {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Struct {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if fieldName(t.Field(i)) == "data" && t.Field(i).Type.Kind() == reflect.Slice {
+				v = v.Field(i)
+				break
+			}
+		}
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		rows := make([]reflect.Value, v.Len())
+		for i := range rows {
+			rows[i] = v.Index(i)
+		}
+		return rows
+	}
+	return []reflect.Value{v}
+}
+
+// Flatten converts structured data into a header row and string rows, with
+// nested fields as dotted columns
+func Flatten(data interface{}) ([]string, [][]string) {
+	var headers []string
+	seen := make(map[string]bool)
+	var flat []*flatRecord
+
+	for _, rec := range records(data) {
+		r := newFlatRecord()
+		flattenValue(rec, "", r)
+		for _, key := range r.keys {
+			if !seen[key] {
+				seen[key] = true
+				headers = append(headers, key)
+			}
+		}
+		flat = append(flat, r)
+	}
+
+	rows := make([][]string, len(flat))
+	for i, r := range flat {
+		row := make([]string, len(headers))
+		for j, key := range headers {
+			row[j] = r.get(key)
+		}
+		rows[i] = row
+	}
+	return headers, rows
+}
+
+// columnIndexes resolves requested column names to header indexes, in the
+// requested order. Names match headers case-insensitively; a name matching no
+// header selects every nested column below it (e.g. "Severity" selects
+// "Severity.SeverityClass").
+func columnIndexes(headers []string, columns []string) ([]int, error) {
+	var indexes []int
+	for _, col := range columns {
+		col = strings.TrimSpace(col)
+		found := false
+		for i, h := range headers {
+			if strings.EqualFold(h, col) {
+				indexes = append(indexes, i)
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		for i, h := range headers {
+			if len(h) > len(col) && strings.EqualFold(h[:len(col)+1], col+".") {
+				indexes = append(indexes, i)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", col, strings.Join(headers, ", "))
+		}
+	}
+	return indexes, nil
+}
+
+// project keeps the cells at the given indexes of every row
+func project(rows [][]string, indexes []int) [][]string {
+	out := make([][]string, len(rows))
+	for r, row := range rows {
+		out[r] = make([]string, len(indexes))
+		for i, idx := range indexes {
+			if idx < len(row) {
+				out[r][i] = row[idx]
+			}
+		}
+	}
+	return out
+}
+
+// neutralizeFormula prevents spreadsheets from evaluating cells as formulas
+func neutralizeFormula(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
+
+// escapeTSV escapes characters that would break a tab-separated row
+func escapeTSV(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// printDelimited writes headers and rows as CSV or TSV
+func (f *Formatter) printDelimited(headers []string, rows [][]string) error {
+	if f.options.NoHeaders {
+		headers = nil
+	}
+
+	if f.format == FormatTSV {
+		write := func(cells []string) error {
+			escaped := make([]string, len(cells))
+			for i, c := range cells {
+				escaped[i] = escapeTSV(neutralizeFormula(stripANSI(c)))
+			}
+			_, err := fmt.Fprintln(f.writer, strings.Join(escaped, "\t"))
+			return err
+		}
+		if headers != nil {
+			if err := write(headers); err != nil {
+				return err
+			}
+		}
+		for _, row := range rows {
+			if err := write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := csv.NewWriter(f.writer)
+	if headers != nil {
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = neutralizeFormula(stripANSI(c))
+		}
+		if err := w.Write(cells); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
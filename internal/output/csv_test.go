@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func TestNeutralizeFormula(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"lodash", "lodash"},
+		{"", ""},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tindented", "'\tindented"},
+		{"\rreturn", "'\rreturn"},
+		// Plain numbers stay numbers
+		{"-1.5", "-1.5"},
+		{"+7", "+7"},
+		{"7.5", "7.5"},
+	}
+	for _, tt := range tests {
+		if got := neutralizeFormula(tt.in); got != tt.want {
+			t.Errorf("neutralizeFormula(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeTSV(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"a\tb", `a\tb`},
+		{"line\nbreak", `line\nbreak`},
+		{"cr\r\nlf", `cr\r\nlf`},
+		{`back\slash`, `back\\slash`},
+		{"\\t literal", `\\t literal`},
+	}
+	for _, tt := range tests {
+		if got := escapeTSV(tt.in); got != tt.want {
+			t.Errorf("escapeTSV(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPrintDelimited(t *testing.T) {
+	page := codeclarity.PaginatedResponse[codeclarity.Vulnerability]{
+		Data: []codeclarity.Vulnerability{
+			{
+				ID:          "CVE-2021-44906",
+				Severity:    codeclarity.Severity{Severity: 9.8, SeverityClass: "CRITICAL"},
+				Description: "=cmd\tinjection,\n\"quoted\"",
+				Affected: []codeclarity.AffectedVuln{
+					{AffectedDependency: "minimist", AffectedVersion: "1.2.5"},
+					{AffectedDependency: "minimist", AffectedVersion: "0.0.8"},
+				},
+			},
+		},
+	}
+	opts := Options{Columns: []string{"Id", "Severity.SeverityClass", "Description", "Affected.AffectedVersion"}}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatCSV, "Id,Severity.SeverityClass,Description,Affected.AffectedVersion\n" +
+			"CVE-2021-44906,CRITICAL,\"'=cmd\tinjection,\n\"\"quoted\"\"\",1.2.5; 0.0.8\n"},
+		{FormatTSV, "Id\tSeverity.SeverityClass\tDescription\tAffected.AffectedVersion\n" +
+			"CVE-2021-44906\tCRITICAL\t'=cmd\\tinjection,\\n\"quoted\"\t1.2.5; 0.0.8\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		f := &Formatter{format: tt.format, writer: &buf, options: opts}
+		if err := f.Print(page); err != nil {
+			t.Fatalf("%s: Print() = %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s output:\n%q\nwant:\n%q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	type record struct {
+		Name    string            `json:"name"`
+		Skipped string            `json:"-"`
+		When    time.Time         `json:"when"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+		Nested  *struct {
+			Count int `json:"count"`
+		} `json:"nested"`
+	}
+	when := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	headers, rows := Flatten([]record{
+		{Name: "a", When: when, Tags: []string{"x", "y"}, Labels: map[string]string{"b": "2", "a": "1"}},
+		{Name: "b"},
+	})
+
+	wantHeaders := []string{"name", "when", "tags", "labels.a", "labels.b", "nested.count"}
+	if len(headers) != len(wantHeaders) {
+		t.Fatalf("headers = %v, want %v", headers, wantHeaders)
+	}
+	for i := range wantHeaders {
+		if headers[i] != wantHeaders[i] {
+			t.Fatalf("headers = %v, want %v", headers, wantHeaders)
+		}
+	}
+	want := [][]string{
+		{"a", "2025-01-02T03:04:05Z", "x; y", "1", "2", ""},
+		{"b", "", "", "", "", ""},
+	}
+	for r := range want {
+		for c := range want[r] {
+			if rows[r][c] != want[r][c] {
+				t.Errorf("row %d column %s = %q, want %q", r, headers[c], rows[r][c], want[r][c])
+			}
+		}
+	}
+}
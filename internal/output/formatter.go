@@ -8,8 +8,6 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
-	"github.com/olekukonko/tablewriter/tw"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,25 +18,93 @@ const (
 	FormatTable Format = "table"
 	FormatJSON  Format = "json"
 	FormatYAML  Format = "yaml"
+	FormatJUnit Format = "junit"
+
+	FormatGitLab      Format = "gitlab"
+	FormatCodeClimate Format = "codeclimate"
+	FormatGitHub      Format = "github"
+
+	FormatCSV Format = "csv"
+	FormatTSV Format = "tsv"
+
+	FormatGoTemplate   Format = "go-template"
+	FormatTemplateFile Format = "template-file"
+	FormatJSONPath     Format = "jsonpath"
+
+	FormatNDJSON Format = "ndjson"
 )
 
+// Options holds formatter settings shared by every command
+type Options struct {
+	// Columns restricts and orders the columns of tabular output
+	Columns []string
+	// Wide shows wide-only columns and disables truncation
+	Wide bool
+	// SortBy sorts rows by a column, descending with a "-" prefix or ":desc" suffix
+	SortBy string
+	// NoHeaders omits the header row of tables and delimited output
+	NoHeaders bool
+}
+
+// colorize reports whether the formatter should emit colors. Structured
+// formats always carry raw values.
+func (f *Formatter) colorize() bool {
+	return f.format == FormatTable && ColorEnabled()
+}
+
+// defaults are applied to every new formatter
+var defaults Options
+
+// SetDefaults sets the options used by formatters created afterwards
+func SetDefaults(opts Options) {
+	defaults = opts
+}
+
 // Formatter handles output formatting
 type Formatter struct {
-	format Format
-	writer io.Writer
+	format   Format
+	template string
+	writer   io.Writer
+	options  Options
 }
 
 // NewFormatter creates a new formatter
 func NewFormatter(format string) *Formatter {
-	f := Format(strings.ToLower(format))
-	if f != FormatTable && f != FormatJSON && f != FormatYAML {
+	f, tmpl := ParseFormat(format)
+	switch f {
+	case FormatTable, FormatJSON, FormatYAML, FormatJUnit, FormatGitLab, FormatCodeClimate, FormatGitHub,
+		FormatCSV, FormatTSV, FormatGoTemplate, FormatTemplateFile, FormatJSONPath, FormatNDJSON:
+	default:
 		f = FormatTable
 	}
 
 	return &Formatter{
-		format: f,
-		writer: os.Stdout,
+		format:   f,
+		template: tmpl,
+		writer:   os.Stdout,
+		options:  defaults,
+	}
+}
+
+// IsStructuredFormat reports whether a format renders data directly with Print
+// rather than as a hand-built table
+func IsStructuredFormat(format string) bool {
+	f, _ := ParseFormat(format)
+	switch f {
+	case FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatGoTemplate, FormatTemplateFile, FormatJSONPath, FormatNDJSON:
+		return true
 	}
+	return false
+}
+
+// IsReportFormat reports whether a format describes a complete result set,
+// so commands should fetch every page instead of a single one
+func IsReportFormat(format string) bool {
+	switch Format(strings.ToLower(format)) {
+	case FormatJUnit, FormatGitLab, FormatCodeClimate, FormatGitHub:
+		return true
+	}
+	return false
 }
 
 // SetWriter sets the output writer
@@ -53,46 +119,46 @@ func (f *Formatter) Print(data interface{}) error {
 		return f.printJSON(data)
 	case FormatYAML:
 		return f.printYAML(data)
+	case FormatCSV, FormatTSV:
+		headers, rows := Flatten(data)
+		headers, rows, err := f.shape(headers, rows)
+		if err != nil {
+			return err
+		}
+		return f.printDelimited(headers, rows)
+	case FormatGoTemplate, FormatTemplateFile:
+		return f.printTemplate(data)
+	case FormatJSONPath:
+		return f.printJSONPath(data)
+	case FormatNDJSON:
+		for _, rec := range records(data) {
+			if err := f.PrintRecord(rec.Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
 	default:
 		return fmt.Errorf("cannot print arbitrary data as table, use PrintTable")
 	}
 }
 
 // PrintTable outputs data as a table
-func (f *Formatter) PrintTable(headers []string, rows [][]string) {
-	if f.format == FormatJSON {
-		f.printTableAsJSON(headers, rows)
-		return
-	}
-	if f.format == FormatYAML {
-		f.printTableAsYAML(headers, rows)
-		return
+func (f *Formatter) PrintTable(headers []string, rows [][]string) error {
+	columns := make([]Column, len(headers))
+	for i, h := range headers {
+		columns[i] = Column{Header: h}
 	}
-
-	table := tablewriter.NewTable(f.writer,
-		tablewriter.WithHeaderAlignment(tw.AlignLeft),
-		tablewriter.WithRowAlignment(tw.AlignLeft),
-	)
-	table.Header(headers)
-	table.Bulk(rows)
-	table.Render()
+	return f.PrintColumns(columns, rows)
 }
 
-func (f *Formatter) printTableAsJSON(headers []string, rows [][]string) {
-	var result []map[string]string
-	for _, row := range rows {
-		item := make(map[string]string)
-		for i, header := range headers {
-			if i < len(row) {
-				item[header] = row[i]
-			}
-		}
-		result = append(result, item)
-	}
-	f.printJSON(result)
+// PrintRecord writes a single record as one line of compact JSON. Used by the
+// ndjson format to stream results as they are fetched.
+func (f *Formatter) PrintRecord(record interface{}) error {
+	return json.NewEncoder(f.writer).Encode(record)
 }
 
-func (f *Formatter) printTableAsYAML(headers []string, rows [][]string) {
+// tableRecords converts table rows to maps keyed by header
+func tableRecords(headers []string, rows [][]string) []map[string]string {
 	var result []map[string]string
 	for _, row := range rows {
 		item := make(map[string]string)
@@ -103,7 +169,7 @@ func (f *Formatter) printTableAsYAML(headers []string, rows [][]string) {
 		}
 		result = append(result, item)
 	}
-	f.printYAML(result)
+	return result
 }
 
 func (f *Formatter) printJSON(data interface{}) error {
@@ -138,6 +204,12 @@ func Warning(format string, args ...interface{}) {
 	fmt.Printf("%s %s\n", yellow("!"), fmt.Sprintf(format, args...))
 }
 
+// Notice prints a warning message to stderr, keeping stdout clean for structured output
+func Notice(format string, args ...interface{}) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Fprintf(os.Stderr, "%s %s\n", yellow("!"), fmt.Sprintf(format, args...))
+}
+
 // Info prints an info message
 func Info(format string, args ...interface{}) {
 	blue := color.New(color.FgBlue).SprintFunc()
@@ -157,11 +229,11 @@ func Dim(text string) string {
 // StatusColor returns colored status text
 func StatusColor(status string) string {
 	switch strings.ToLower(status) {
-	case "success", "completed":
+	case "success", "completed", "ok", "downloaded":
 		return color.GreenString(status)
-	case "failed":
+	case "failed", "error", "invalid":
 		return color.RedString(status)
-	case "started", "triggered", "requested":
+	case "started", "triggered", "requested", "stale", "never scanned", "downloading":
 		return color.YellowString(status)
 	default:
 		return status
@@ -170,16 +242,5 @@ func StatusColor(status string) string {
 
 // SeverityColor returns colored severity text
 func SeverityColor(severity string) string {
-	switch strings.ToLower(severity) {
-	case "critical":
-		return color.New(color.FgRed, color.Bold).Sprint(severity)
-	case "high":
-		return color.RedString(severity)
-	case "medium":
-		return color.YellowString(severity)
-	case "low":
-		return color.BlueString(severity)
-	default:
-		return severity
-	}
+	return ColorBySeverity(severity, severity)
 }
@@ -0,0 +1,158 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+const (
+	// GitHubActionsEnvVar is set to "true" by GitHub Actions runners
+	GitHubActionsEnvVar = "GITHUB_ACTIONS"
+	// GitHubStepSummaryEnvVar points to the job summary file
+	GitHubStepSummaryEnvVar = "GITHUB_STEP_SUMMARY"
+
+	// maxSummaryRows keeps the job summary well below GitHub's size limit
+	maxSummaryRows = 100
+)
+
+// GitHubOptions configures the GitHub Actions formatter
+type GitHubOptions struct {
+	// Title heads the job summary
+	Title string
+	// LockFile overrides the file annotations are attached to
+	LockFile string
+	// SummaryPath overrides $GITHUB_STEP_SUMMARY
+	SummaryPath string
+}
+
+// GitHubActions reports whether the CLI is running inside GitHub Actions
+func GitHubActions() bool {
+	return os.Getenv(GitHubActionsEnvVar) == "true"
+}
+
+// gitHubCommand maps a severity class to a workflow command
+func gitHubCommand(class string) string {
+	switch SeverityRank(class) {
+	case 4, 3:
+		return "error"
+	case 2:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes a workflow command message
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property value
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// PrintGitHub emits one workflow command annotation per affected dependency and
+// appends a markdown summary to the job summary file when available
+func (f *Formatter) PrintGitHub(vulns []codeclarity.Vulnerability, opts GitHubOptions) error {
+	for _, v := range vulns {
+		for _, a := range affectedOrUnknown(v) {
+			file := opts.LockFile
+			if file == "" {
+				file = LockFileFor(a.AffectedDependency)
+			}
+			title := fmt.Sprintf("%s (%s %.1f)", v.ID, v.Severity.SeverityClass, v.Severity.Severity)
+			if a.AffectedDependency != "" {
+				title += fmt.Sprintf(" in %s@%s", a.AffectedDependency, a.AffectedVersion)
+			}
+			if _, err := fmt.Fprintf(f.writer, "::%s file=%s,title=%s::%s\n",
+				gitHubCommand(v.Severity.SeverityClass),
+				escapeGitHubProperty(file),
+				escapeGitHubProperty(title),
+				escapeGitHubData(v.Description)); err != nil {
+				return err
+			}
+		}
+	}
+
+	path := opts.SummaryPath
+	if path == "" {
+		path = os.Getenv(GitHubStepSummaryEnvVar)
+	}
+	if path == "" {
+		return nil
+	}
+
+	summary, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open job summary: %w", err)
+	}
+	defer summary.Close()
+
+	return writeGitHubSummary(summary, vulns, opts)
+}
+
+// writeGitHubSummary writes a markdown overview of the findings
+func writeGitHubSummary(w io.Writer, vulns []codeclarity.Vulnerability, opts GitHubOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = "CodeClarity vulnerabilities"
+	}
+
+	counts := make(map[int]int)
+	for _, v := range vulns {
+		counts[SeverityRank(v.Severity.SeverityClass)]++
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s\n\n", title)
+	if len(vulns) == 0 {
+		sb.WriteString(":white_check_mark: No vulnerabilities found\n\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	}
+
+	sb.WriteString("| Critical | High | Medium | Low | None | Total |\n")
+	sb.WriteString("|---|---|---|---|---|---|\n")
+	fmt.Fprintf(&sb, "| %d | %d | %d | %d | %d | %d |\n\n", counts[4], counts[3], counts[2], counts[1], counts[0], len(vulns))
+
+	sorted := make([]codeclarity.Vulnerability, len(vulns))
+	copy(sorted, vulns)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Severity.Severity > sorted[j].Severity.Severity
+	})
+
+	sb.WriteString("| ID | Severity | CVSS | Package | Version |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	rows, truncated := 0, false
+	for _, v := range sorted {
+		for _, a := range affectedOrUnknown(v) {
+			if rows == maxSummaryRows {
+				truncated = true
+				break
+			}
+			pkg, version := strings.ReplaceAll(a.AffectedDependency, "|", "\\|"), a.AffectedVersion
+			if pkg == "" {
+				pkg, version = "-", "-"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %.1f | %s | %s |\n", v.ID, v.Severity.SeverityClass, v.Severity.Severity, pkg, version)
+			rows++
+		}
+	}
+	if truncated {
+		fmt.Fprintf(&sb, "\n_Only the %d most severe findings are listed._\n", maxSummaryRows)
+	}
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
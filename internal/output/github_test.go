@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func TestGitHubSummaryTruncation(t *testing.T) {
+	tests := []struct {
+		findings  int
+		rows      int
+		truncated bool
+	}{
+		{maxSummaryRows - 1, maxSummaryRows - 1, false},
+		{maxSummaryRows, maxSummaryRows, false},
+		{maxSummaryRows + 1, maxSummaryRows, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.findings), func(t *testing.T) {
+			var vulns []codeclarity.Vulnerability
+			for i := range tt.findings {
+				vulns = append(vulns, testVulnerability(fmt.Sprintf("CVE-2024-%04d", i), "pkg", "1.0.0", "HIGH"))
+			}
+			var sb strings.Builder
+			if err := writeGitHubSummary(&sb, vulns, GitHubOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Count(sb.String(), "| CVE-2024-"); got != tt.rows {
+				t.Errorf("listed %d findings, want %d", got, tt.rows)
+			}
+			if got := strings.Contains(sb.String(), "_Only the"); got != tt.truncated {
+				t.Errorf("truncation note = %v, want %v", got, tt.truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// GitLabSchemaVersion is the GitLab security report schema version reports declare
+const GitLabSchemaVersion = "15.0.7"
+
+// GitLabOptions configures the GitLab dependency scanning formatter
+type GitLabOptions struct {
+	// LockFile overrides the file findings are attributed to
+	LockFile string
+	// ScannerVersion is reported as the analyzer and scanner version
+	ScannerVersion string
+	// StartTime and EndTime bound the scan, defaulting to now
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// GitLabReport is a GitLab dependency scanning report (gl-dependency-scanning-report.json)
+type GitLabReport struct {
+	Version         string                `json:"version"`
+	Vulnerabilities []GitLabVulnerability `json:"vulnerabilities"`
+	Scan            GitLabScan            `json:"scan"`
+}
+
+// GitLabVulnerability is a single finding in a GitLab report
+type GitLabVulnerability struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Severity    string             `json:"severity"`
+	Solution    string             `json:"solution,omitempty"`
+	Identifiers []GitLabIdentifier `json:"identifiers"`
+	Links       []GitLabLink       `json:"links,omitempty"`
+	Location    GitLabLocation     `json:"location"`
+}
+
+// GitLabIdentifier references an external vulnerability identifier
+type GitLabIdentifier struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// GitLabLink is a reference URL for a finding
+type GitLabLink struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url"`
+}
+
+// GitLabLocation identifies the dependency a finding affects
+type GitLabLocation struct {
+	File       string           `json:"file"`
+	Dependency GitLabDependency `json:"dependency"`
+}
+
+// GitLabDependency is an affected package version
+type GitLabDependency struct {
+	Package GitLabPackage `json:"package"`
+	Version string        `json:"version"`
+}
+
+// GitLabPackage names an affected package
+type GitLabPackage struct {
+	Name string `json:"name"`
+}
+
+// GitLabScan describes the scan that produced the report
+type GitLabScan struct {
+	Analyzer  GitLabScanner `json:"analyzer"`
+	Scanner   GitLabScanner `json:"scanner"`
+	Type      string        `json:"type"`
+	StartTime string        `json:"start_time"`
+	EndTime   string        `json:"end_time"`
+	Status    string        `json:"status"`
+}
+
+// GitLabScanner identifies the analyzer or scanner
+type GitLabScanner struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Vendor  GitLabVendor `json:"vendor"`
+}
+
+// GitLabVendor names the scanner vendor
+type GitLabVendor struct {
+	Name string `json:"name"`
+}
+
+// gitLabSeverity maps a severity class to the GitLab severity enum
+func gitLabSeverity(class string) string {
+	switch strings.ToLower(class) {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	case "none":
+		return "Info"
+	default:
+		return "Unknown"
+	}
+}
+
+// unknownDependency stands for the package of a vulnerability reported
+// without affected dependencies
+const unknownDependency = "unknown"
+
+// affectedOrUnknown returns the affected dependencies of a vulnerability, or a
+// single entry without a dependency when none is reported, so that report
+// formats never drop a finding
+func affectedOrUnknown(v codeclarity.Vulnerability) []codeclarity.AffectedVuln {
+	if len(v.Affected) > 0 {
+		return v.Affected
+	}
+	return []codeclarity.AffectedVuln{{VulnerabilityId: v.ID, Severity: v.Severity}}
+}
+
+// LockFileFor guesses the lockfile declaring a package. Composer packages are
+// named "vendor/package" while npm scoped packages start with "@".
+func LockFileFor(pkg string) string {
+	if strings.Contains(pkg, "/") && !strings.HasPrefix(pkg, "@") {
+		return "composer.lock"
+	}
+	return "package-lock.json"
+}
+
+// vulnerabilityIdentifier classifies an advisory identifier
+func vulnerabilityIdentifier(id string) GitLabIdentifier {
+	upper := strings.ToUpper(id)
+	switch {
+	case strings.HasPrefix(upper, "CVE-"):
+		return GitLabIdentifier{Type: "cve", Name: upper, Value: upper, URL: "https://nvd.nist.gov/vuln/detail/" + upper}
+	case strings.HasPrefix(upper, "GHSA-"):
+		return GitLabIdentifier{Type: "ghsa", Name: id, Value: id, URL: "https://github.com/advisories/" + id}
+	default:
+		return GitLabIdentifier{Type: "codeclarity", Name: id, Value: id}
+	}
+}
+
+// findingUUID derives a stable UUID-formatted identifier for a finding
+func findingUUID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// BuildGitLab converts vulnerabilities into a GitLab dependency scanning report,
+// with one finding per affected dependency. A dependency version reported more
+// than once for the same vulnerability, as happens across workspaces, yields
+// one finding.
+func BuildGitLab(vulns []codeclarity.Vulnerability, opts GitLabOptions) GitLabReport {
+	now := time.Now().UTC()
+	if opts.StartTime.IsZero() {
+		opts.StartTime = now
+	}
+	if opts.EndTime.IsZero() {
+		opts.EndTime = now
+	}
+	if opts.ScannerVersion == "" {
+		opts.ScannerVersion = "dev"
+	}
+
+	scanner := GitLabScanner{
+		ID:      "codeclarity",
+		Name:    "CodeClarity",
+		Version: opts.ScannerVersion,
+		Vendor:  GitLabVendor{Name: "CodeClarity"},
+	}
+	report := GitLabReport{
+		Version:         GitLabSchemaVersion,
+		Vulnerabilities: []GitLabVulnerability{},
+		Scan: GitLabScan{
+			Analyzer:  scanner,
+			Scanner:   scanner,
+			Type:      "dependency_scanning",
+			StartTime: opts.StartTime.Format("2006-01-02T15:04:05"),
+			EndTime:   opts.EndTime.Format("2006-01-02T15:04:05"),
+			Status:    "success",
+		},
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range vulns {
+		for _, a := range affectedOrUnknown(v) {
+			findingID := findingUUID(v.ID, a.AffectedDependency, a.AffectedVersion)
+			if seen[findingID] {
+				continue
+			}
+			seen[findingID] = true
+
+			identifiers := []GitLabIdentifier{vulnerabilityIdentifier(v.ID)}
+			if a.VulnerabilityId != "" && !strings.EqualFold(a.VulnerabilityId, v.ID) {
+				identifiers = append(identifiers, vulnerabilityIdentifier(a.VulnerabilityId))
+			}
+
+			var links []GitLabLink
+			for _, id := range identifiers {
+				if id.URL != "" {
+					links = append(links, GitLabLink{Name: id.Name, URL: id.URL})
+				}
+			}
+
+			file := opts.LockFile
+			if file == "" {
+				file = LockFileFor(a.AffectedDependency)
+			}
+
+			name := fmt.Sprintf("%s in %s", v.ID, a.AffectedDependency)
+			solution := fmt.Sprintf("Upgrade %s to a version that is not affected.", a.AffectedDependency)
+			pkg := a.AffectedDependency
+			if pkg == "" {
+				name = v.ID
+				solution = "Upgrade the affected dependency to a version that is not affected."
+				pkg = unknownDependency
+			}
+
+			report.Vulnerabilities = append(report.Vulnerabilities, GitLabVulnerability{
+				ID:          findingID,
+				Name:        name,
+				Description: v.Description,
+				Severity:    gitLabSeverity(v.Severity.SeverityClass),
+				Solution:    solution,
+				Identifiers: identifiers,
+				Links:       links,
+				Location: GitLabLocation{
+					File: file,
+					Dependency: GitLabDependency{
+						Package: GitLabPackage{Name: pkg},
+						Version: a.AffectedVersion,
+					},
+				},
+			})
+		}
+	}
+
+	return report
+}
+
+// Validate checks the fields GitLab needs to display the report's findings and
+// that finding IDs are unique. It guards against building incomplete reports;
+// conformance to the published JSON schema is covered by the package tests.
+func (r *GitLabReport) Validate() error {
+	if r.Version == "" {
+		return fmt.Errorf("gitlab report: missing schema version")
+	}
+	if r.Scan.Type != "dependency_scanning" {
+		return fmt.Errorf("gitlab report: invalid scan type %q", r.Scan.Type)
+	}
+	for _, t := range []string{r.Scan.StartTime, r.Scan.EndTime} {
+		if _, err := time.Parse("2006-01-02T15:04:05", t); err != nil {
+			return fmt.Errorf("gitlab report: invalid scan time %q", t)
+		}
+	}
+	ids := make(map[string]bool)
+	for _, v := range r.Vulnerabilities {
+		if v.ID == "" {
+			return fmt.Errorf("gitlab report: finding %q has no id", v.Name)
+		}
+		if ids[v.ID] {
+			return fmt.Errorf("gitlab report: duplicate finding id %s", v.ID)
+		}
+		ids[v.ID] = true
+		switch v.Severity {
+		case "Info", "Unknown", "Low", "Medium", "High", "Critical":
+		default:
+			return fmt.Errorf("gitlab report: finding %s has invalid severity %q", v.ID, v.Severity)
+		}
+		if len(v.Identifiers) == 0 {
+			return fmt.Errorf("gitlab report: finding %s has no identifiers", v.ID)
+		}
+		for _, id := range v.Identifiers {
+			if id.Type == "" || id.Name == "" || id.Value == "" {
+				return fmt.Errorf("gitlab report: finding %s has an incomplete identifier", v.ID)
+			}
+		}
+		if v.Location.File == "" || v.Location.Dependency.Package.Name == "" {
+			return fmt.Errorf("gitlab report: finding %s has an incomplete location", v.ID)
+		}
+	}
+	return nil
+}
+
+// PrintGitLab outputs vulnerabilities as a GitLab dependency scanning report
+func (f *Formatter) PrintGitLab(vulns []codeclarity.Vulnerability, opts GitLabOptions) error {
+	report := BuildGitLab(vulns, opts)
+	if err := report.Validate(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f.writer)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
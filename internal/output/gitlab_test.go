@@ -0,0 +1,132 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func testVulnerability(id, pkg, version, class string) codeclarity.Vulnerability {
+	severity := codeclarity.Severity{Severity: 7.5, SeverityClass: class}
+	v := codeclarity.Vulnerability{ID: id, Severity: severity, Description: id + " description"}
+	if pkg != "" {
+		v.Affected = []codeclarity.AffectedVuln{{
+			AffectedDependency: pkg,
+			AffectedVersion:    version,
+			VulnerabilityId:    id,
+			Severity:           severity,
+		}}
+	}
+	return v
+}
+
+func TestBuildGitLab(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	minimist := testVulnerability("CVE-2021-44906", "minimist", "1.2.5", "CRITICAL")
+	// The same dependency version found in two workspaces
+	twice := minimist
+	twice.Affected = append(append([]codeclarity.AffectedVuln{}, minimist.Affected...), minimist.Affected...)
+	vulns := []codeclarity.Vulnerability{
+		twice,
+		minimist,
+		testVulnerability("GHSA-xxxx-yyyy-zzzz", "acme/http", "2.0.0", "medium"),
+		testVulnerability("CODECLARITY-1", "", "", "none"),
+	}
+	report := BuildGitLab(vulns, GitLabOptions{StartTime: start, EndTime: start.Add(time.Minute)})
+
+	if report.Version != GitLabSchemaVersion {
+		t.Errorf("Version = %q, want %q", report.Version, GitLabSchemaVersion)
+	}
+	if report.Scan.StartTime != "2025-01-01T12:00:00" || report.Scan.EndTime != "2025-01-01T12:01:00" {
+		t.Errorf("scan times = %s - %s", report.Scan.StartTime, report.Scan.EndTime)
+	}
+	if err := report.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name, severity, identifierType, file, pkg string
+	}{
+		{"CVE-2021-44906 in minimist", "Critical", "cve", "package-lock.json", "minimist"},
+		{"GHSA-xxxx-yyyy-zzzz in acme/http", "Medium", "ghsa", "composer.lock", "acme/http"},
+		{"CODECLARITY-1", "Info", "codeclarity", "package-lock.json", unknownDependency},
+	}
+	if len(report.Vulnerabilities) != len(tests) {
+		t.Fatalf("got %d findings, want %d", len(report.Vulnerabilities), len(tests))
+	}
+	for i, tt := range tests {
+		got := report.Vulnerabilities[i]
+		if got.Name != tt.name {
+			t.Errorf("finding %d: Name = %q, want %q", i, got.Name, tt.name)
+		}
+		if got.Severity != tt.severity {
+			t.Errorf("%s: Severity = %q, want %q", tt.name, got.Severity, tt.severity)
+		}
+		if got.Identifiers[0].Type != tt.identifierType {
+			t.Errorf("%s: identifier type = %q, want %q", tt.name, got.Identifiers[0].Type, tt.identifierType)
+		}
+		if got.Location.File != tt.file || got.Location.Dependency.Package.Name != tt.pkg {
+			t.Errorf("%s: location = %+v", tt.name, got.Location)
+		}
+	}
+
+	// Finding IDs are stable across runs
+	again := BuildGitLab(vulns, GitLabOptions{})
+	if again.Vulnerabilities[0].ID != report.Vulnerabilities[0].ID {
+		t.Errorf("finding ID changed between runs")
+	}
+}
+
+func TestBuildGitLabJSON(t *testing.T) {
+	report := BuildGitLab(nil, GitLabOptions{})
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	// An empty report still lists its findings as an array
+	if vulns, ok := doc["vulnerabilities"].([]any); !ok || len(vulns) != 0 {
+		t.Errorf("vulnerabilities = %v, want []", doc["vulnerabilities"])
+	}
+	scan := doc["scan"].(map[string]any)
+	for _, key := range []string{"analyzer", "scanner", "type", "start_time", "end_time", "status"} {
+		if _, ok := scan[key]; !ok {
+			t.Errorf("scan is missing %q", key)
+		}
+	}
+}
+
+func TestGitLabValidate(t *testing.T) {
+	valid := func() GitLabReport {
+		return BuildGitLab([]codeclarity.Vulnerability{testVulnerability("CVE-2022-24999", "qs", "6.5.2", "HIGH")}, GitLabOptions{})
+	}
+	tests := []struct {
+		name   string
+		modify func(r *GitLabReport)
+	}{
+		{"missing version", func(r *GitLabReport) { r.Version = "" }},
+		{"wrong scan type", func(r *GitLabReport) { r.Scan.Type = "sast" }},
+		{"bad start time", func(r *GitLabReport) { r.Scan.StartTime = "yesterday" }},
+		{"bad severity", func(r *GitLabReport) { r.Vulnerabilities[0].Severity = "high" }},
+		{"no identifiers", func(r *GitLabReport) { r.Vulnerabilities[0].Identifiers = nil }},
+		{"incomplete identifier", func(r *GitLabReport) { r.Vulnerabilities[0].Identifiers[0].Value = "" }},
+		{"no file", func(r *GitLabReport) { r.Vulnerabilities[0].Location.File = "" }},
+		{"no package", func(r *GitLabReport) { r.Vulnerabilities[0].Location.Dependency.Package.Name = "" }},
+		{"no id", func(r *GitLabReport) { r.Vulnerabilities[0].ID = "" }},
+		{"duplicate id", func(r *GitLabReport) { r.Vulnerabilities = append(r.Vulnerabilities, r.Vulnerabilities[0]) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+			if err := r.Validate(); err == nil {
+				t.Error("Validate() = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,605 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled kubectl-style JSONPath template such as
+// {range .data[*]}{.id}{"\t"}{.name}{"\n"}{end}
+type JSONPath struct {
+	nodes []jpNode
+}
+
+type jpNode interface{}
+
+// jpText is literal template text
+type jpText struct {
+	text string
+}
+
+// jpRange repeats its body for every result of a path
+type jpRange struct {
+	path jpPath
+	body []jpNode
+}
+
+// jpPath is a sequence of segments evaluated from the root ($) or current (@) element
+type jpPath struct {
+	root bool
+	segs []jpSegment
+}
+
+type jpSegmentKind int
+
+const (
+	segField jpSegmentKind = iota
+	segRecursive
+	segWildcard
+	segIndex
+	segSlice
+	segFilter
+)
+
+type jpSegment struct {
+	kind       jpSegmentKind
+	name       string
+	index      int
+	start, end *int
+	filter     *jpFilter
+}
+
+// jpFilter is a [?(@.path op value)] expression
+type jpFilter struct {
+	path  jpPath
+	op    string
+	value any
+}
+
+// ParseJSONPath compiles a JSONPath template. A bare path without braces is
+// treated as a single expression.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	root := &jpRange{}
+	stack := []*jpRange{root}
+
+	for len(template) > 0 {
+		open := strings.IndexByte(template, '{')
+		if open == -1 {
+			top := stack[len(stack)-1]
+			top.body = append(top.body, jpText{text: template})
+			break
+		}
+		if open > 0 {
+			top := stack[len(stack)-1]
+			top.body = append(top.body, jpText{text: template[:open]})
+		}
+
+		closeIdx, err := matchingBrace(template, open)
+		if err != nil {
+			return nil, err
+		}
+		expr := strings.TrimSpace(template[open+1 : closeIdx])
+		template = template[closeIdx+1:]
+
+		top := stack[len(stack)-1]
+		switch {
+		case expr == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("jsonpath: {end} without {range}")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			r := &jpRange{path: path}
+			top.body = append(top.body, r)
+			stack = append(stack, r)
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid string literal %s", expr)
+			}
+			top.body = append(top.body, jpText{text: text})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, err
+			}
+			top.body = append(top.body, path)
+		}
+	}
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("jsonpath: {range} without {end}")
+	}
+	return &JSONPath{nodes: root.body}, nil
+}
+
+// matchingBrace finds the brace closing the one at open, skipping quoted strings
+func matchingBrace(s string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed '{'")
+}
+
+// unquote decodes a single or double quoted string literal
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// parsePath compiles a path expression such as .data[?(@.status=="success")].id
+func parsePath(expr string) (jpPath, error) {
+	p := jpPath{}
+	s := expr
+	switch {
+	case strings.HasPrefix(s, "$"):
+		p.root = true
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		s = s[1:]
+	}
+
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := readName(s[2:])
+			if name == "" {
+				return p, fmt.Errorf("jsonpath: expected field name after '..' in %q", expr)
+			}
+			p.segs = append(p.segs, jpSegment{kind: segRecursive, name: name})
+			s = rest
+		case strings.HasPrefix(s, ".*"):
+			p.segs = append(p.segs, jpSegment{kind: segWildcard})
+			s = s[2:]
+		case s[0] == '.':
+			name, rest := readName(s[1:])
+			if name != "" {
+				p.segs = append(p.segs, jpSegment{kind: segField, name: name})
+			}
+			s = rest
+		case s[0] == '[':
+			end, err := matchingBracket(s)
+			if err != nil {
+				return p, fmt.Errorf("jsonpath: %v in %q", err, expr)
+			}
+			seg, err := parseBracket(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return p, fmt.Errorf("jsonpath: %v in %q", err, expr)
+			}
+			p.segs = append(p.segs, seg)
+			s = s[end+1:]
+		default:
+			return p, fmt.Errorf("jsonpath: unexpected %q in %q", s, expr)
+		}
+	}
+	return p, nil
+}
+
+// readName reads a field name up to the next separator
+func readName(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] != '.' && s[i] != '[' {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// matchingBracket finds the bracket closing the one starting s
+func matchingBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed '['")
+}
+
+// parseBracket compiles the content of a [...] segment
+func parseBracket(s string) (jpSegment, error) {
+	switch {
+	case s == "*":
+		return jpSegment{kind: segWildcard}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		filter, err := parseFilter(strings.TrimSpace(s[2 : len(s)-1]))
+		if err != nil {
+			return jpSegment{}, err
+		}
+		return jpSegment{kind: segFilter, filter: filter}, nil
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		name, err := unquote(s)
+		if err != nil {
+			return jpSegment{}, fmt.Errorf("invalid field name %s", s)
+		}
+		return jpSegment{kind: segField, name: name}, nil
+	case strings.Contains(s, ":"):
+		parts := strings.SplitN(s, ":", 3)
+		seg := jpSegment{kind: segSlice}
+		for i, part := range parts[:2] {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jpSegment{}, fmt.Errorf("invalid slice bound %q", part)
+			}
+			if i == 0 {
+				seg.start = &n
+			} else {
+				seg.end = &n
+			}
+		}
+		return seg, nil
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return jpSegment{}, fmt.Errorf("invalid index %q", s)
+		}
+		return jpSegment{kind: segIndex, index: n}, nil
+	}
+}
+
+// parseFilter compiles a filter expression such as @.severity > 7
+func parseFilter(s string) (*jpFilter, error) {
+	if idx, op := filterOperator(s); op != "" {
+		path, err := parsePath(strings.TrimSpace(s[:idx]))
+		if err != nil {
+			return nil, err
+		}
+		literal := strings.TrimSpace(s[idx+len(op):])
+		var value any
+		if strings.HasPrefix(literal, "'") || strings.HasPrefix(literal, `"`) {
+			str, err := unquote(literal)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter literal %s", literal)
+			}
+			value = str
+		} else if err := json.Unmarshal([]byte(literal), &value); err != nil {
+			return nil, fmt.Errorf("invalid filter literal %s", literal)
+		}
+		return &jpFilter{path: path, op: op, value: value}, nil
+	}
+
+	// Existence check
+	path, err := parsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	return &jpFilter{path: path}, nil
+}
+
+// filterOperator finds the first comparison operator outside quoted literals,
+// so that @.name=="a<b" compares against "a<b"
+func filterOperator(s string) (int, string) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+				if strings.HasPrefix(s[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// Execute renders the template against data, which is first converted to its
+// JSON representation so field names match the API's JSON keys
+func (jp *JSONPath) Execute(w io.Writer, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.render(&buf, jp.nodes, doc, doc); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func (jp *JSONPath) render(buf *bytes.Buffer, nodes []jpNode, root, current any) error {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case jpText:
+			buf.WriteString(n.text)
+		case jpPath:
+			results := n.eval(root, current)
+			for i, r := range results {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				if err := writeJSONPathValue(buf, r); err != nil {
+					return err
+				}
+			}
+		case *jpRange:
+			for _, item := range n.path.eval(root, current) {
+				// Ranging over a field holding an array iterates its elements
+				if list, ok := item.([]any); ok && n.path.selectsField() {
+					for _, el := range list {
+						if err := jp.render(buf, n.body, root, el); err != nil {
+							return err
+						}
+					}
+					continue
+				}
+				if err := jp.render(buf, n.body, root, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSONPathValue prints strings and numbers raw and everything else as JSON
+func writeJSONPathValue(buf *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case string:
+		buf.WriteString(val)
+	case json.Number:
+		buf.WriteString(val.String())
+	case nil:
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// selectsField reports whether the path ends with a field lookup
+func (p jpPath) selectsField() bool {
+	if len(p.segs) == 0 {
+		return true
+	}
+	kind := p.segs[len(p.segs)-1].kind
+	return kind == segField || kind == segRecursive
+}
+
+// eval returns every value the path selects
+func (p jpPath) eval(root, current any) []any {
+	values := []any{current}
+	if p.root {
+		values = []any{root}
+	}
+	for _, seg := range p.segs {
+		var next []any
+		for _, v := range values {
+			next = append(next, seg.apply(root, v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (seg jpSegment) apply(root, v any) []any {
+	switch seg.kind {
+	case segField:
+		if obj, ok := v.(map[string]any); ok {
+			if val, ok := obj[seg.name]; ok {
+				return []any{val}
+			}
+		}
+	case segRecursive:
+		return recursiveLookup(v, seg.name)
+	case segWildcard:
+		switch val := v.(type) {
+		case []any:
+			return val
+		case map[string]any:
+			out := make([]any, 0, len(val))
+			for _, key := range sortedKeys(val) {
+				out = append(out, val[key])
+			}
+			return out
+		}
+	case segIndex:
+		if list, ok := v.([]any); ok {
+			idx := seg.index
+			if idx < 0 {
+				idx += len(list)
+			}
+			if idx >= 0 && idx < len(list) {
+				return []any{list[idx]}
+			}
+		}
+	case segSlice:
+		if list, ok := v.([]any); ok {
+			start, end := 0, len(list)
+			if seg.start != nil {
+				start = clampIndex(*seg.start, len(list))
+			}
+			if seg.end != nil {
+				end = clampIndex(*seg.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case segFilter:
+		list, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		var out []any
+		for _, item := range list {
+			if seg.filter.match(root, item) {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// recursiveLookup finds every value stored under name at any depth
+func recursiveLookup(v any, name string) []any {
+	var out []any
+	switch val := v.(type) {
+	case map[string]any:
+		if found, ok := val[name]; ok {
+			out = append(out, found)
+		}
+		for _, key := range sortedKeys(val) {
+			out = append(out, recursiveLookup(val[key], name)...)
+		}
+	case []any:
+		for _, item := range val {
+			out = append(out, recursiveLookup(item, name)...)
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// match evaluates the filter against an element
+func (f *jpFilter) match(root, item any) bool {
+	results := f.path.eval(root, item)
+	if f.op == "" {
+		for _, r := range results {
+			if r != nil && r != false {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range results {
+		if compareJSONPath(r, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareJSONPath compares numbers numerically and everything else as strings
+func compareJSONPath(left any, op string, right any) bool {
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if lok && rok {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+		return false
+	}
+
+	ls, rs := fmt.Sprint(left), fmt.Sprint(right)
+	switch op {
+	case "==":
+		return ls == rs
+	case "!=":
+		return ls != rs
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
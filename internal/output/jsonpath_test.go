@@ -0,0 +1,152 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// kubectlDoc is the document used by the kubectl JSONPath documentation
+const kubectlDoc = `{
+  "kind": "List",
+  "items": [
+    {
+      "kind": "None",
+      "metadata": {"name": "127.0.0.1", "labels": {"kubernetes.io/hostname": "127.0.0.1"}},
+      "status": {"capacity": {"cpu": "4"}, "addresses": [{"type": "LegacyHostIP", "address": "127.0.0.1"}]}
+    },
+    {
+      "kind": "None",
+      "metadata": {"name": "127.0.0.2"},
+      "status": {"capacity": {"cpu": "8"}, "addresses": [{"type": "LegacyHostIP", "address": "127.0.0.2"}, {"type": "another", "address": "127.0.0.3"}]}
+    }
+  ],
+  "users": [
+    {"name": "myself", "user": {}},
+    {"name": "e2e", "user": {"username": "admin", "password": "secret"}}
+  ]
+}`
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name, template, want string
+	}{
+		// Examples from the kubectl JSONPath documentation
+		{"field", "{.kind}", "List"},
+		{"bracket field", "{['kind']}", "List"},
+		{"root", "{$.kind}", "List"},
+		{"recursive descent", "{..name}", "127.0.0.1 127.0.0.2 myself e2e"},
+		{"wildcard", "{.items[*].metadata.name}", "127.0.0.1 127.0.0.2"},
+		{"index", "{.items[0].metadata.name}", "127.0.0.1"},
+		{"negative slice", "{.items[-1:].metadata.name}", "127.0.0.2"},
+		{"slice", "{.users[0:1].name}", "myself"},
+		{"filter", `{.users[?(@.name=="e2e")].user.password}`, "secret"},
+		{"existence filter", "{.users[?(@.user.username)].name}", "e2e"},
+		{"range", "{range .items[*]}[{.metadata.name}, {.status.capacity}] {end}", `[127.0.0.1, {"cpu":"4"}] [127.0.0.2, {"cpu":"8"}] `},
+		{"quoted text", `{range .users[*]}{.name}{"\t"}{end}`, "myself\te2e\t"},
+		{"dotted key", "{.items[0].metadata.labels['kubernetes.io/hostname']}", "127.0.0.1"},
+		{"bare path", ".items[1].status.addresses[1].address", "127.0.0.3"},
+		{"object", "{.users[0]}", `{"name":"myself","user":{}}`},
+		{"missing field", "{.items[0].spec}", ""},
+
+		// Filters comparing numbers and quoted literals
+		{"numeric filter", "{.items[?(@.status.capacity.cpu>'5')].metadata.name}", "127.0.0.2"},
+		{"not equal", `{.users[?(@.name!="e2e")].name}`, "myself"},
+		{"single quotes", "{.users[?(@.name=='myself')].name}", "myself"},
+		{"operator in literal", `{.users[?(@.name!="x==y")].name}`, "myself e2e"},
+		{"equals in literal", `{.users[?(@.name=="a==b")].name}`, ""},
+		{"bracket in literal", `{.users[?(@.name=="e2e]")].name}`, ""},
+		{"brace in literal", `{.users[?(@.name=="}")].name}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%s) = %v", tt.template, err)
+			}
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, json.RawMessage(kubectlDoc)); err != nil {
+				t.Fatalf("Execute() = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("%s = %q, want %q", tt.template, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathNumbers(t *testing.T) {
+	data := map[string]any{"items": []map[string]any{
+		{"id": "a", "score": 9.8},
+		{"id": "b", "score": 7},
+		{"id": "c", "score": 10},
+	}}
+	tests := []struct {
+		template, want string
+	}{
+		{"{.items[?(@.score>=9)].id}", "a c"},
+		{"{.items[?(@.score<9)].id}", "b"},
+		{"{.items[?(@.score==10)].id}", "c"},
+		// Large numbers are printed as they were encoded
+		{"{.items[2].score}", "10"},
+	}
+	for _, tt := range tests {
+		jp, err := ParseJSONPath(tt.template)
+		if err != nil {
+			t.Fatalf("ParseJSONPath(%s) = %v", tt.template, err)
+		}
+		var buf bytes.Buffer
+		if err := jp.Execute(&buf, data); err != nil {
+			t.Fatalf("Execute() = %v", err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.template, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestParseFilterQuotes(t *testing.T) {
+	tests := []struct {
+		expr, op string
+		value    any
+	}{
+		{`@.name=="a<b"`, "==", "a<b"},
+		{`@.name != 'x==y'`, "!=", "x==y"},
+		{`@.name=="say \"<=\""`, "==", `say "<="`},
+		{`@['a<b'] >= 2`, ">=", float64(2)},
+		{`@.name`, "", nil},
+	}
+	for _, tt := range tests {
+		f, err := parseFilter(tt.expr)
+		if err != nil {
+			t.Fatalf("parseFilter(%s) = %v", tt.expr, err)
+		}
+		if f.op != tt.op || f.value != tt.value {
+			t.Errorf("parseFilter(%s) = %s %v, want %s %v", tt.expr, f.op, f.value, tt.op, tt.value)
+		}
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	tests := []struct {
+		name, template string
+	}{
+		{"unclosed brace", "{.kind"},
+		{"unclosed bracket", "{.items[0}"},
+		{"end without range", "{.kind}{end}"},
+		{"range without end", "{range .items[*]}{.kind}"},
+		{"bad string", `{"unterminated}`},
+		{"bad index", "{.items[x]}"},
+		{"bad slice", "{.items[a:b]}"},
+		{"recursive without name", "{..}"},
+		{"bad filter literal", "{.items[?(@.kind==None)]}"},
+		{"bad field", "{kind}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJSONPath(tt.template); err == nil {
+				t.Errorf("ParseJSONPath(%s) succeeded, want an error", tt.template)
+			}
+		})
+	}
+}
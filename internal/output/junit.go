@@ -0,0 +1,193 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// JUnitOptions configures the JUnit formatter
+type JUnitOptions struct {
+	// SuiteName names the generated test suite
+	SuiteName string
+	// FailSeverity is the minimum severity class that fails a test case
+	FailSeverity string
+}
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite groups test cases
+type JUnitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []JUnitProperty `xml:"properties>property,omitempty"`
+	Cases      []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitProperty is a key/value pair attached to a suite
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase represents one affected dependency
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// SeverityRank orders severity classes from none (0) to critical (4)
+func SeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ValidSeverity reports whether a severity threshold name is recognized
+func ValidSeverity(severity string) bool {
+	switch strings.ToLower(severity) {
+	case "critical", "high", "medium", "low", "none":
+		return true
+	}
+	return false
+}
+
+// BuildJUnit converts vulnerabilities into a JUnit report where each affected
+// dependency is a test case failing when it has findings at or above the threshold
+func BuildJUnit(vulns []codeclarity.Vulnerability, opts JUnitOptions) JUnitTestSuites {
+	if opts.SuiteName == "" {
+		opts.SuiteName = "codeclarity"
+	}
+	if opts.FailSeverity == "" {
+		opts.FailSeverity = "high"
+	}
+	threshold := SeverityRank(opts.FailSeverity)
+
+	type dependency struct {
+		name, version string
+		failing       []string
+		passing       []string
+	}
+	deps := make(map[string]*dependency)
+	var keys []string
+
+	for _, v := range vulns {
+		for _, a := range affectedOrUnknown(v) {
+			// Findings without a known dependency get a test case of their own
+			key := v.ID
+			if a.AffectedDependency != "" {
+				key = a.AffectedDependency + "@" + a.AffectedVersion
+			}
+			dep, ok := deps[key]
+			if !ok {
+				dep = &dependency{name: a.AffectedDependency, version: a.AffectedVersion}
+				deps[key] = dep
+				keys = append(keys, key)
+			}
+			line := fmt.Sprintf("%s [%s %.1f] %s", v.ID, v.Severity.SeverityClass, v.Severity.Severity, firstLine(v.Description))
+			if SeverityRank(v.Severity.SeverityClass) >= threshold {
+				dep.failing = append(dep.failing, line)
+			} else {
+				dep.passing = append(dep.passing, line)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	suite := JUnitTestSuite{
+		Name:      opts.SuiteName,
+		Time:      "0",
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05"),
+		Properties: []JUnitProperty{
+			{Name: "fail_severity", Value: strings.ToLower(opts.FailSeverity)},
+		},
+	}
+
+	for _, key := range keys {
+		dep := deps[key]
+		tc := JUnitTestCase{
+			Name:      key,
+			ClassName: opts.SuiteName + ".dependencies",
+			Time:      "0",
+		}
+		if len(dep.failing) > 0 {
+			tc.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("%s has %d vulnerabilities at or above %s severity", key, len(dep.failing), strings.ToLower(opts.FailSeverity)),
+				Type:    "vulnerability",
+				Text:    strings.Join(dep.failing, "\n"),
+			}
+			suite.Failures++
+		}
+		if len(dep.passing) > 0 {
+			tc.SystemOut = "Below threshold:\n" + strings.Join(dep.passing, "\n")
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	return JUnitTestSuites{
+		Name:     opts.SuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []JUnitTestSuite{suite},
+	}
+}
+
+// PrintJUnit outputs vulnerabilities as a JUnit XML report
+func (f *Formatter) PrintJUnit(vulns []codeclarity.Vulnerability, opts JUnitOptions) error {
+	report := BuildJUnit(vulns, opts)
+
+	if _, err := fmt.Fprint(f.writer, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(f.writer)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(f.writer)
+	return err
+}
+
+// firstLine returns the first line of a description
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		return s[:idx]
+	}
+	return s
+}
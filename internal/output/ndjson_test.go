@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// ndjsonLines decodes every line of ndjson output
+func ndjsonLines(t *testing.T, out string) []map[string]any {
+	t.Helper()
+	if !strings.HasSuffix(out, "\n") {
+		t.Fatalf("output does not end with a newline: %q", out)
+	}
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", line, err)
+		}
+		lines = append(lines, rec)
+	}
+	return lines
+}
+
+func TestPrintNDJSON(t *testing.T) {
+	vulns := []codeclarity.Vulnerability{
+		{ID: "CVE-2021-44906", Description: "multi\nline"},
+		{ID: "CVE-2022-24999"},
+	}
+	tests := []struct {
+		name string
+		data any
+		want []string
+	}{
+		{"page", codeclarity.PaginatedResponse[codeclarity.Vulnerability]{Data: vulns, TotalEntries: 2}, []string{"CVE-2021-44906", "CVE-2022-24999"}},
+		{"page pointer", &codeclarity.PaginatedResponse[codeclarity.Vulnerability]{Data: vulns}, []string{"CVE-2021-44906", "CVE-2022-24999"}},
+		{"slice", vulns, []string{"CVE-2021-44906", "CVE-2022-24999"}},
+		{"single record", vulns[1], []string{"CVE-2022-24999"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := &Formatter{format: FormatNDJSON, writer: &buf}
+			if err := f.Print(tt.data); err != nil {
+				t.Fatalf("Print() = %v", err)
+			}
+			lines := ndjsonLines(t, buf.String())
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.want), buf.String())
+			}
+			for i, id := range tt.want {
+				if lines[i]["Id"] != id {
+					t.Errorf("line %d: Id = %v, want %s", i, lines[i]["Id"], id)
+				}
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatNDJSON, writer: &buf}
+	if err := f.Print([]codeclarity.Vulnerability{}); err != nil || buf.Len() != 0 {
+		t.Errorf("Print(empty) = %v, %q, want no output", err, buf.String())
+	}
+}
+
+func TestPrintRecord(t *testing.T) {
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatNDJSON, writer: &buf}
+	for _, id := range []string{"a", "b"} {
+		if err := f.PrintRecord(map[string]string{"id": id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "{\"id\":\"a\"}\n{\"id\":\"b\"}\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestPrintColumnsNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatNDJSON, writer: &buf, options: Options{SortBy: "Name"}}
+	err := f.PrintTable([]string{"Name", "Version"}, [][]string{{"qs", "6.5.2"}, {"minimist", "1.2.5"}})
+	if err != nil {
+		t.Fatalf("PrintTable() = %v", err)
+	}
+	lines := ndjsonLines(t, buf.String())
+	if len(lines) != 2 || lines[0]["Name"] != "minimist" || lines[1]["Version"] != "6.5.2" {
+		t.Errorf("output = %s", buf.String())
+	}
+}
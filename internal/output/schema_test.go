@@ -0,0 +1,340 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// schemaVulnerabilities exercises every branch of the CI formatters: both
+// ecosystems, aliases, every severity, a finding without a dependency and a
+// dependency reported twice
+func schemaVulnerabilities() []codeclarity.Vulnerability {
+	minimist := testVulnerability("CVE-2021-44906", "minimist", "1.2.5", "CRITICAL")
+	minimist.Affected = append(minimist.Affected, minimist.Affected[0])
+	minimist.Affected[1].VulnerabilityId = "GHSA-xvch-5gv4-984h"
+	minimist.Severity.Vector = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+	return []codeclarity.Vulnerability{
+		minimist,
+		minimist,
+		testVulnerability("GHSA-xxxx-yyyy-zzzz", "acme/http", "2.0.0", "medium"),
+		testVulnerability("CVE-2022-24999", "@scope/qs", "6.5.2", "HIGH"),
+		testVulnerability("CVE-2017-16137", "debug", "2.6.8", "LOW"),
+		testVulnerability("CODECLARITY-1", "", "", "none"),
+		testVulnerability("CODECLARITY-2", "ms", "2.0.0", ""),
+	}
+}
+
+func TestGitLabSchema(t *testing.T) {
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatGitLab, writer: &buf}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := f.PrintGitLab(schemaVulnerabilities(), GitLabOptions{StartTime: start, EndTime: start}); err != nil {
+		t.Fatal(err)
+	}
+	validateJSONSchema(t, "dependency-scanning-report-format-15.0.7.json", buf.Bytes())
+}
+
+func TestCodeClimateSchema(t *testing.T) {
+	var buf bytes.Buffer
+	f := &Formatter{format: FormatCodeClimate, writer: &buf}
+	if err := f.PrintCodeClimate(schemaVulnerabilities(), CodeClimateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	validateJSONSchema(t, "codeclimate-report.json", buf.Bytes())
+}
+
+func TestJUnitSchema(t *testing.T) {
+	for _, threshold := range []string{"critical", "low"} {
+		var buf bytes.Buffer
+		f := &Formatter{format: FormatJUnit, writer: &buf}
+		if err := f.PrintJUnit(schemaVulnerabilities(), JUnitOptions{FailSeverity: threshold}); err != nil {
+			t.Fatal(err)
+		}
+		validateXSD(t, "junit-10.xsd", buf.Bytes())
+	}
+}
+
+// The validators must reject what the schemas forbid, or the tests above
+// prove nothing
+func TestSchemaValidatorsReject(t *testing.T) {
+	tests := []struct {
+		schema, doc string
+	}{
+		{"dependency-scanning-report-format-15.0.7.json", `{"version": "15.0.7", "vulnerabilities": []}`},
+		{"dependency-scanning-report-format-15.0.7.json", `{"version": "15", "vulnerabilities": [], "scan": {}}`},
+		{"codeclimate-report.json", `[{"type": "issue", "check_name": "c", "description": "d", "categories": ["Security"], "location": {"path": "p", "lines": {"begin": 1, "end": 1}}, "severity": "high", "fingerprint": "f"}]`},
+		{"codeclimate-report.json", `[{"type": "issue", "check_name": "c", "description": "d", "categories": [], "location": {"path": "p", "lines": {"begin": 1, "end": 1}}, "severity": "info", "fingerprint": "f"}]`},
+		{"junit-10.xsd", `<testsuites><testsuite name="s"></testsuite></testsuites>`},
+		{"junit-10.xsd", `<testsuites><testsuite name="s" tests="1"><testcase name="c" time="soon"/></testsuite></testsuites>`},
+		{"junit-10.xsd", `<testsuites><testcase name="c"/></testsuites>`},
+	}
+	for _, tt := range tests {
+		var err error
+		if strings.HasSuffix(tt.schema, ".xsd") {
+			err = checkXSD(tt.schema, []byte(tt.doc))
+		} else {
+			err = checkJSONSchema(tt.schema, []byte(tt.doc))
+		}
+		if err == nil {
+			t.Errorf("%s accepted %s", tt.schema, tt.doc)
+		}
+	}
+}
+
+func validateJSONSchema(t *testing.T, schema string, doc []byte) {
+	t.Helper()
+	if err := checkJSONSchema(schema, doc); err != nil {
+		t.Errorf("%s: %v\n%s", schema, err, doc)
+	}
+}
+
+func validateXSD(t *testing.T, schema string, doc []byte) {
+	t.Helper()
+	if err := checkXSD(schema, doc); err != nil {
+		t.Errorf("%s: %v\n%s", schema, err, doc)
+	}
+}
+
+// checkJSONSchema validates a document against a schema in testdata
+func checkJSONSchema(schema string, doc []byte) error {
+	data, err := os.ReadFile(filepath.Join("testdata", schema))
+	if err != nil {
+		return err
+	}
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return err
+	}
+	return jsonSchemaCheck(root, root, v, "$")
+}
+
+// jsonSchemaCheck implements the draft-07 keywords used by the testdata schemas
+func jsonSchemaCheck(root, s map[string]any, v any, path string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		target := root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			target, _ = target[part].(map[string]any)
+		}
+		if target == nil {
+			return fmt.Errorf("%s: unresolved $ref %s", path, ref)
+		}
+		return jsonSchemaCheck(root, target, v, path)
+	}
+
+	if typ, ok := s["type"].(string); ok && !jsonSchemaType(typ, v) {
+		return fmt.Errorf("%s: %v is not of type %s", path, v, typ)
+	}
+	if c, ok := s["const"]; ok && v != c {
+		return fmt.Errorf("%s: %v is not %v", path, v, c)
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", path, v, enum)
+	}
+
+	switch v := v.(type) {
+	case string:
+		if n, ok := s["minLength"].(float64); ok && len([]rune(v)) < int(n) {
+			return fmt.Errorf("%s: %q is shorter than %v", path, v, n)
+		}
+		if n, ok := s["maxLength"].(float64); ok && len([]rune(v)) > int(n) {
+			return fmt.Errorf("%s: string is longer than %v", path, n)
+		}
+		if pattern, ok := s["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %s", path, v, pattern)
+		}
+	case float64:
+		if n, ok := s["minimum"].(float64); ok && v < n {
+			return fmt.Errorf("%s: %v is less than %v", path, v, n)
+		}
+	case []any:
+		if n, ok := s["minItems"].(float64); ok && len(v) < int(n) {
+			return fmt.Errorf("%s: fewer than %v items", path, n)
+		}
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := jsonSchemaCheck(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		required, _ := s["required"].([]any)
+		for _, key := range required {
+			if _, ok := v[key.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, key)
+			}
+		}
+		properties, _ := s["properties"].(map[string]any)
+		for key, val := range v {
+			prop, ok := properties[key].(map[string]any)
+			if !ok {
+				if s["additionalProperties"] == false {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+				continue
+			}
+			if err := jsonSchemaCheck(root, prop, val, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonSchemaType(typ string, v any) bool {
+	switch v := v.(type) {
+	case string:
+		return typ == "string"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == float64(int64(v)))
+	case bool:
+		return typ == "boolean"
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	case nil:
+		return typ == "null"
+	}
+	return false
+}
+
+// xsdNode is an element of a parsed XML document or schema
+type xsdNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xsdNode  `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+func (n *xsdNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xsdElement is what a schema allows for one element
+type xsdElement struct {
+	simple   bool
+	mixed    bool
+	attrs    map[string]xsdAttribute
+	children map[string]bool
+}
+
+type xsdAttribute struct {
+	typ      string
+	required bool
+}
+
+// checkXSD validates a document against the global elements of a schema in testdata
+func checkXSD(schema string, doc []byte) error {
+	data, err := os.ReadFile(filepath.Join("testdata", schema))
+	if err != nil {
+		return err
+	}
+	var root xsdNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	patterns := make(map[string]*regexp.Regexp)
+	elements := make(map[string]*xsdElement)
+	for _, n := range root.Children {
+		switch n.XMLName.Local {
+		case "simpleType":
+			for _, r := range n.Children {
+				for _, p := range r.Children {
+					if p.XMLName.Local == "pattern" {
+						patterns[n.attr("name")] = regexp.MustCompile("^(?:" + p.attr("value") + ")$")
+					}
+				}
+			}
+		case "element":
+			el := &xsdElement{attrs: make(map[string]xsdAttribute), children: make(map[string]bool)}
+			el.simple = n.attr("type") != ""
+			for _, ct := range n.Children {
+				el.mixed = ct.attr("mixed") == "true"
+				xsdContent(ct, el)
+			}
+			elements[n.attr("name")] = el
+		}
+	}
+
+	var v xsdNode
+	if err := xml.Unmarshal(doc, &v); err != nil {
+		return err
+	}
+	return xsdCheck(elements, patterns, &v, "/"+v.XMLName.Local)
+}
+
+// xsdContent collects the attributes and child elements of a complex type
+func xsdContent(n xsdNode, el *xsdElement) {
+	for _, c := range n.Children {
+		switch c.XMLName.Local {
+		case "attribute":
+			el.attrs[c.attr("name")] = xsdAttribute{typ: c.attr("type"), required: c.attr("use") == "required"}
+		case "element":
+			el.children[c.attr("ref")] = true
+		case "sequence", "choice", "all":
+			xsdContent(c, el)
+		}
+	}
+}
+
+func xsdCheck(elements map[string]*xsdElement, patterns map[string]*regexp.Regexp, n *xsdNode, path string) error {
+	el, ok := elements[n.XMLName.Local]
+	if !ok {
+		return fmt.Errorf("%s: undeclared element", path)
+	}
+	if el.simple {
+		if len(n.Attrs) > 0 || len(n.Children) > 0 {
+			return fmt.Errorf("%s: simple element has attributes or children", path)
+		}
+		return nil
+	}
+	if !el.mixed && strings.TrimSpace(n.Text) != "" {
+		return fmt.Errorf("%s: unexpected text content", path)
+	}
+	for _, a := range n.Attrs {
+		decl, ok := el.attrs[a.Name.Local]
+		if !ok {
+			return fmt.Errorf("%s: undeclared attribute %s", path, a.Name.Local)
+		}
+		if re, ok := patterns[decl.typ]; ok && !re.MatchString(a.Value) {
+			return fmt.Errorf("%s: attribute %s=%q is not a valid %s", path, a.Name.Local, a.Value, decl.typ)
+		}
+	}
+	for name, decl := range el.attrs {
+		if decl.required && !slices.ContainsFunc(n.Attrs, func(a xml.Attr) bool { return a.Name.Local == name }) {
+			return fmt.Errorf("%s: missing required attribute %s", path, name)
+		}
+	}
+	for i := range n.Children {
+		c := &n.Children[i]
+		if !el.children[c.XMLName.Local] {
+			return fmt.Errorf("%s: unexpected child %s", path, c.XMLName.Local)
+		}
+		if err := xsdCheck(elements, patterns, c, path+"/"+c.XMLName.Local); err != nil {
+			return err
+		}
+	}
+	return nil
+}
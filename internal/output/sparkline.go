@@ -0,0 +1,29 @@
+package output
+
+import "strings"
+
+// sparkTicks are the bar heights of a sparkline, lowest first
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a one-line bar chart scaled between their
+// minimum and maximum
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		tick := 0
+		if hi > lo {
+			tick = (v - lo) * (len(sparkTicks) - 1) / (hi - lo)
+		}
+		b.WriteRune(sparkTicks[tick])
+	}
+	return b.String()
+}
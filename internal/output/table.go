@@ -0,0 +1,259 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/pkg/twwidth"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// Column describes a table column
+type Column struct {
+	Header string
+	// Wide columns are only shown with --wide or when selected with --columns
+	Wide bool
+	// MaxWidth truncates cells in table output unless --wide is set (0 for no
+	// limit). Columns with a limit also shrink to fit the terminal.
+	MaxWidth int
+	// Color decorates cells in table output; structured formats keep raw values
+	Color func(string) string
+}
+
+// minColumnWidth is the narrowest a column shrinks to when fitting the terminal
+const minColumnWidth = 12
+
+// PrintColumns outputs rows described by column definitions, applying column
+// selection, wide mode, sorting and header options. Unknown --columns and
+// --sort-by names are reported as errors.
+func (f *Formatter) PrintColumns(columns []Column, rows [][]string) error {
+	headers := make([]string, len(columns))
+	var indexes []int
+	for i, c := range columns {
+		headers[i] = c.Header
+		if !c.Wide || f.options.Wide {
+			indexes = append(indexes, i)
+		}
+	}
+
+	// Explicitly selected columns may include wide ones
+	if len(f.options.Columns) > 0 {
+		var err error
+		indexes, err = columnIndexes(headers, f.options.Columns)
+		if err != nil {
+			return err
+		}
+	}
+	columns = pick(columns, indexes)
+	headers = pick(headers, indexes)
+	rows = project(rows, indexes)
+
+	if f.options.SortBy != "" {
+		if err := sortRows(headers, rows, f.options.SortBy); err != nil {
+			return err
+		}
+	}
+
+	switch f.format {
+	case FormatJSON:
+		return f.printJSON(tableRecords(headers, rows))
+	case FormatNDJSON:
+		for _, rec := range tableRecords(headers, rows) {
+			if err := f.PrintRecord(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatYAML:
+		return f.printYAML(tableRecords(headers, rows))
+	case FormatCSV, FormatTSV:
+		return f.printDelimited(headers, rows)
+	case FormatGoTemplate, FormatTemplateFile, FormatJSONPath:
+		return f.Print(tableRecords(headers, rows))
+	}
+
+	f.renderTable(columns, headers, rows)
+	return nil
+}
+
+// renderTable draws rows as a terminal table, truncating and coloring cells
+func (f *Formatter) renderTable(columns []Column, headers []string, rows [][]string) {
+	limits := make([]int, len(columns))
+	if !f.options.Wide {
+		for i, c := range columns {
+			limits[i] = c.MaxWidth
+		}
+		fitToWidth(columns, headers, rows, limits, TerminalWidth())
+	}
+
+	colorize := f.colorize()
+	cells := make([][]string, len(rows))
+	for r, row := range rows {
+		cells[r] = make([]string, len(row))
+		for i, cell := range row {
+			if limits[i] > 0 {
+				cell = truncateCell(cell, limits[i])
+			}
+			if colorize && columns[i].Color != nil {
+				cell = columns[i].Color(cell)
+			}
+			cells[r][i] = cell
+		}
+	}
+
+	table := tablewriter.NewTable(f.writer,
+		tablewriter.WithHeaderAlignment(tw.AlignLeft),
+		tablewriter.WithRowAlignment(tw.AlignLeft),
+	)
+	if !f.options.NoHeaders {
+		table.Header(headers)
+	}
+	table.Bulk(cells)
+	table.Render()
+}
+
+// fitToWidth lowers the limits of truncatable columns, widest first, until the
+// table fits the terminal width. A width of 0 leaves the limits unchanged.
+func fitToWidth(columns []Column, headers []string, rows [][]string, limits []int, width int) {
+	if width <= 0 || len(columns) == 0 {
+		return
+	}
+
+	natural := make([]int, len(columns))
+	for i, h := range headers {
+		natural[i] = twwidth.Width(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			natural[i] = max(natural[i], twwidth.Width(cell))
+		}
+	}
+
+	// Each column adds a separator and one space of padding on each side
+	total := 1
+	for i := range natural {
+		if limits[i] > 0 {
+			natural[i] = min(natural[i], limits[i])
+		}
+		total += natural[i] + 3
+	}
+
+	for total > width {
+		widest := -1
+		for i, c := range columns {
+			if c.MaxWidth > 0 && natural[i] > minColumnWidth && (widest == -1 || natural[i] > natural[widest]) {
+				widest = i
+			}
+		}
+		if widest == -1 {
+			return
+		}
+		shrink := min(natural[widest]-minColumnWidth, total-width)
+		natural[widest] -= shrink
+		limits[widest] = natural[widest]
+		total -= shrink
+	}
+}
+
+// shape applies column selection and sorting to flattened rows
+func (f *Formatter) shape(headers []string, rows [][]string) ([]string, [][]string, error) {
+	if len(f.options.Columns) > 0 {
+		indexes, err := columnIndexes(headers, f.options.Columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		headers = pick(headers, indexes)
+		rows = project(rows, indexes)
+	}
+
+	if f.options.SortBy != "" {
+		if err := sortRows(headers, rows, f.options.SortBy); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return headers, rows, nil
+}
+
+// pick keeps the elements at the given indexes
+func pick[T any](values []T, indexes []int) []T {
+	out := make([]T, len(indexes))
+	for i, idx := range indexes {
+		out[i] = values[idx]
+	}
+	return out
+}
+
+// sortRows sorts rows by a column. Numbers compare numerically, everything
+// else case-insensitively. "-COLUMN" or "COLUMN:desc" sorts descending.
+func sortRows(headers []string, rows [][]string, sortBy string) error {
+	desc := false
+	name := sortBy
+	if strings.HasPrefix(name, "-") {
+		desc = true
+		name = name[1:]
+	}
+	if base, order, ok := strings.Cut(name, ":"); ok {
+		name = base
+		switch strings.ToLower(order) {
+		case "desc":
+			desc = true
+		case "asc":
+		default:
+			return fmt.Errorf("invalid sort order %q (use asc or desc)", order)
+		}
+	}
+
+	col := -1
+	for i, h := range headers {
+		if strings.EqualFold(h, name) {
+			col = i
+			break
+		}
+	}
+	if col == -1 {
+		return fmt.Errorf("cannot sort by unknown column %q (available: %s)", name, strings.Join(headers, ", "))
+	}
+
+	cell := func(row []string) string {
+		if col < len(row) {
+			return stripANSI(row[col])
+		}
+		return ""
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := cell(rows[i]), cell(rows[j])
+		if desc {
+			a, b = b, a
+		}
+		af, aErr := strconv.ParseFloat(a, 64)
+		bf, bErr := strconv.ParseFloat(b, 64)
+		if aErr == nil && bErr == nil {
+			return af < bf
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return nil
+}
+
+// truncateCell shortens a table cell to a display width, marking the cut with
+// "...". Wide characters such as CJK ideographs take two columns.
+func truncateCell(s string, width int) string {
+	if width <= 3 || twwidth.Width(s) <= width {
+		return s
+	}
+	return twwidth.Truncate(s, width, "...")
+}
+
+// Truncate shortens a string to maxLen characters, marking the cut with "..."
+func Truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen || maxLen <= 3 {
+		return s
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
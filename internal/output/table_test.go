@@ -0,0 +1,147 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var testColumns = []Column{
+	{Header: "Name"},
+	{Header: "Severity"},
+	{Header: "CVSS"},
+	{Header: "Description", MaxWidth: 20},
+	{Header: "Vector", Wide: true},
+}
+
+var testRows = [][]string{
+	{"qs", "HIGH", "7.5", "Prototype pollution in the query string parser", "AV:N"},
+	{"minimist", "CRITICAL", "9.8", "Prototype pollution", "AV:N/AC:L"},
+	{"debug", "LOW", "3.7", "ReDoS", "AV:L"},
+}
+
+// printColumns renders the test rows with the given format and options
+func printColumns(t *testing.T, format Format, opts Options) (string, error) {
+	t.Helper()
+	t.Setenv("COLUMNS", "")
+	var buf bytes.Buffer
+	f := &Formatter{format: format, writer: &buf, options: opts}
+	rows := make([][]string, len(testRows))
+	for i := range testRows {
+		rows[i] = append([]string(nil), testRows[i]...)
+	}
+	err := f.PrintColumns(testColumns, rows)
+	return buf.String(), err
+}
+
+func TestPrintColumnsOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		opts    Options
+		want    []string
+		exclude []string
+	}{
+		{"default", FormatTable, Options{}, []string{"NAME", "SEVERITY", "Prototype polluti..."}, []string{"VECTOR", "AV:N/AC:L"}},
+		{"wide", FormatTable, Options{Wide: true}, []string{"VECTOR", "AV:N/AC:L", "Prototype pollution in the query string parser"}, nil},
+		{"columns select wide column", FormatTable, Options{Columns: []string{"vector", "name"}}, []string{"VECTOR", "AV:N/AC:L", "minimist"}, []string{"SEVERITY", "CVSS"}},
+		{"no headers", FormatTable, Options{NoHeaders: true}, []string{"minimist"}, []string{"NAME"}},
+		{"csv columns", FormatCSV, Options{Columns: []string{"Severity", "Name"}}, []string{"Severity,Name\nHIGH,qs\nCRITICAL,minimist\nLOW,debug\n"}, nil},
+		{"csv no headers", FormatCSV, Options{Columns: []string{"Name"}, NoHeaders: true}, []string{"qs\nminimist\ndebug\n"}, []string{"Name"}},
+		{"sort numeric", FormatCSV, Options{Columns: []string{"Name", "CVSS"}, SortBy: "CVSS"}, []string{"Name,CVSS\ndebug,3.7\nqs,7.5\nminimist,9.8\n"}, nil},
+		{"sort descending prefix", FormatCSV, Options{Columns: []string{"Name", "CVSS"}, SortBy: "-cvss"}, []string{"Name,CVSS\nminimist,9.8\nqs,7.5\ndebug,3.7\n"}, nil},
+		{"sort descending suffix", FormatCSV, Options{Columns: []string{"Name", "CVSS"}, SortBy: "Name:desc"}, []string{"Name,CVSS\nqs,7.5\nminimist,9.8\ndebug,3.7\n"}, nil},
+		{"json keeps selected columns", FormatJSON, Options{Columns: []string{"Name"}}, []string{`"Name": "qs"`}, []string{"Severity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := printColumns(t, tt.format, tt.opts)
+			if err != nil {
+				t.Fatalf("PrintColumns() = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output is missing %q:\n%s", want, out)
+				}
+			}
+			for _, exclude := range tt.exclude {
+				if strings.Contains(out, exclude) {
+					t.Errorf("output contains %q:\n%s", exclude, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintColumnsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"unknown column", Options{Columns: []string{"Name", "Score"}}, `unknown column "Score"`},
+		{"unknown sort column", Options{SortBy: "Score"}, `unknown column "Score"`},
+		{"sort by hidden column", Options{Columns: []string{"Name"}, SortBy: "CVSS"}, `unknown column "CVSS"`},
+		{"invalid sort order", Options{SortBy: "Name:up"}, `invalid sort order "up"`},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{FormatTable, FormatCSV, FormatJSON} {
+			t.Run(tt.name+"/"+string(format), func(t *testing.T) {
+				out, err := printColumns(t, format, tt.opts)
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("PrintColumns() = %v, want an error containing %q", err, tt.want)
+				}
+				if out != "" {
+					t.Errorf("PrintColumns() printed output despite the error:\n%s", out)
+				}
+			})
+		}
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"Prototype pollution", 12, "Prototype..."},
+		// Each ideograph takes two columns
+		{"脆弱性のあるパッケージ", 10, "脆弱性..."},
+		{"脆弱性", 6, "脆弱性"},
+	}
+	for _, tt := range tests {
+		if got := truncateCell(tt.in, tt.width); got != tt.want {
+			t.Errorf("truncateCell(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFitToWidth(t *testing.T) {
+	columns := []Column{{Header: "ID"}, {Header: "Description", MaxWidth: 60}}
+	headers := []string{"ID", "Description"}
+	tests := []struct {
+		name  string
+		cell  string
+		width int
+		want  int
+	}{
+		// 13 + 40 columns of text, 3 of border and padding per column, one closing border
+		{"fits", strings.Repeat("a", 40), 60, 60},
+		{"shrinks", strings.Repeat("a", 40), 50, 30},
+		// 20 ideographs are 40 columns wide
+		{"shrinks wide characters", strings.Repeat("脆", 20), 50, 30},
+		{"never below the minimum", strings.Repeat("a", 40), 10, minColumnWidth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := []int{0, 60}
+			fitToWidth(columns, headers, [][]string{{"CVE-2024-0001", tt.cell}}, limits, tt.width)
+			if limits[1] != tt.want {
+				t.Errorf("Description limit = %d, want %d", limits[1], tt.want)
+			}
+		})
+	}
+}
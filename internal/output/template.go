@@ -0,0 +1,74 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseFormat splits a format such as "go-template={{.ID}}" into its kind and
+// argument. Only the kind is case-insensitive.
+func ParseFormat(format string) (Format, string) {
+	kind, arg, found := strings.Cut(format, "=")
+	f := Format(strings.ToLower(strings.TrimSpace(kind)))
+	switch f {
+	case FormatGoTemplate, FormatTemplateFile, FormatJSONPath:
+		if found {
+			return f, arg
+		}
+	}
+	return Format(strings.ToLower(format)), ""
+}
+
+// templateFuncs are available to go-template and template-file output
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"yaml": func(v any) (string, error) {
+		data, err := yaml.Marshal(v)
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// printTemplate renders data with a Go template. Templates see the typed API
+// structs, so fields use Go names ({{range .Data}}{{.Name}}{{end}}).
+func (f *Formatter) printTemplate(data interface{}) error {
+	text := f.template
+	if f.format == FormatTemplateFile {
+		content, err := os.ReadFile(f.template)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		text = string(content)
+	}
+	if text == "" {
+		return fmt.Errorf("%s output requires a template, e.g. --output '%s={{.ID}}'", f.format, FormatGoTemplate)
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl.Execute(f.writer, data)
+}
+
+// printJSONPath renders data with a JSONPath template over its JSON representation
+func (f *Formatter) printJSONPath(data interface{}) error {
+	if f.template == "" {
+		return fmt.Errorf("jsonpath output requires an expression, e.g. --output 'jsonpath={.data[*].id}'")
+	}
+	jp, err := ParseJSONPath(f.template)
+	if err != nil {
+		return err
+	}
+	return jp.Execute(f.writer, data)
+}
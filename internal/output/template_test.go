@@ -0,0 +1,93 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in     string
+		format Format
+		arg    string
+	}{
+		{"json", FormatJSON, ""},
+		{"JSON", FormatJSON, ""},
+		{"jsonpath={.data[*].id}", FormatJSONPath, "{.data[*].id}"},
+		{"JSONPath={.data[?(@.name==\"a=b\")]}", FormatJSONPath, "{.data[?(@.name==\"a=b\")]}"},
+		{"go-template={{.Data}}", FormatGoTemplate, "{{.Data}}"},
+		{"template-file=Report.tmpl", FormatTemplateFile, "Report.tmpl"},
+		{"jsonpath", FormatJSONPath, ""},
+	}
+	for _, tt := range tests {
+		format, arg := ParseFormat(tt.in)
+		if format != tt.format || arg != tt.arg {
+			t.Errorf("ParseFormat(%s) = %s, %q, want %s, %q", tt.in, format, arg, tt.format, tt.arg)
+		}
+	}
+}
+
+func TestPrintTemplates(t *testing.T) {
+	page := codeclarity.PaginatedResponse[codeclarity.Vulnerability]{
+		Data: []codeclarity.Vulnerability{
+			{ID: "CVE-2021-44906", Severity: codeclarity.Severity{SeverityClass: "CRITICAL"}},
+			{ID: "CVE-2022-24999", Severity: codeclarity.Severity{SeverityClass: "HIGH"}},
+		},
+	}
+	file := filepath.Join(t.TempDir(), "ids.tmpl")
+	if err := os.WriteFile(file, []byte("{{range .Data}}{{lower .ID}}\n{{end}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, output, want string
+	}{
+		{"go-template", "go-template={{range .Data}}{{.ID}} {{.Severity.SeverityClass}}\n{{end}}", "CVE-2021-44906 CRITICAL\nCVE-2022-24999 HIGH\n"},
+		{"go-template json", "go-template={{json (index .Data 0).Severity.SeverityClass}}", `"CRITICAL"`},
+		{"template-file", "template-file=" + file, "cve-2021-44906\ncve-2022-24999\n"},
+		{"jsonpath", `jsonpath={range .data[*]}{.Id}{"\n"}{end}`, "CVE-2021-44906\nCVE-2022-24999\n"},
+		{"jsonpath filter", `jsonpath={.data[?(@.Severity.SeverityClass=="HIGH")].Id}`, "CVE-2022-24999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := NewFormatter(tt.output)
+			f.SetWriter(&buf)
+			if err := f.Print(page); err != nil {
+				t.Fatalf("Print() = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name, output, want string
+	}{
+		{"no template", "go-template", "requires a template"},
+		{"no jsonpath", "jsonpath", "requires an expression"},
+		{"bad template", "go-template={{.ID", "failed to parse template"},
+		{"missing key", "go-template={{.Missing}}", "Missing"},
+		{"missing file", "template-file=" + filepath.Join(t.TempDir(), "missing.tmpl"), "failed to read template file"},
+		{"bad jsonpath", "jsonpath={.data[0}", "jsonpath"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := NewFormatter(tt.output)
+			f.SetWriter(&buf)
+			err := f.Print(map[string]string{"ID": "x"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Print() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Package recording captures API interactions to disk and serves them back,
+// so a CLI session can be reproduced offline.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces sensitive values in recordings
+const Redacted = "REDACTED"
+
+// sensitiveHeaders are dropped from recordings
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Api-Key":     true,
+}
+
+// sensitiveKeys are JSON object keys whose values are redacted
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"api_key":       true,
+	"secret":        true,
+	"email":         true,
+}
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Seq    int    `json:"seq"`
+	Method string `json:"method"`
+	// Path is relative to the API base URL and includes the query string
+	Path           string          `json:"path"`
+	RequestHeader  http.Header     `json:"request_header,omitempty"`
+	RequestBody    json.RawMessage `json:"request_body,omitempty"`
+	Status         int             `json:"status"`
+	ResponseHeader http.Header     `json:"response_header,omitempty"`
+	ResponseBody   json.RawMessage `json:"response_body,omitempty"`
+	// ResponseText holds a response body that is not JSON
+	ResponseText string `json:"response_text,omitempty"`
+}
+
+// key identifies requests that replay the same way
+func (i *Interaction) key() string {
+	return i.Method + " " + i.Path
+}
+
+// relativePath returns the request path and query relative to baseURL's path
+func relativePath(baseURL string, u *url.URL) string {
+	path := u.Path
+	if base, err := url.Parse(baseURL); err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// Recorder is an http.RoundTripper that forwards requests and saves each
+// sanitized interaction as a numbered JSON file in Dir
+type Recorder struct {
+	Dir     string
+	BaseURL string
+	Next    http.RoundTripper
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecorder creates dir and returns a recorder writing to it
+func NewRecorder(dir, baseURL string, next http.RoundTripper) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	// Continue numbering after any existing recordings
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{Dir: dir, BaseURL: baseURL, Next: next, seq: len(existing)}, nil
+}
+
+// RoundTrip performs the request and records it
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Method:         req.Method,
+		Path:           relativePath(r.BaseURL, req.URL),
+		RequestHeader:  sanitizeHeader(req.Header),
+		RequestBody:    sanitizeJSON(reqBody),
+		Status:         resp.StatusCode,
+		ResponseHeader: sanitizeHeader(resp.Header),
+		ResponseBody:   sanitizeJSON(respBody),
+	}
+	if interaction.ResponseBody == nil && len(respBody) > 0 {
+		interaction.ResponseText = string(respBody)
+	}
+
+	if err := r.save(interaction); err != nil {
+		return nil, fmt.Errorf("failed to record interaction: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save(i *Interaction) error {
+	r.mu.Lock()
+	r.seq++
+	i.Seq = r.seq
+	r.mu.Unlock()
+
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.Dir, fmt.Sprintf("%04d.json", i.Seq)), append(data, '\n'), 0600)
+}
+
+func sanitizeHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for k, v := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		out[k] = v
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// sanitizeJSON redacts sensitive keys at any depth. It returns nil for
+// bodies that are empty or not JSON.
+func sanitizeJSON(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil
+	}
+	data, err := json.Marshal(redact(v))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if sensitiveKeys[strings.ToLower(k)] {
+				if child != nil && child != "" {
+					val[k] = Redacted
+				}
+				continue
+			}
+			val[k] = redact(child)
+		}
+	case []interface{}:
+		for i, child := range val {
+			val[i] = redact(child)
+		}
+	}
+	return v
+}
+
+// Replayer is an http.RoundTripper serving recorded interactions without
+// touching the network. Requests with the same method and path get the
+// recorded responses in order; once exhausted the last one repeats, so
+// polling loops settle on their final recorded state.
+type Replayer struct {
+	BaseURL string
+
+	mu      sync.Mutex
+	queues  map[string][]*Interaction
+	served  map[string]int
+	entries int
+}
+
+// LoadReplayer reads the interactions recorded in dir
+func LoadReplayer(dir, baseURL string) (*Replayer, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recordings found in %s", dir)
+	}
+
+	var interactions []*Interaction
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var i Interaction
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", file, err)
+		}
+		interactions = append(interactions, &i)
+	}
+	sort.SliceStable(interactions, func(a, b int) bool {
+		return interactions[a].Seq < interactions[b].Seq
+	})
+
+	r := &Replayer{
+		BaseURL: baseURL,
+		queues:  map[string][]*Interaction{},
+		served:  map[string]int{},
+		entries: len(interactions),
+	}
+	for _, i := range interactions {
+		r.queues[i.key()] = append(r.queues[i.key()], i)
+	}
+	return r, nil
+}
+
+// Len returns the number of loaded interactions
+func (r *Replayer) Len() int {
+	return r.entries
+}
+
+// RoundTrip returns the next recorded response for the request
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := req.Method + " " + relativePath(r.BaseURL, req.URL)
+
+	r.mu.Lock()
+	queue := r.queues[key]
+	n := r.served[key]
+	if n < len(queue) {
+		r.served[key] = n + 1
+	} else {
+		n = len(queue) - 1
+	}
+	r.mu.Unlock()
+
+	if n < 0 {
+		return nil, fmt.Errorf("no recorded response for %s", key)
+	}
+	i := queue[n]
+
+	body := []byte(i.ResponseText)
+	if i.ResponseBody != nil {
+		body = i.ResponseBody
+	}
+	header := i.ResponseHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Content-Length")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// severityColor returns the chart color for a severity class
+func severityColor(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "#7f1d1d"
+	case "high":
+		return "#dc2626"
+	case "medium":
+		return "#f59e0b"
+	case "low":
+		return "#3b82f6"
+	default:
+		return "#9ca3af"
+	}
+}
+
+// severityBar is one bar of a severity chart
+type severityBar struct {
+	label string
+	count int
+}
+
+// severityBars returns the bars of a severity chart and the largest count
+func severityBars(stats *codeclarity.VulnerabilityStats) ([]severityBar, int) {
+	if stats == nil {
+		stats = &codeclarity.VulnerabilityStats{}
+	}
+	bars := []severityBar{
+		{"Critical", stats.Critical},
+		{"High", stats.High},
+		{"Medium", stats.Medium},
+		{"Low", stats.Low},
+		{"None", stats.None},
+	}
+	maxCount := 0
+	for _, b := range bars {
+		maxCount = max(maxCount, b.count)
+	}
+	return bars, maxCount
+}
+
+// SeverityChartText renders vulnerability counts as a text bar chart in a
+// fenced code block, for documents that cannot embed images
+func SeverityChartText(stats *codeclarity.VulnerabilityStats) string {
+	const barWidth = 30
+	bars, maxCount := severityBars(stats)
+
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	for _, b := range bars {
+		width := 0
+		if maxCount > 0 {
+			width = b.count * barWidth / maxCount
+		}
+		if b.count > 0 && width < 1 {
+			width = 1
+		}
+		fmt.Fprintf(&sb, "%-8s %s %d\n", b.label, strings.Repeat("█", width), b.count)
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
+
+// SeverityChartSVG renders vulnerability counts as a horizontal bar chart
+func SeverityChartSVG(stats *codeclarity.VulnerabilityStats) string {
+	bars, maxCount := severityBars(stats)
+
+	const (
+		labelWidth = 70
+		barWidth   = 320
+		barHeight  = 22
+		gap        = 8
+	)
+
+	height := len(bars)*(barHeight+gap) + gap
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="Vulnerabilities by severity" font-family="sans-serif" font-size="13">`,
+		labelWidth+barWidth+60, height, labelWidth+barWidth+60, height)
+
+	for i, b := range bars {
+		y := gap + i*(barHeight+gap)
+		width := 0
+		if maxCount > 0 {
+			width = b.count * barWidth / maxCount
+		}
+		if b.count > 0 && width < 2 {
+			width = 2
+		}
+		fmt.Fprintf(&sb, `<text x="0" y="%d" dominant-baseline="middle">%s</text>`, y+barHeight/2, b.label)
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" rx="3" fill="%s"/>`, labelWidth, y, width, barHeight, severityColor(b.label))
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" dominant-baseline="middle">%d</text>`, labelWidth+width+6, y+barHeight/2, b.count)
+	}
+
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
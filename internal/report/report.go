@@ -0,0 +1,227 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"codeclarity.io/internal/output"
+	"codeclarity.io/pkg/codeclarity"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// Format represents the report document format
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat validates a report format name
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unsupported report format %q (use markdown or html)", s)
+	}
+}
+
+// Data holds everything rendered into a report
+type Data struct {
+	Project         *codeclarity.Project
+	Analysis        *codeclarity.Analysis
+	VulnStats       *codeclarity.VulnerabilityStats
+	SBOMStats       *codeclarity.SBOMStats
+	LicenseStats    *codeclarity.LicenseStats
+	Vulnerabilities []codeclarity.Vulnerability
+	Suppressed      int
+	TopPackages     []PackageRisk
+	Licenses        []LicenseCount
+	GeneratedAt     time.Time
+}
+
+// PackageRisk aggregates the vulnerabilities affecting one dependency version
+type PackageRisk struct {
+	Name            string
+	Version         string
+	Critical        int
+	High            int
+	Medium          int
+	Low             int
+	MaxSeverity     float64
+	Vulnerabilities []string
+}
+
+// Total returns the number of vulnerabilities affecting the package
+func (p PackageRisk) Total() int {
+	return len(p.Vulnerabilities)
+}
+
+// LicenseCount is a license and the number of dependencies using it
+type LicenseCount struct {
+	License string
+	Count   int
+}
+
+// NewData assembles report data and derives rankings from the raw results
+func NewData(project *codeclarity.Project, analysis *codeclarity.Analysis, vulnStats *codeclarity.VulnerabilityStats, sbomStats *codeclarity.SBOMStats, licenseStats *codeclarity.LicenseStats, vulns []codeclarity.Vulnerability, top int) *Data {
+	d := &Data{
+		Project:         project,
+		Analysis:        analysis,
+		VulnStats:       vulnStats,
+		SBOMStats:       sbomStats,
+		LicenseStats:    licenseStats,
+		Vulnerabilities: vulns,
+		GeneratedAt:     time.Now().UTC(),
+	}
+
+	sort.SliceStable(d.Vulnerabilities, func(i, j int) bool {
+		return d.Vulnerabilities[i].Severity.Severity > d.Vulnerabilities[j].Severity.Severity
+	})
+
+	d.TopPackages = rankPackages(vulns)
+	if top > 0 && len(d.TopPackages) > top {
+		d.TopPackages = d.TopPackages[:top]
+	}
+
+	if licenseStats != nil {
+		for license, count := range licenseStats.ByLicense {
+			d.Licenses = append(d.Licenses, LicenseCount{License: license, Count: count})
+		}
+		sort.Slice(d.Licenses, func(i, j int) bool {
+			if d.Licenses[i].Count != d.Licenses[j].Count {
+				return d.Licenses[i].Count > d.Licenses[j].Count
+			}
+			return d.Licenses[i].License < d.Licenses[j].License
+		})
+	}
+
+	return d
+}
+
+// rankPackages orders dependencies by critical, then high findings, then worst CVSS score
+func rankPackages(vulns []codeclarity.Vulnerability) []PackageRisk {
+	byKey := make(map[string]*PackageRisk)
+	var order []string
+
+	for _, v := range vulns {
+		for _, a := range v.Affected {
+			key := a.AffectedDependency + "@" + a.AffectedVersion
+			p, ok := byKey[key]
+			if !ok {
+				p = &PackageRisk{Name: a.AffectedDependency, Version: a.AffectedVersion}
+				byKey[key] = p
+				order = append(order, key)
+			}
+			p.Vulnerabilities = append(p.Vulnerabilities, v.ID)
+			switch strings.ToLower(v.Severity.SeverityClass) {
+			case "critical":
+				p.Critical++
+			case "high":
+				p.High++
+			case "medium":
+				p.Medium++
+			case "low":
+				p.Low++
+			}
+			p.MaxSeverity = max(p.MaxSeverity, v.Severity.Severity)
+		}
+	}
+
+	packages := make([]PackageRisk, 0, len(order))
+	for _, key := range order {
+		packages = append(packages, *byKey[key])
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Critical != b.Critical {
+			return a.Critical > b.Critical
+		}
+		if a.High != b.High {
+			return a.High > b.High
+		}
+		return a.MaxSeverity > b.MaxSeverity
+	})
+	return packages
+}
+
+// Render writes the report in the given format. If templatePath is set, that
+// template is used instead of the built-in one.
+func Render(w io.Writer, format Format, data *Data, templatePath string) error {
+	name := "report.md.tmpl"
+	if format == FormatHTML {
+		name = "report.html.tmpl"
+	}
+
+	var content []byte
+	var err error
+	if templatePath != "" {
+		name = filepath.Base(templatePath)
+		content, err = os.ReadFile(templatePath)
+	} else {
+		content, err = templates.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	if format == FormatHTML {
+		funcs := htmltemplate.FuncMap(templateFuncs())
+		funcs["severityChart"] = func(s *codeclarity.VulnerabilityStats) htmltemplate.HTML {
+			return htmltemplate.HTML(SeverityChartSVG(s))
+		}
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		return tmpl.Execute(w, data)
+	}
+
+	funcs := texttemplate.FuncMap(templateFuncs())
+	// Markdown renderers strip raw SVG and data URI images, so draw the chart as text
+	funcs["severityChart"] = SeverityChartText
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl.Execute(w, data)
+}
+
+// templateFuncs returns helpers available to both built-in and custom templates
+func templateFuncs() map[string]any {
+	return map[string]any{
+		"date": func(t time.Time) string {
+			return t.Format("2006-01-02 15:04 MST")
+		},
+		"truncate": func(n int, s string) string {
+			return output.Truncate(s, n)
+		},
+		"lower":         strings.ToLower,
+		"upper":         strings.ToUpper,
+		"join":          strings.Join,
+		"severityColor": severityColor,
+		"mdEscape": func(s string) string {
+			s = strings.ReplaceAll(s, "|", "\\|")
+			return strings.Join(strings.Fields(s), " ")
+		},
+		"percent": func(part, total int) string {
+			if total == 0 {
+				return "0%"
+			}
+			return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// composerLock is a composer.lock
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+type composerPackage struct {
+	Name    string        `json:"name"`
+	Version string        `json:"version"`
+	Require composerLinks `json:"require"`
+	Dist    struct {
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+}
+
+// composerManifest is the part of composer.json describing dependencies
+type composerManifest struct {
+	Name       string        `json:"name"`
+	Version    string        `json:"version"`
+	Require    composerLinks `json:"require"`
+	RequireDev composerLinks `json:"require-dev"`
+}
+
+// composerLinks maps package names to constraints. Composer writes empty
+// maps as [] so both forms are accepted.
+type composerLinks map[string]string
+
+func (l *composerLinks) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("[]")) {
+		*l = nil
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	*l = m
+	return nil
+}
+
+// ParseComposer parses a composer.lock. Direct dependencies come from the
+// composer.json in dir, or are the packages nothing else requires when there
+// is none.
+func ParseComposer(lockfile, dir string, data []byte) (*Graph, error) {
+	var lock composerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockfile, err)
+	}
+
+	g := newGraph(lockfile, Composer)
+	byName := map[string]string{}
+	all := append(append([]composerPackage{}, lock.Packages...), lock.PackagesDev...)
+	for _, p := range all {
+		pkg := g.addPackage(strings.ToLower(p.Name), p.Version)
+		if p.Dist.Shasum != "" {
+			pkg.Hashes = []Hash{{Alg: "SHA-1", Content: p.Dist.Shasum}}
+		}
+		byName[pkg.Name] = pkg.Key()
+	}
+	for _, p := range all {
+		pkg := g.Packages[byName[strings.ToLower(p.Name)]]
+		for name := range p.Require {
+			if k, ok := byName[strings.ToLower(name)]; ok {
+				pkg.Dependencies = append(pkg.Dependencies, k)
+			}
+		}
+	}
+
+	root := &Importer{Path: "."}
+	manifest, err := readComposerManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		root.Name, root.Version = manifest.Name, manifest.Version
+		// Platform requirements such as php and ext-json have no vendor
+		// and are not in the lockfile
+		for name := range manifest.Require {
+			if k, ok := byName[strings.ToLower(name)]; ok {
+				root.Dependencies = append(root.Dependencies, k)
+			}
+		}
+		for name := range manifest.RequireDev {
+			if k, ok := byName[strings.ToLower(name)]; ok {
+				root.DevDependencies = append(root.DevDependencies, k)
+			}
+		}
+	} else {
+		required := map[string]bool{}
+		for _, p := range g.Packages {
+			for _, k := range p.Dependencies {
+				required[k] = true
+			}
+		}
+		for i, p := range all {
+			k := byName[strings.ToLower(p.Name)]
+			switch {
+			case required[k]:
+			case i >= len(lock.Packages):
+				root.DevDependencies = append(root.DevDependencies, k)
+			default:
+				root.Dependencies = append(root.Dependencies, k)
+			}
+		}
+	}
+	g.Importers = append(g.Importers, root)
+
+	g.finish()
+	return g, nil
+}
+
+// readComposerManifest reads dir/composer.json, returning nil when it does
+// not exist
+func readComposerManifest(dir string) (*composerManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m composerManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid composer.json in %s: %w", dir, err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,257 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SpecVersion is the CycloneDX specification version written
+const SpecVersion = "1.5"
+
+// Property names recorded on components
+const (
+	PropertyDependency  = "codeclarity:dependency"
+	PropertyDevelopment = "codeclarity:development"
+	PropertyWorkspace   = "codeclarity:workspace"
+	PropertyLockfile    = "codeclarity:lockfile"
+)
+
+// BOM is a CycloneDX JSON document
+type BOM struct {
+	BOMFormat    string       `json:"bomFormat"`
+	SpecVersion  string       `json:"specVersion"`
+	SerialNumber string       `json:"serialNumber"`
+	Version      int          `json:"version"`
+	Metadata     Metadata     `json:"metadata"`
+	Components   []Component  `json:"components"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Metadata describes the BOM and the project it is about
+type Metadata struct {
+	Timestamp  time.Time  `json:"timestamp"`
+	Tools      Tools      `json:"tools"`
+	Component  Component  `json:"component"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Tools lists the tools that produced the BOM
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component is a package, a workspace or the project itself
+type Component struct {
+	Type       string     `json:"type"`
+	BOMRef     string     `json:"bom-ref,omitempty"`
+	Group      string     `json:"group,omitempty"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	Scope      string     `json:"scope,omitempty"`
+	Hashes     []Hash     `json:"hashes,omitempty"`
+	PURL       string     `json:"purl,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Property is a name-value pair attached to a component
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Dependency lists the components a component depends on
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// NewBOM renders the graphs of the lockfiles found in a project as one
+// document. Packages are referenced by package URL and merged across
+// lockfiles; a package is direct if any lockfile has it as direct and
+// development-only if every lockfile does. Importers at the project root
+// are the metadata component, named after the first of them with a name or
+// name otherwise; other importers become application components.
+func NewBOM(name string, graphs []*Graph, tool string) *BOM {
+	const rootRef = "project:."
+
+	root := Component{Type: "application", BOMRef: rootRef, Name: name}
+	components := map[string]*Component{}
+	depends := map[string]map[string]bool{rootRef: {}}
+	direct := map[string]bool{}
+	prod := map[string]bool{}
+	lockfiles := map[string][]string{}
+	named := false
+
+	edge := func(from, to string) {
+		if depends[from] == nil {
+			depends[from] = map[string]bool{}
+		}
+		depends[from][to] = true
+	}
+
+	for _, g := range graphs {
+		dir := path.Dir(g.Lockfile)
+		for _, p := range g.Packages {
+			ref := PackageURL(p.Ecosystem, p.Name, p.Version)
+			if components[ref] == nil {
+				c := &Component{Type: "library", BOMRef: ref, Version: p.Version, Hashes: p.Hashes, PURL: ref}
+				c.Group, c.Name = splitGroup(p.Ecosystem, p.Name)
+				components[ref] = c
+				depends[ref] = map[string]bool{}
+			}
+			direct[ref] = direct[ref] || p.Direct
+			prod[ref] = prod[ref] || !p.Dev
+			lockfiles[ref] = append(lockfiles[ref], g.Lockfile)
+			for _, dep := range p.Dependencies {
+				d := g.Packages[dep]
+				edge(ref, PackageURL(d.Ecosystem, d.Name, d.Version))
+			}
+		}
+
+		importerRef := func(p string) string {
+			return "project:" + path.Join(dir, p)
+		}
+		for _, imp := range g.Importers {
+			ref := importerRef(imp.Path)
+			if ref == rootRef {
+				if !named && imp.Name != "" {
+					root.Name, root.Version = imp.Name, imp.Version
+					named = true
+				}
+			} else if components[ref] == nil {
+				impName := imp.Name
+				if impName == "" {
+					impName = path.Join(dir, imp.Path)
+				}
+				components[ref] = &Component{
+					Type:       "application",
+					BOMRef:     ref,
+					Name:       impName,
+					Version:    imp.Version,
+					Properties: []Property{{Name: PropertyWorkspace, Value: path.Join(dir, imp.Path)}},
+				}
+				edge(rootRef, ref)
+			}
+			for _, k := range append(append([]string{}, imp.Dependencies...), imp.DevDependencies...) {
+				p := g.Packages[k]
+				edge(ref, PackageURL(p.Ecosystem, p.Name, p.Version))
+			}
+			for _, ws := range imp.Workspaces {
+				edge(ref, importerRef(ws))
+			}
+		}
+	}
+
+	bom := &BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  SpecVersion,
+		SerialNumber: newSerialNumber(),
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: time.Now().UTC().Truncate(time.Second),
+			Tools:     Tools{Components: []Component{{Type: "application", Name: tool}}},
+			Component: root,
+		},
+		Components:   []Component{},
+		Dependencies: []Dependency{},
+	}
+	for _, g := range graphs {
+		bom.Metadata.Properties = append(bom.Metadata.Properties, Property{Name: PropertyLockfile, Value: g.Lockfile})
+	}
+
+	refs := make([]string, 0, len(components))
+	for ref := range components {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		c := *components[ref]
+		if c.Type == "library" {
+			kind := "transitive"
+			if direct[ref] {
+				kind = "direct"
+			}
+			c.Scope = "required"
+			c.Properties = append(c.Properties, Property{Name: PropertyDependency, Value: kind})
+			if !prod[ref] {
+				c.Scope = "optional"
+				c.Properties = append(c.Properties, Property{Name: PropertyDevelopment, Value: "true"})
+			}
+			for _, lf := range sortedSet(lockfiles[ref]...) {
+				c.Properties = append(c.Properties, Property{Name: PropertyLockfile, Value: lf})
+			}
+		}
+		bom.Components = append(bom.Components, c)
+	}
+
+	for _, ref := range append([]string{rootRef}, refs...) {
+		var dependsOn []string
+		for _, dep := range sortedKeys(depends[ref]) {
+			// Links to directories that are not importers are left out
+			if components[dep] != nil {
+				dependsOn = append(dependsOn, dep)
+			}
+		}
+		bom.Dependencies = append(bom.Dependencies, Dependency{Ref: ref, DependsOn: dependsOn})
+	}
+	return bom
+}
+
+// PackageURL returns the package URL of a package
+func PackageURL(eco Ecosystem, name, version string) string {
+	var b strings.Builder
+	b.WriteString("pkg:" + string(eco) + "/")
+	for i, part := range strings.Split(name, "/") {
+		if i > 0 {
+			b.WriteString("/")
+		}
+		if scope, ok := strings.CutPrefix(part, "@"); ok {
+			b.WriteString("%40")
+			part = scope
+		}
+		b.WriteString(url.PathEscape(part))
+	}
+	if version != "" {
+		b.WriteString("@" + url.PathEscape(version))
+	}
+	return b.String()
+}
+
+// splitGroup splits the npm scope or Composer vendor off a package name
+func splitGroup(eco Ecosystem, name string) (group, rest string) {
+	if i := strings.LastIndex(name, "/"); i > 0 && (eco == Composer || strings.HasPrefix(name, "@")) {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func sortedSet(values ...string) []string {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return sortedKeys(set)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for v := range set {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// newSerialNumber returns a random RFC 4122 URN
+func newSerialNumber() string {
+	var u [16]byte
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
@@ -0,0 +1,207 @@
+// Package sbom builds software bills of materials from lockfiles without the
+// CodeClarity server.
+//
+// Each supported lockfile (package-lock.json, yarn.lock, pnpm-lock.yaml and
+// composer.lock) is parsed into a Graph of the resolved packages and the
+// edges between them, starting from the importers: the root project and its
+// workspaces. Graphs are then rendered as a CycloneDX document.
+package sbom
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Ecosystem is the package ecosystem of a lockfile
+type Ecosystem string
+
+// Supported ecosystems
+const (
+	NPM      Ecosystem = "npm"
+	Composer Ecosystem = "composer"
+)
+
+// Package is a resolved package. Packages are identified by their name and
+// version; installing the same version at several places gives one package.
+type Package struct {
+	Name      string
+	Version   string
+	Ecosystem Ecosystem
+	// Hashes are the checksums of the package archive
+	Hashes []Hash
+	// Dependencies are the keys of the packages this one depends on
+	Dependencies []string
+	// Direct is set for packages an importer depends on
+	Direct bool
+	// Dev is set for packages only reachable from development dependencies
+	Dev bool
+}
+
+// Hash is a checksum in CycloneDX form: an algorithm such as "SHA-512" and
+// the hex-encoded digest
+type Hash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+// sriAlgorithms maps subresource integrity algorithms to CycloneDX ones
+var sriAlgorithms = map[string]string{
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+}
+
+// integrityHashes decodes a subresource integrity string such as
+// "sha512-<base64>", as found in npm, yarn and pnpm lockfiles
+func integrityHashes(sri string) []Hash {
+	var hashes []Hash
+	for _, part := range strings.Fields(sri) {
+		alg, digest, ok := strings.Cut(part, "-")
+		if !ok || sriAlgorithms[alg] == "" {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			continue
+		}
+		hashes = append(hashes, Hash{Alg: sriAlgorithms[alg], Content: hex.EncodeToString(sum)})
+	}
+	return hashes
+}
+
+// Key identifies the package within its graph
+func (p *Package) Key() string {
+	return packageKey(p.Name, p.Version)
+}
+
+func packageKey(name, version string) string {
+	return name + "@" + version
+}
+
+// Importer is a project whose dependencies are installed from the lockfile:
+// the root project or one of its workspaces
+type Importer struct {
+	// Path is slash-separated and relative to the lockfile's directory
+	Path    string
+	Name    string
+	Version string
+	// Dependencies and DevDependencies hold package keys
+	Dependencies    []string
+	DevDependencies []string
+	// Workspaces holds the paths of the importers this one depends on
+	Workspaces []string
+}
+
+// Graph is the dependency graph of one lockfile
+type Graph struct {
+	// Lockfile is the slash-separated path of the lockfile
+	Lockfile  string
+	Ecosystem Ecosystem
+	Importers []*Importer
+	Packages  map[string]*Package
+}
+
+func newGraph(lockfile string, eco Ecosystem) *Graph {
+	return &Graph{Lockfile: lockfile, Ecosystem: eco, Packages: map[string]*Package{}}
+}
+
+// addPackage returns the graph's package for name and version, adding it
+// when missing
+func (g *Graph) addPackage(name, version string) *Package {
+	key := packageKey(name, version)
+	if p, ok := g.Packages[key]; ok {
+		return p
+	}
+	p := &Package{Name: name, Version: version, Ecosystem: g.Ecosystem}
+	g.Packages[key] = p
+	return p
+}
+
+// Stats counts the packages of a graph
+type Stats struct {
+	Packages   int `json:"packages"`
+	Direct     int `json:"direct"`
+	Transitive int `json:"transitive"`
+	Dev        int `json:"dev"`
+}
+
+// Stats counts the graph's packages by kind
+func (g *Graph) Stats() Stats {
+	s := Stats{Packages: len(g.Packages)}
+	for _, p := range g.Packages {
+		if p.Direct {
+			s.Direct++
+		} else {
+			s.Transitive++
+		}
+		if p.Dev {
+			s.Dev++
+		}
+	}
+	return s
+}
+
+// finish removes dangling and repeated edges, orders everything and marks
+// direct and development packages
+func (g *Graph) finish() {
+	clean := func(keys []string) []string {
+		seen := map[string]bool{}
+		var out []string
+		for _, k := range keys {
+			if _, ok := g.Packages[k]; ok && !seen[k] {
+				seen[k] = true
+				out = append(out, k)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	for _, p := range g.Packages {
+		p.Dependencies = clean(p.Dependencies)
+	}
+
+	sort.SliceStable(g.Importers, func(i, j int) bool { return g.Importers[i].Path < g.Importers[j].Path })
+	var prod []string
+	for _, imp := range g.Importers {
+		imp.Dependencies = clean(imp.Dependencies)
+		imp.DevDependencies = clean(imp.DevDependencies)
+		imp.Workspaces = slices.Compact(slices.Sorted(slices.Values(imp.Workspaces)))
+		for _, k := range imp.Dependencies {
+			g.Packages[k].Direct = true
+		}
+		for _, k := range imp.DevDependencies {
+			g.Packages[k].Direct = true
+		}
+		prod = append(prod, imp.Dependencies...)
+	}
+
+	// Anything not reachable from a production dependency is only needed
+	// for development
+	reachable := map[string]bool{}
+	for len(prod) > 0 {
+		k := prod[len(prod)-1]
+		prod = prod[:len(prod)-1]
+		if reachable[k] {
+			continue
+		}
+		reachable[k] = true
+		prod = append(prod, g.Packages[k].Dependencies...)
+	}
+	for k, p := range g.Packages {
+		p.Dev = !reachable[k]
+	}
+}
+
+// splitName splits "name@version" at the version separator, skipping the
+// "@" of a scoped npm name
+func splitName(s string) (name, version string, ok bool) {
+	i := strings.LastIndex(s, "@")
+	if i <= 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+1:], true
+}
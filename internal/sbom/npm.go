@@ -0,0 +1,329 @@
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packageJSON is the part of package.json describing dependencies
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	Workspaces           workspaceGlobs    `json:"workspaces"`
+}
+
+// workspaceGlobs accepts both the array and the {"packages": [...]} forms
+// of the workspaces field
+type workspaceGlobs []string
+
+func (w *workspaceGlobs) UnmarshalJSON(data []byte) error {
+	var globs []string
+	if err := json.Unmarshal(data, &globs); err == nil {
+		*w = globs
+		return nil
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*w = obj.Packages
+	return nil
+}
+
+// prodDependencies returns the names of the non-development dependencies
+func (p *packageJSON) prodDependencies() map[string]string {
+	deps := map[string]string{}
+	for _, m := range []map[string]string{p.PeerDependencies, p.OptionalDependencies, p.Dependencies} {
+		for name, spec := range m {
+			deps[name] = spec
+		}
+	}
+	return deps
+}
+
+// readPackageJSON reads dir/package.json, returning nil when it does not
+// exist
+func readPackageJSON(dir string) (*packageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p packageJSON
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid package.json in %s: %w", dir, err)
+	}
+	return &p, nil
+}
+
+// expandWorkspaces resolves the workspace globs of the project in dir to
+// the slash-separated paths of the workspaces holding a package.json
+func expandWorkspaces(dir string, globs []string) ([]string, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, glob := range globs {
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(glob)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace pattern %q: %w", glob, err)
+		}
+		for _, m := range matches {
+			if _, err := os.Stat(filepath.Join(m, "package.json")); err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(dir, m)
+			if err != nil {
+				return nil, err
+			}
+			rel = filepath.ToSlash(rel)
+			if !seen[rel] {
+				seen[rel] = true
+				paths = append(paths, rel)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// npmLock is a package-lock.json or npm-shrinkwrap.json
+type npmLock struct {
+	Name            string                `json:"name"`
+	Version         string                `json:"version"`
+	LockfileVersion int                   `json:"lockfileVersion"`
+	Packages        map[string]npmPackage `json:"packages"`
+	Dependencies    map[string]npmV1Dep   `json:"dependencies"`
+}
+
+// npmPackage is an entry of the "packages" map of lockfile versions 2 and 3
+type npmPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity"`
+	Link                 bool              `json:"link"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// npmV1Dep is an entry of the nested "dependencies" tree of lockfile
+// version 1
+type npmV1Dep struct {
+	Version      string              `json:"version"`
+	Integrity    string              `json:"integrity"`
+	Dev          bool                `json:"dev"`
+	Requires     map[string]string   `json:"requires"`
+	Dependencies map[string]npmV1Dep `json:"dependencies"`
+}
+
+// ParseNPM parses a package-lock.json. dir is the lockfile's directory,
+// where package.json is read from for version 1 lockfiles.
+func ParseNPM(lockfile, dir string, data []byte) (*Graph, error) {
+	var lock npmLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockfile, err)
+	}
+	g := newGraph(lockfile, NPM)
+	if lock.Packages != nil {
+		parseNPMPackages(g, &lock)
+	} else {
+		manifest, err := readPackageJSON(dir)
+		if err != nil {
+			return nil, err
+		}
+		parseNPMV1(g, &lock, manifest)
+	}
+	g.finish()
+	return g, nil
+}
+
+// parseNPMPackages reads the flat "packages" map keyed by install path.
+// Entries outside node_modules are the root project and its workspaces.
+func parseNPMPackages(g *Graph, lock *npmLock) {
+	isImporter := func(p string) bool {
+		return p == "" || !(strings.HasPrefix(p, "node_modules/") || strings.Contains(p, "/node_modules/"))
+	}
+
+	// resolve finds the install path a dependency of the package at from
+	// resolves to, following node's lookup through parent node_modules
+	resolve := func(from, name string) (string, bool) {
+		for base := from; ; base = npmParent(base) {
+			candidate := path.Join(base, "node_modules", name)
+			if entry, ok := lock.Packages[candidate]; ok {
+				if entry.Link {
+					return entry.Resolved, true
+				}
+				return candidate, true
+			}
+			if base == "" {
+				return "", false
+			}
+		}
+	}
+
+	keys := map[string]string{}
+	for p, entry := range lock.Packages {
+		if isImporter(p) || entry.Link {
+			continue
+		}
+		name := entry.Name
+		if name == "" {
+			name = p[strings.LastIndex(p, "node_modules/")+len("node_modules/"):]
+		}
+		pkg := g.addPackage(name, entry.Version)
+		if pkg.Hashes == nil {
+			pkg.Hashes = integrityHashes(entry.Integrity)
+		}
+		keys[p] = pkg.Key()
+	}
+
+	link := func(from string, deps map[string]string) (pkgs, workspaces []string) {
+		for name := range deps {
+			target, ok := resolve(from, name)
+			if !ok {
+				continue
+			}
+			if key, ok := keys[target]; ok {
+				pkgs = append(pkgs, key)
+			} else if isImporter(target) {
+				workspaces = append(workspaces, npmImporterPath(target))
+			}
+		}
+		return pkgs, workspaces
+	}
+
+	for p, entry := range lock.Packages {
+		if entry.Link {
+			continue
+		}
+		if !isImporter(p) {
+			pkg := g.Packages[keys[p]]
+			for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies} {
+				found, _ := link(p, deps)
+				pkg.Dependencies = append(pkg.Dependencies, found...)
+			}
+			continue
+		}
+
+		imp := &Importer{Path: npmImporterPath(p), Name: entry.Name, Version: entry.Version}
+		if p == "" && imp.Name == "" {
+			imp.Name, imp.Version = lock.Name, lock.Version
+		}
+		for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies} {
+			found, workspaces := link(p, deps)
+			imp.Dependencies = append(imp.Dependencies, found...)
+			imp.Workspaces = append(imp.Workspaces, workspaces...)
+		}
+		found, workspaces := link(p, entry.DevDependencies)
+		imp.DevDependencies = append(imp.DevDependencies, found...)
+		imp.Workspaces = append(imp.Workspaces, workspaces...)
+		g.Importers = append(g.Importers, imp)
+	}
+}
+
+// npmParent returns the install path whose node_modules is searched after
+// the one of p
+func npmParent(p string) string {
+	if i := strings.LastIndex(p, "/node_modules/"); i >= 0 {
+		return p[:i]
+	}
+	if strings.HasPrefix(p, "node_modules/") {
+		return ""
+	}
+	if d := path.Dir(p); d != "." {
+		return d
+	}
+	return ""
+}
+
+func npmImporterPath(p string) string {
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// parseNPMV1 reads the nested "dependencies" tree of lockfile version 1.
+// Direct dependencies come from package.json, or are the top-level entries
+// nothing else requires when there is none.
+func parseNPMV1(g *Graph, lock *npmLock, manifest *packageJSON) {
+	type scope struct {
+		deps   map[string]npmV1Dep
+		parent *scope
+	}
+	resolve := func(s *scope, name string) (string, bool) {
+		for ; s != nil; s = s.parent {
+			if dep, ok := s.deps[name]; ok {
+				return packageKey(name, dep.Version), true
+			}
+		}
+		return "", false
+	}
+
+	var walk func(s *scope)
+	walk = func(s *scope) {
+		for name, dep := range s.deps {
+			pkg := g.addPackage(name, dep.Version)
+			if pkg.Hashes == nil {
+				pkg.Hashes = integrityHashes(dep.Integrity)
+			}
+			inner := &scope{deps: dep.Dependencies, parent: s}
+			for req := range dep.Requires {
+				if key, ok := resolve(inner, req); ok {
+					pkg.Dependencies = append(pkg.Dependencies, key)
+				}
+			}
+			walk(inner)
+		}
+	}
+	top := &scope{deps: lock.Dependencies}
+	walk(top)
+
+	root := &Importer{Path: ".", Name: lock.Name, Version: lock.Version}
+	if manifest != nil {
+		for name := range manifest.prodDependencies() {
+			if key, ok := resolve(top, name); ok {
+				root.Dependencies = append(root.Dependencies, key)
+			}
+		}
+		for name := range manifest.DevDependencies {
+			if key, ok := resolve(top, name); ok {
+				root.DevDependencies = append(root.DevDependencies, key)
+			}
+		}
+	} else {
+		required := map[string]bool{}
+		for _, p := range g.Packages {
+			for _, k := range p.Dependencies {
+				required[k] = true
+			}
+		}
+		for name, dep := range lock.Dependencies {
+			key := packageKey(name, dep.Version)
+			if required[key] {
+				continue
+			}
+			if dep.Dev {
+				root.DevDependencies = append(root.DevDependencies, key)
+			} else {
+				root.Dependencies = append(root.Dependencies, key)
+			}
+		}
+	}
+	g.Importers = append(g.Importers, root)
+}
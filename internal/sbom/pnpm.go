@@ -0,0 +1,203 @@
+package sbom
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pnpmLock is a pnpm-lock.yaml of lockfile version 5 to 9
+type pnpmLock struct {
+	LockfileVersion string                  `yaml:"lockfileVersion"`
+	Importers       map[string]pnpmImporter `yaml:"importers"`
+	// Root holds the dependencies of single project lockfiles, which have
+	// no importers before version 9
+	Root      pnpmImporter           `yaml:",inline"`
+	Packages  map[string]pnpmPackage `yaml:"packages"`
+	Snapshots map[string]pnpmPackage `yaml:"snapshots"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]pnpmRef `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmRef `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmRef `yaml:"optionalDependencies"`
+}
+
+// pnpmRef is the resolved version of an importer's dependency, written as
+// a plain string before version 6 and as {specifier, version} since
+type pnpmRef string
+
+func (r *pnpmRef) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*r = pnpmRef(node.Value)
+		return nil
+	}
+	var v struct {
+		Version string `yaml:"version"`
+	}
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	*r = pnpmRef(v.Version)
+	return nil
+}
+
+// pnpmPackage is an entry of the packages or snapshots sections. Since
+// version 9 packages holds the metadata and snapshots the dependencies.
+type pnpmPackage struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Resolution struct {
+		Integrity string `yaml:"integrity"`
+	} `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// ParsePNPM parses a pnpm-lock.yaml. Importer names are read from the
+// package.json files under dir when present.
+func ParsePNPM(lockfile, dir string, data []byte) (*Graph, error) {
+	var lock pnpmLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockfile, err)
+	}
+	major, err := strconv.Atoi(strings.SplitN(strings.TrimPrefix(lock.LockfileVersion, "v"), ".", 2)[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: unsupported lockfile version %q", lockfile, lock.LockfileVersion)
+	}
+
+	g := newGraph(lockfile, NPM)
+	entries := lock.Packages
+	if major >= 9 {
+		entries = lock.Snapshots
+	}
+
+	keys := map[string]string{}
+	for depPath := range entries {
+		meta, ok := lock.Packages[depPath]
+		if !ok {
+			meta = lock.Packages[pnpmStripPeers(depPath, major)]
+		}
+		name, version := pnpmNameVersion(depPath, major)
+		if meta.Name != "" {
+			name = meta.Name
+		}
+		if meta.Version != "" {
+			version = meta.Version
+		}
+		if name == "" || version == "" {
+			continue
+		}
+		pkg := g.addPackage(name, version)
+		if pkg.Hashes == nil {
+			pkg.Hashes = integrityHashes(meta.Resolution.Integrity)
+		}
+		keys[depPath] = pkg.Key()
+	}
+
+	for depPath, entry := range entries {
+		key, ok := keys[depPath]
+		if !ok {
+			continue
+		}
+		pkg := g.Packages[key]
+		for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies} {
+			for name, ref := range deps {
+				if k, ok := keys[pnpmDepPath(name, ref, major)]; ok {
+					pkg.Dependencies = append(pkg.Dependencies, k)
+				}
+			}
+		}
+	}
+
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]pnpmImporter{".": lock.Root}
+	}
+	for p, entry := range importers {
+		imp := &Importer{Path: path.Clean(p)}
+		manifest, err := readPackageJSON(filepath.Join(dir, filepath.FromSlash(imp.Path)))
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			imp.Name, imp.Version = manifest.Name, manifest.Version
+		}
+
+		add := func(deps map[string]pnpmRef, dev bool) {
+			for name, ref := range deps {
+				if target, ok := strings.CutPrefix(string(ref), "link:"); ok {
+					imp.Workspaces = append(imp.Workspaces, path.Clean(path.Join(imp.Path, target)))
+					continue
+				}
+				k, ok := keys[pnpmDepPath(name, string(ref), major)]
+				switch {
+				case !ok:
+				case dev:
+					imp.DevDependencies = append(imp.DevDependencies, k)
+				default:
+					imp.Dependencies = append(imp.Dependencies, k)
+				}
+			}
+		}
+		add(entry.Dependencies, false)
+		add(entry.OptionalDependencies, false)
+		add(entry.DevDependencies, true)
+		g.Importers = append(g.Importers, imp)
+	}
+
+	g.finish()
+	return g, nil
+}
+
+// pnpmDepPath returns the key of the packages section a dependency
+// resolved to ref refers to. Aliased dependencies carry their own key.
+func pnpmDepPath(name, ref string, major int) string {
+	switch {
+	case major >= 9:
+		if strings.Contains(pnpmStripPeers(ref, major), "@") {
+			return ref
+		}
+		return name + "@" + ref
+	case strings.HasPrefix(ref, "/"):
+		return ref
+	case major >= 6:
+		return "/" + name + "@" + ref
+	default:
+		return "/" + name + "/" + ref
+	}
+}
+
+// pnpmStripPeers removes the peer dependency suffix from a key:
+// "(react@18.2.0)" since version 6, "_react@18.2.0" before
+func pnpmStripPeers(depPath string, major int) string {
+	if major >= 6 {
+		depPath, _, _ = strings.Cut(depPath, "(")
+		return depPath
+	}
+	i := strings.LastIndex(depPath, "/")
+	if j := strings.Index(depPath[i+1:], "_"); j >= 0 {
+		return depPath[:i+1+j]
+	}
+	return depPath
+}
+
+// pnpmNameVersion extracts the name and version from a registry package
+// key, "/name/1.0.0" before version 6 and "/name@1.0.0" or "name@1.0.0"
+// since
+func pnpmNameVersion(depPath string, major int) (name, version string) {
+	s := strings.TrimPrefix(pnpmStripPeers(depPath, major), "/")
+	if major >= 6 {
+		name, version, _ = splitName(s)
+		return name, version
+	}
+	i := strings.LastIndex(s, "/")
+	if i <= 0 {
+		return "", ""
+	}
+	return s[:i], s[i+1:]
+}
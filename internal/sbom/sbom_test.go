@@ -0,0 +1,188 @@
+package sbom
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// describe renders a graph as one line per importer and package
+func describe(g *Graph) []string {
+	var lines []string
+	for _, imp := range g.Importers {
+		lines = append(lines, fmt.Sprintf("importer %s %s@%s deps=%s dev=%s workspaces=%s", imp.Path, imp.Name, imp.Version,
+			strings.Join(imp.Dependencies, ","), strings.Join(imp.DevDependencies, ","), strings.Join(imp.Workspaces, ",")))
+	}
+	keys := make([]string, 0, len(g.Packages))
+	for k := range g.Packages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := g.Packages[k]
+		line := k
+		if p.Direct {
+			line += " direct"
+		}
+		if p.Dev {
+			line += " dev"
+		}
+		if len(p.Dependencies) > 0 {
+			line += " -> " + strings.Join(p.Dependencies, ",")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestScanFixtures(t *testing.T) {
+	tests := []struct {
+		dir       string
+		lockfile  string
+		ecosystem Ecosystem
+		want      []string
+	}{
+		{"yarn-classic", "yarn.lock", NPM, []string{
+			"importer . app@1.0.0 deps=debug@4.3.4 dev=@babel/code-frame@7.22.13,minimist@1.2.8 workspaces=",
+			"@babel/code-frame@7.22.13 direct dev -> @babel/highlight@7.22.20",
+			"@babel/highlight@7.22.20 dev",
+			"debug@4.3.4 direct -> ms@2.1.2",
+			"minimist@1.2.8 direct dev",
+			"ms@2.1.2",
+		}},
+		{"yarn-berry", "yarn.lock", NPM, []string{
+			"importer . app@ deps=lodash@4.17.21,strip-ansi@6.0.1 dev=typescript@5.3.3 workspaces=",
+			"ansi-regex@5.0.1",
+			"lodash@4.17.21 direct",
+			"strip-ansi@6.0.1 direct -> ansi-regex@5.0.1",
+			"typescript@5.3.3 direct dev",
+		}},
+		{"pnpm", "pnpm-lock.yaml", NPM, []string{
+			"importer . app@2.0.0 deps=express@4.18.2,react-dom@18.2.0,react@18.2.0 dev=vitest@1.0.0 workspaces=",
+			"express@4.18.2 direct -> qs@6.11.0",
+			"qs@6.11.0",
+			"react-dom@18.2.0 direct -> react@18.2.0",
+			"react@18.2.0 direct",
+			"vitest@1.0.0 direct dev -> qs@6.11.0",
+		}},
+		{"pnpm-v6", "pnpm-lock.yaml", NPM, []string{
+			"importer . @ deps=debug@4.3.4 dev= workspaces=",
+			"debug@4.3.4 direct -> ms@2.1.2",
+			"ms@2.1.2",
+		}},
+		{"composer", "composer.lock", Composer, []string{
+			"importer . acme/app@ deps=guzzlehttp/guzzle@7.8.1 dev=phpunit/phpunit@10.5.5 workspaces=",
+			"guzzlehttp/guzzle@7.8.1 direct -> guzzlehttp/psr7@2.6.2",
+			"guzzlehttp/psr7@2.6.2",
+			"phpunit/phpunit@10.5.5 direct dev -> sebastian/diff@5.1.0",
+			"sebastian/diff@5.1.0 dev",
+		}},
+		{"workspace", "package-lock.json", NPM, []string{
+			"importer . monorepo@ deps= dev=typescript@5.3.3 workspaces=",
+			"importer packages/api @acme/api@1.0.0 deps=express@4.18.2 dev= workspaces=packages/shared",
+			"importer packages/shared @acme/shared@1.0.0 deps=ms@2.1.3 dev= workspaces=",
+			// express gets its own copy of ms from its nested node_modules
+			"express@4.18.2 direct -> ms@2.0.0",
+			"ms@2.0.0",
+			"ms@2.1.3 direct",
+			"typescript@5.3.3 direct dev",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			graphs, err := Scan(filepath.Join("testdata", tt.dir))
+			if err != nil {
+				t.Fatalf("Scan() = %v", err)
+			}
+			if len(graphs) != 1 {
+				t.Fatalf("Scan() found %d lockfiles, want 1", len(graphs))
+			}
+			g := graphs[0]
+			if g.Lockfile != tt.lockfile || g.Ecosystem != tt.ecosystem {
+				t.Errorf("graph of %s (%s), want %s (%s)", g.Lockfile, g.Ecosystem, tt.lockfile, tt.ecosystem)
+			}
+			if got := describe(g); !slices.Equal(got, tt.want) {
+				t.Errorf("graph:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestScanHashes(t *testing.T) {
+	// The fixtures' integrity fields hash "<name>-<version>"
+	sha512Of := func(s string) string {
+		sum := sha512.Sum512([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	tests := []struct {
+		dir, key string
+		want     []Hash
+	}{
+		{"yarn-classic", "@babel/code-frame@7.22.13", []Hash{{Alg: "SHA-512", Content: sha512Of("@babel/code-frame-7.22.13")}}},
+		{"pnpm", "react-dom@18.2.0", []Hash{{Alg: "SHA-512", Content: sha512Of("react-dom-18.2.0")}}},
+		{"workspace", "ms@2.0.0", []Hash{{Alg: "SHA-512", Content: sha512Of("ms-2.0.0")}}},
+		{"composer", "guzzlehttp/psr7@2.6.2", []Hash{{Alg: "SHA-1", Content: "3c9a6e4f0e6b2a8d7c5f1e9b8a7d6c5b4a3f2e1d"}}},
+		// Yarn berry checksums are not archive integrity hashes
+		{"yarn-berry", "lodash@4.17.21", nil},
+		{"composer", "guzzlehttp/guzzle@7.8.1", nil},
+	}
+	for _, tt := range tests {
+		graphs, err := Scan(filepath.Join("testdata", tt.dir))
+		if err != nil {
+			t.Fatalf("Scan(%s) = %v", tt.dir, err)
+		}
+		p := graphs[0].Packages[tt.key]
+		if p == nil {
+			t.Fatalf("%s: no package %s", tt.dir, tt.key)
+		}
+		if !slices.Equal(p.Hashes, tt.want) {
+			t.Errorf("%s: %s hashes = %v, want %v", tt.dir, tt.key, p.Hashes, tt.want)
+		}
+	}
+}
+
+func TestNewBOMWorkspace(t *testing.T) {
+	graphs, err := Scan(filepath.Join("testdata", "workspace"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bom := NewBOM("workspace", graphs, "codeclarity-test")
+
+	if bom.Metadata.Component.Name != "monorepo" {
+		t.Errorf("metadata component = %q, want monorepo", bom.Metadata.Component.Name)
+	}
+	components := map[string]Component{}
+	for _, c := range bom.Components {
+		components[c.BOMRef] = c
+	}
+	api, ok := components["project:packages/api"]
+	if !ok || api.Type != "application" || api.Name != "@acme/api" {
+		t.Errorf("workspace component = %+v", api)
+	}
+	if c := components["pkg:npm/typescript@5.3.3"]; c.Scope != "optional" {
+		t.Errorf("typescript scope = %q, want optional", c.Scope)
+	}
+	if c := components["pkg:npm/ms@2.0.0"]; c.Scope != "required" {
+		t.Errorf("ms@2.0.0 scope = %q, want required", c.Scope)
+	}
+
+	dependsOn := map[string][]string{}
+	for _, d := range bom.Dependencies {
+		dependsOn[d.Ref] = d.DependsOn
+	}
+	want := map[string][]string{
+		"project:.":              {"pkg:npm/typescript@5.3.3", "project:packages/api", "project:packages/shared"},
+		"project:packages/api":   {"pkg:npm/express@4.18.2", "project:packages/shared"},
+		"pkg:npm/express@4.18.2": {"pkg:npm/ms@2.0.0"},
+	}
+	for ref, deps := range want {
+		if !slices.Equal(dependsOn[ref], deps) {
+			t.Errorf("%s depends on %v, want %v", ref, dependsOn[ref], deps)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package sbom
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"codeclarity.io/internal/snapshot"
+)
+
+// parsers maps lockfile names to the function parsing them
+var parsers = map[string]func(lockfile, dir string, data []byte) (*Graph, error){
+	"package-lock.json": ParseNPM,
+	"yarn.lock":         ParseYarn,
+	"pnpm-lock.yaml":    ParsePNPM,
+	"composer.lock":     ParseComposer,
+}
+
+// Scan parses every lockfile under root, skipping the ones ignored the way
+// snapshot.Collect does. Graphs are ordered by lockfile path.
+func Scan(root string) ([]*Graph, error) {
+	files, err := snapshot.Collect(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var graphs []*Graph
+	for _, f := range files {
+		parse, ok := parsers[path.Base(f.Path)]
+		if !ok {
+			continue
+		}
+		name := filepath.Join(root, filepath.FromSlash(f.Path))
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		g, err := parse(f.Path, filepath.Dir(name), data)
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, g)
+	}
+	return graphs, nil
+}
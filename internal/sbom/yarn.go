@@ -0,0 +1,268 @@
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yarnEntry is a resolved package of a yarn.lock, shared by all the
+// descriptors ("name@range") that resolve to it
+type yarnEntry struct {
+	descriptors []string
+	version     string
+	// resolution is only recorded by yarn berry
+	resolution   string
+	integrity    string
+	dependencies map[string]string
+}
+
+// ParseYarn parses a yarn.lock of either yarn classic (v1) or yarn berry
+// (v2 and later). The lockfile does not record which packages the projects
+// depend on, so package.json is read from dir and its workspaces.
+func ParseYarn(lockfile, dir string, data []byte) (*Graph, error) {
+	var (
+		entries []*yarnEntry
+		err     error
+	)
+	berry := bytes.HasPrefix(data, []byte("__metadata:")) || bytes.Contains(data, []byte("\n__metadata:"))
+	if berry {
+		entries, err = parseYarnBerry(data)
+	} else {
+		entries, err = parseYarnClassic(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", lockfile, err)
+	}
+
+	g := newGraph(lockfile, NPM)
+	byDescriptor := map[string]string{}
+	workspaceByName := map[string]string{}
+	for _, e := range entries {
+		name := yarnDescriptorName(e.descriptors[0])
+		if berry {
+			if n, ref, ok := splitName(e.resolution); ok {
+				name = n
+				if ws, ok := strings.CutPrefix(ref, "workspace:"); ok {
+					workspaceByName[name] = path.Clean(ws)
+					continue
+				}
+			}
+		}
+		pkg := g.addPackage(name, e.version)
+		if pkg.Hashes == nil {
+			pkg.Hashes = integrityHashes(e.integrity)
+		}
+		for _, d := range e.descriptors {
+			byDescriptor[d] = pkg.Key()
+		}
+	}
+
+	// lookup resolves a dependency to a package key or a workspace path
+	lookup := func(name, spec string) (key, workspace string) {
+		if k, ok := byDescriptor[name+"@"+spec]; ok {
+			return k, ""
+		}
+		if k, ok := byDescriptor[name+"@npm:"+spec]; ok {
+			return k, ""
+		}
+		return "", workspaceByName[name]
+	}
+
+	for _, e := range entries {
+		key, ok := byDescriptor[e.descriptors[0]]
+		if !ok {
+			continue
+		}
+		pkg := g.Packages[key]
+		for name, spec := range e.dependencies {
+			if k, _ := lookup(name, spec); k != "" {
+				pkg.Dependencies = append(pkg.Dependencies, k)
+			}
+		}
+	}
+
+	root, err := readPackageJSON(dir)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%s has no package.json next to it", lockfile)
+	}
+	paths, err := expandWorkspaces(dir, root.Workspaces)
+	if err != nil {
+		return nil, err
+	}
+	manifests := map[string]*packageJSON{".": root}
+	for _, p := range paths {
+		m, err := readPackageJSON(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		manifests[p] = m
+		if m.Name != "" && workspaceByName[m.Name] == "" {
+			workspaceByName[m.Name] = p
+		}
+	}
+
+	for p, m := range manifests {
+		imp := &Importer{Path: p, Name: m.Name, Version: m.Version}
+		for name, spec := range m.prodDependencies() {
+			key, ws := lookup(name, spec)
+			switch {
+			case key != "":
+				imp.Dependencies = append(imp.Dependencies, key)
+			case ws != "" && ws != p:
+				imp.Workspaces = append(imp.Workspaces, ws)
+			}
+		}
+		for name, spec := range m.DevDependencies {
+			key, ws := lookup(name, spec)
+			switch {
+			case key != "":
+				imp.DevDependencies = append(imp.DevDependencies, key)
+			case ws != "" && ws != p:
+				imp.Workspaces = append(imp.Workspaces, ws)
+			}
+		}
+		g.Importers = append(g.Importers, imp)
+	}
+
+	g.finish()
+	return g, nil
+}
+
+// yarnDescriptorName returns the package name of a "name@range" descriptor
+func yarnDescriptorName(d string) string {
+	if len(d) < 2 {
+		return d
+	}
+	if i := strings.Index(d[1:], "@"); i >= 0 {
+		return d[:i+1]
+	}
+	return d
+}
+
+// parseYarnClassic reads the indentation-based format of yarn v1:
+//
+//	"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+//	  version "7.1.2"
+//	  dependencies:
+//	    debug "^4.1.0"
+func parseYarnClassic(data []byte) ([]*yarnEntry, error) {
+	var (
+		entries []*yarnEntry
+		current *yarnEntry
+		section string
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(text) - len(trimmed)
+
+		switch {
+		case indent == 0:
+			if !strings.HasSuffix(trimmed, ":") {
+				return nil, fmt.Errorf("line %d: expected an entry", line)
+			}
+			current = &yarnEntry{dependencies: map[string]string{}}
+			for _, d := range strings.Split(strings.TrimSuffix(trimmed, ":"), ",") {
+				current.descriptors = append(current.descriptors, yarnUnquote(strings.TrimSpace(d)))
+			}
+			entries = append(entries, current)
+			section = ""
+		case current == nil:
+			return nil, fmt.Errorf("line %d: unexpected indentation", line)
+		case indent == 2 && strings.HasSuffix(trimmed, ":"):
+			section = strings.TrimSuffix(trimmed, ":")
+		case indent == 2:
+			section = ""
+			key, value := yarnField(trimmed)
+			switch key {
+			case "version":
+				current.version = value
+			case "integrity":
+				current.integrity = value
+			}
+		default:
+			if section == "dependencies" || section == "optionalDependencies" {
+				name, spec := yarnField(trimmed)
+				current.dependencies[name] = spec
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// yarnField splits a `key "value"` line
+func yarnField(s string) (key, value string) {
+	key, value, _ = strings.Cut(s, " ")
+	if strings.HasPrefix(key, `"`) {
+		if end := strings.Index(s[1:], `"`); end >= 0 {
+			key, value = s[1:end+1], s[end+2:]
+		}
+	}
+	return yarnUnquote(key), yarnUnquote(strings.TrimSpace(value))
+}
+
+func yarnUnquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// yarnBerryEntry is an entry of a yarn berry lockfile, which is YAML
+type yarnBerryEntry struct {
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+func parseYarnBerry(data []byte) ([]*yarnEntry, error) {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var entries []*yarnEntry
+	for key, node := range doc {
+		if key == "__metadata" {
+			continue
+		}
+		var be yarnBerryEntry
+		if err := node.Decode(&be); err != nil {
+			return nil, fmt.Errorf("entry %s: %w", key, err)
+		}
+		e := &yarnEntry{
+			version:      be.Version,
+			resolution:   be.Resolution,
+			dependencies: map[string]string{},
+		}
+		for _, d := range strings.Split(key, ",") {
+			e.descriptors = append(e.descriptors, strings.TrimSpace(d))
+		}
+		for _, deps := range []map[string]string{be.Dependencies, be.OptionalDependencies} {
+			for name, spec := range deps {
+				e.dependencies[name] = spec
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
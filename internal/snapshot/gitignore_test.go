@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		// Patterns without a slash match at any depth
+		{"basename", "yarn.lock", "yarn.lock", false, true},
+		{"basename nested", "yarn.lock", "web/app/yarn.lock", false, true},
+		{"basename glob", "*.lock", "web/composer.lock", false, true},
+		{"glob stops at slash", "web*", "web/package.json", false, false},
+		{"question mark", "package-lock.jso?", "package-lock.json", false, true},
+		{"class", "[cy]*.lock", "yarn.lock", false, true},
+		{"negated class", "[!y]*.lock", "yarn.lock", false, false},
+
+		// A leading or inner slash anchors to the .gitignore's directory
+		{"anchored", "/yarn.lock", "yarn.lock", false, true},
+		{"anchored not nested", "/yarn.lock", "web/yarn.lock", false, false},
+		{"inner slash", "web/yarn.lock", "web/yarn.lock", false, true},
+		{"inner slash not nested", "web/yarn.lock", "apps/web/yarn.lock", false, false},
+
+		// "**" spans directories
+		{"leading double star", "**/fixtures", "test/data/fixtures", true, true},
+		{"leading double star at root", "**/fixtures", "fixtures", true, true},
+		{"trailing double star", "fixtures/**", "fixtures/a/package.json", false, true},
+		{"trailing double star not the directory", "fixtures/**", "fixtures", true, false},
+		{"inner double star", "a/**/yarn.lock", "a/b/c/yarn.lock", false, true},
+		{"inner double star zero dirs", "a/**/yarn.lock", "a/yarn.lock", false, true},
+
+		// Rules ending with a slash only match directories
+		{"dir only", "build/", "build", true, true},
+		{"dir only file", "build/", "build", false, false},
+		{"dir only nested", "build/", "web/build", true, true},
+
+		// The last matching rule wins
+		{"negation", "*.lock\n!composer.lock", "composer.lock", false, false},
+		{"negation overridden", "!composer.lock\n*.lock", "composer.lock", false, true},
+		{"escaped bang", `\!important`, "!important", false, true},
+		{"escaped hash", `\#lock`, "#lock", false, true},
+
+		{"comment", "# yarn.lock", "# yarn.lock", false, false},
+		{"blank and trailing spaces", "\n  \nyarn.lock   ", "yarn.lock", false, true},
+		{"escaped trailing space", `yarn.lock\ `, "yarn.lock ", false, true},
+		{"crlf", "yarn.lock\r", "yarn.lock", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ig ignoreFile
+			for _, line := range strings.Split(tt.rules, "\n") {
+				if rule, ok := parseIgnoreRule(line); ok {
+					ig.rules = append(ig.rules, rule)
+				}
+			}
+			if _, ignored := ig.match(tt.path, tt.isDir); ignored != tt.ignored {
+				t.Errorf("%q matching %s: ignored = %v, want %v", tt.rules, tt.path, ignored, tt.ignored)
+			}
+		})
+	}
+}
+
+func TestCollectIgnores(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":                         "yarn.lock\n/build/\n!keep/yarn.lock\n",
+		"package.json":                       "{}",
+		"yarn.lock":                          "",
+		"keep/yarn.lock":                     "",
+		"keep/package.json":                  "{}",
+		"build/package.json":                 "{}",
+		"web/build/package.json":             "{}",
+		"web/.gitignore":                     "composer.lock\n",
+		"web/composer.json":                  "{}",
+		"web/composer.lock":                  "",
+		"composer.lock":                      "",
+		"api/.gitignore":                     "!yarn.lock\n",
+		"api/yarn.lock":                      "",
+		"node_modules/qs/package.json":       "{}",
+		"examples/node_modules/package.json": "{}",
+		"README.md":                          "",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collected, err := Collect(root)
+	if err != nil {
+		t.Fatalf("Collect() = %v", err)
+	}
+	var got []string
+	for _, f := range collected {
+		got = append(got, f.Path)
+	}
+	want := []string{
+		// A deeper .gitignore re-includes what its parent ignores
+		"api/yarn.lock",
+		"composer.lock",
+		"keep/package.json",
+		"keep/yarn.lock",
+		"package.json",
+		// build/ is anchored to the root
+		"web/build/package.json",
+		"web/composer.json",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Collect() = %v, want %v", got, want)
+	}
+}
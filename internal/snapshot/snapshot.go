@@ -0,0 +1,169 @@
+// Package snapshot packages the dependency manifests and lockfiles of a
+// local source tree for analysis.
+//
+// Files are collected the way git would see them: paths ignored by the
+// .gitignore files of the tree are skipped, as are .git, node_modules and
+// vendor directories, which never hold the project's own manifests.
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxFileSize bounds the size of each packaged file
+	MaxFileSize = 50 << 20
+	// MaxFiles bounds the number of packaged files
+	MaxFiles = 1000
+)
+
+// ManifestNames lists the file names packaged into a snapshot
+var ManifestNames = []string{
+	"package.json",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"composer.json",
+	"composer.lock",
+}
+
+// skippedDirs are never walked
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// File is a file selected for a snapshot
+type File struct {
+	// Path is slash-separated and relative to the snapshot root
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Collect walks root and returns its manifests and lockfiles that are not
+// ignored, ordered by path
+func Collect(root string) ([]File, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range ManifestNames {
+		wanted[name] = true
+	}
+
+	ignores := &ignoreSet{files: map[string]*ignoreFile{}}
+	var files []File
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (skippedDirs[d.Name()] || ignores.ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			ig, err := loadIgnoreFile(filepath.Join(p, ".gitignore"))
+			if err != nil {
+				return err
+			}
+			if ig != nil {
+				ignores.files[rel] = ig
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || !wanted[d.Name()] || ignores.ignored(rel, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > MaxFileSize {
+			return fmt.Errorf("%s is larger than %d MB", rel, MaxFileSize>>20)
+		}
+		if len(files) == MaxFiles {
+			return fmt.Errorf("more than %d manifests found", MaxFiles)
+		}
+		files = append(files, File{Path: rel, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// Write archives files, read from root, to w as a gzipped tar. Entries have
+// fixed modes and timestamps so the same files give the same archive.
+func Write(w io.Writer, root string, files []File) error {
+	if len(files) == 0 {
+		return errors.New("no files to package")
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		if err := addFile(tw, root, f); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, root string, f File) error {
+	if !fs.ValidPath(f.Path) || path.Clean(f.Path) != f.Path {
+		return fmt.Errorf("invalid path %q", f.Path)
+	}
+	src, err := os.Open(filepath.Join(root, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:     f.Path,
+		Mode:     0o644,
+		Size:     info.Size(),
+		ModTime:  time.Unix(0, 0).UTC(),
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, src); err != nil {
+		return fmt.Errorf("failed to package %s: %w", f.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Package store keeps synced projects, analyses and results on disk so they
+// can be inspected without reaching the server.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"codeclarity.io/internal/config"
+	"codeclarity.io/pkg/codeclarity"
+)
+
+const (
+	// DirName is the store directory inside the config directory
+	DirName = "store"
+	// StaleAfter is the age after which synced data is reported as stale
+	StaleAfter = 24 * time.Hour
+
+	projectFile = "project.json"
+	filePerms   = 0600
+	dirPerms    = 0700
+)
+
+// ErrNotSynced is returned when the requested data has not been synced
+var ErrNotSynced = errors.New("not synced")
+
+// ProjectSnapshot is a project and its analyses as of SyncedAt
+type ProjectSnapshot struct {
+	Project  codeclarity.Project    `json:"project"`
+	Analyses []codeclarity.Analysis `json:"analyses"`
+	SyncedAt time.Time              `json:"synced_at"`
+}
+
+// AnalysisSnapshot holds the results of one analysis as of SyncedAt
+type AnalysisSnapshot struct {
+	Analysis           codeclarity.Analysis            `json:"analysis"`
+	Workspace          string                          `json:"workspace"`
+	VulnerabilityStats *codeclarity.VulnerabilityStats `json:"vulnerability_stats,omitempty"`
+	SBOMStats          *codeclarity.SBOMStats          `json:"sbom_stats,omitempty"`
+	LicenseStats       *codeclarity.LicenseStats       `json:"license_stats,omitempty"`
+	Vulnerabilities    []codeclarity.Vulnerability     `json:"vulnerabilities"`
+	SBOM               []codeclarity.Dependency        `json:"sbom,omitempty"`
+	Licenses           []codeclarity.License           `json:"licenses,omitempty"`
+	SyncedAt           time.Time                       `json:"synced_at"`
+}
+
+// Store reads and writes snapshots under a directory laid out as
+// <org>/<project>/project.json and <org>/<project>/<analysis>.json
+type Store struct {
+	dir string
+}
+
+// Dir returns the default store directory
+func Dir() (string, error) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, DirName), nil
+}
+
+// Open returns the store in the default directory
+func Open() (*Store, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return New(dir), nil
+}
+
+// New returns a store rooted at dir
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NormalizeWorkspace maps the empty workspace to the root workspace "."
+func NormalizeWorkspace(workspace string) string {
+	if workspace == "" {
+		return "."
+	}
+	return workspace
+}
+
+// SaveProject writes a project snapshot
+func (s *Store) SaveProject(orgID string, snap *ProjectSnapshot) error {
+	dir, err := s.projectDir(orgID, snap.Project.ID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, projectFile), snap)
+}
+
+// Project reads a project snapshot
+func (s *Store) Project(orgID, projectID string) (*ProjectSnapshot, error) {
+	dir, err := s.projectDir(orgID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var snap ProjectSnapshot
+	if err := readJSON(filepath.Join(dir, projectFile), &snap); err != nil {
+		if errors.Is(err, ErrNotSynced) {
+			return nil, fmt.Errorf("project %s %w: run 'codeclarity sync %s'", projectID, ErrNotSynced, projectID)
+		}
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// SaveAnalysis writes the results of an analysis of projectID
+func (s *Store) SaveAnalysis(orgID, projectID string, snap *AnalysisSnapshot) error {
+	path, err := s.analysisPath(orgID, projectID, snap.Analysis.ID)
+	if err != nil {
+		return err
+	}
+	snap.Workspace = NormalizeWorkspace(snap.Workspace)
+	return writeJSON(path, snap)
+}
+
+// Analysis reads the stored results of an analysis for workspace
+func (s *Store) Analysis(orgID, projectID, analysisID, workspace string) (*AnalysisSnapshot, error) {
+	path, err := s.analysisPath(orgID, projectID, analysisID)
+	if err != nil {
+		return nil, err
+	}
+	var snap AnalysisSnapshot
+	if err := readJSON(path, &snap); err != nil {
+		if errors.Is(err, ErrNotSynced) {
+			return nil, fmt.Errorf("results of analysis %s %w: run 'codeclarity sync %s'", analysisID, ErrNotSynced, projectID)
+		}
+		return nil, err
+	}
+	if want := NormalizeWorkspace(workspace); snap.Workspace != want {
+		return nil, fmt.Errorf("analysis %s was synced for workspace %q, not %q: run 'codeclarity sync %s --workspace %s'",
+			analysisID, snap.Workspace, want, projectID, want)
+	}
+	return &snap, nil
+}
+
+// HasAnalysis reports whether results of an analysis are stored for workspace
+func (s *Store) HasAnalysis(orgID, projectID, analysisID, workspace string) bool {
+	_, err := s.Analysis(orgID, projectID, analysisID, workspace)
+	return err == nil
+}
+
+func (s *Store) projectDir(orgID, projectID string) (string, error) {
+	if err := checkID(orgID); err != nil {
+		return "", err
+	}
+	if err := checkID(projectID); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, orgID, projectID), nil
+}
+
+func (s *Store) analysisPath(orgID, projectID, analysisID string) (string, error) {
+	dir, err := s.projectDir(orgID, projectID)
+	if err != nil {
+		return "", err
+	}
+	if err := checkID(analysisID); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, analysisID+".json"), nil
+}
+
+// checkID rejects identifiers that would escape the store directory
+func checkID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("invalid identifier %q", id)
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPerms); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, filePerms); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotSynced
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("corrupt store file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Describe tells the user which synced data is shown and how old it is,
+// flagging data older than StaleAfter
+func Describe(syncedAt time.Time) string {
+	age := time.Since(syncedAt)
+	msg := fmt.Sprintf("Offline: showing data synced %s ago (%s)", FormatAge(age), syncedAt.Local().Format("2006-01-02 15:04"))
+	if age > StaleAfter {
+		msg += ", data may be stale: run 'codeclarity sync' when online"
+	}
+	return msg
+}
+
+// FormatAge renders a duration compactly as seconds, minutes, hours or days
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// Paginate returns one page of items shaped like an API listing
+func Paginate[T any](items []T, page, perPage int) *codeclarity.PaginatedResponse[T] {
+	if page < 0 {
+		page = 0
+	}
+	if perPage <= 0 {
+		perPage = len(items)
+	}
+	start := min(page*perPage, len(items))
+	end := min(start+perPage, len(items))
+
+	totalPages := 1
+	if perPage > 0 {
+		totalPages = (len(items) + perPage - 1) / perPage
+	}
+	return &codeclarity.PaginatedResponse[T]{
+		Data:           items[start:end],
+		Page:           page,
+		EntryCount:     end - start,
+		EntriesPerPage: perPage,
+		TotalEntries:   len(items),
+		TotalPages:     totalPages,
+		MatchingCount:  len(items),
+	}
+}
@@ -0,0 +1,44 @@
+package suppress
+
+import (
+	"codeclarity.io/internal/output"
+	"github.com/spf13/pflag"
+)
+
+// Flags holds the --ignore-file and --no-ignore options of a command
+type Flags struct {
+	File     string
+	Disabled bool
+}
+
+// Register adds --ignore-file and --no-ignore to a flag set
+func (f *Flags) Register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.File, "ignore-file", DefaultFileName, "Suppression file listing accepted risks")
+	fs.BoolVar(&f.Disabled, "no-ignore", false, "Do not apply the suppression file")
+}
+
+// Load loads the suppression file unless disabled with --no-ignore
+func (f *Flags) Load() (*Set, error) {
+	if f.Disabled {
+		return &Set{}, nil
+	}
+	return Load(f.File)
+}
+
+// Report warns about expired, expiring and (optionally) unused suppressions
+func Report(set *Set, checkUnused bool) {
+	if set.Empty() {
+		return
+	}
+	for _, rule := range set.Expired() {
+		output.Notice("Suppression %s in %s expired on %s and is no longer applied", rule.String(), set.Path, rule.Expires)
+	}
+	for _, rule := range set.ExpiringSoon() {
+		output.Notice("Suppression %s in %s expires on %s", rule.String(), set.Path, rule.Expires)
+	}
+	if checkUnused {
+		for _, rule := range set.Unused() {
+			output.Notice("Suppression %s in %s no longer matches any vulnerability", rule.String(), set.Path)
+		}
+	}
+}
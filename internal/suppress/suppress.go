@@ -0,0 +1,288 @@
+package suppress
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFileName is the repository-local suppression file
+	DefaultFileName = ".codeclarity-ignore.yaml"
+
+	// DateLayout is the layout used for expiry dates
+	DateLayout = "2006-01-02"
+
+	// ExpiryWarningWindow is how long before expiry a suppression is reported
+	ExpiryWarningWindow = 14 * 24 * time.Hour
+)
+
+// File represents the contents of a suppression file
+type File struct {
+	Suppressions []Rule `yaml:"suppressions"`
+}
+
+// Rule represents a single accepted risk
+type Rule struct {
+	ID            string `yaml:"id"`
+	Package       string `yaml:"package,omitempty"`
+	Version       string `yaml:"version,omitempty"`
+	Justification string `yaml:"justification"`
+	Expires       string `yaml:"expires,omitempty"`
+
+	expiry *time.Time
+}
+
+// ExpiresOn returns the parsed expiry date, or nil if the rule never expires
+func (r *Rule) ExpiresOn() *time.Time {
+	return r.expiry
+}
+
+// String returns a short description of the rule
+func (r *Rule) String() string {
+	s := r.ID
+	if r.Package != "" {
+		s += " (" + r.Package
+		if r.Version != "" {
+			s += "@" + r.Version
+		}
+		s += ")"
+	}
+	return s
+}
+
+// matches checks whether the rule applies to a vulnerability. A scoped rule
+// needs one affected entry matching both its ID and its package.
+func (r *Rule) matches(v *codeclarity.Vulnerability) bool {
+	if r.Package == "" {
+		if strings.EqualFold(r.ID, v.ID) {
+			return true
+		}
+		for _, a := range v.Affected {
+			if strings.EqualFold(r.ID, a.VulnerabilityId) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, a := range v.Affected {
+		if r.matchesEntry(v, &a) && a.AffectedDependency == r.Package &&
+			(r.Version == "" || a.AffectedVersion == r.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEntry checks whether the rule ID designates an affected entry. The
+// merged vulnerability ID covers an entry unless another entry carries the
+// rule ID itself.
+func (r *Rule) matchesEntry(v *codeclarity.Vulnerability, a *codeclarity.AffectedVuln) bool {
+	if strings.EqualFold(r.ID, a.VulnerabilityId) {
+		return true
+	}
+	if !strings.EqualFold(r.ID, v.ID) {
+		return false
+	}
+	for _, other := range v.Affected {
+		if strings.EqualFold(r.ID, other.VulnerabilityId) {
+			return false
+		}
+	}
+	return true
+}
+
+// Set holds loaded suppression rules and tracks which ones were used. It is
+// safe for concurrent use.
+type Set struct {
+	Path  string
+	rules []Rule
+	now   time.Time
+
+	mu   sync.Mutex
+	used []bool
+}
+
+// Load reads a suppression file. A missing file yields an empty set.
+func Load(path string) (*Set, error) {
+	set := &Set{Path: path, now: time.Now()}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return set, nil
+		}
+		return nil, err
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for i, rule := range file.Suppressions {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("%s: suppression #%d has no id", path, i+1)
+		}
+		if strings.TrimSpace(rule.Justification) == "" {
+			return nil, fmt.Errorf("%s: suppression %s has no justification", path, rule.ID)
+		}
+		if rule.Version != "" && rule.Package == "" {
+			return nil, fmt.Errorf("%s: suppression %s sets a version without a package", path, rule.ID)
+		}
+		if rule.Expires != "" {
+			expiry, err := time.Parse(DateLayout, rule.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("%s: suppression %s has invalid expiry %q (expected YYYY-MM-DD)", path, rule.ID, rule.Expires)
+			}
+			rule.expiry = &expiry
+		}
+		set.rules = append(set.rules, rule)
+	}
+	set.used = make([]bool, len(set.rules))
+
+	return set, nil
+}
+
+// Empty reports whether the set contains no rules
+func (s *Set) Empty() bool {
+	return s == nil || len(s.rules) == 0
+}
+
+// Rules returns the loaded rules
+func (s *Set) Rules() []Rule {
+	if s == nil {
+		return nil
+	}
+	return s.rules
+}
+
+// expired reports whether a rule is past its expiry date
+func (s *Set) expired(r *Rule) bool {
+	// A suppression stays valid through the whole expiry day
+	return r.expiry != nil && !s.now.Before(r.expiry.AddDate(0, 0, 1))
+}
+
+// Match returns the active rule suppressing a vulnerability, or nil
+func (s *Set) Match(v *codeclarity.Vulnerability) *Rule {
+	if s == nil {
+		return nil
+	}
+	for i := range s.rules {
+		rule := &s.rules[i]
+		if s.expired(rule) || !rule.matches(v) {
+			continue
+		}
+		s.mu.Lock()
+		s.used[i] = true
+		s.mu.Unlock()
+		return rule
+	}
+	return nil
+}
+
+// Filter splits vulnerabilities into kept and suppressed ones
+func (s *Set) Filter(vulns []codeclarity.Vulnerability) (kept, suppressed []codeclarity.Vulnerability) {
+	for _, v := range vulns {
+		if s.Match(&v) != nil {
+			suppressed = append(suppressed, v)
+		} else {
+			kept = append(kept, v)
+		}
+	}
+	return kept, suppressed
+}
+
+// Expired returns rules past their expiry date
+func (s *Set) Expired() []Rule {
+	var out []Rule
+	for i := range s.Rules() {
+		if s.expired(&s.rules[i]) {
+			out = append(out, s.rules[i])
+		}
+	}
+	return out
+}
+
+// ExpiringSoon returns active rules that expire within ExpiryWarningWindow
+func (s *Set) ExpiringSoon() []Rule {
+	var out []Rule
+	for i := range s.Rules() {
+		rule := &s.rules[i]
+		if rule.expiry == nil || s.expired(rule) {
+			continue
+		}
+		if rule.expiry.Sub(s.now) < ExpiryWarningWindow {
+			out = append(out, *rule)
+		}
+	}
+	return out
+}
+
+// Unused returns active rules that did not match any vulnerability so far
+func (s *Set) Unused() []Rule {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Rule
+	for i := range s.Rules() {
+		if !s.used[i] && !s.expired(&s.rules[i]) {
+			out = append(out, s.rules[i])
+		}
+	}
+	return out
+}
+
+// Counts holds vulnerability counts per severity class
+type Counts struct {
+	Total    int `json:"total" yaml:"total"`
+	Critical int `json:"critical" yaml:"critical"`
+	High     int `json:"high" yaml:"high"`
+	Medium   int `json:"medium" yaml:"medium"`
+	Low      int `json:"low" yaml:"low"`
+	None     int `json:"none" yaml:"none"`
+}
+
+// Count tallies vulnerabilities by severity class
+func Count(vulns []codeclarity.Vulnerability) Counts {
+	var c Counts
+	for _, v := range vulns {
+		c.Total++
+		switch strings.ToLower(v.Severity.SeverityClass) {
+		case "critical":
+			c.Critical++
+		case "high":
+			c.High++
+		case "medium":
+			c.Medium++
+		case "low":
+			c.Low++
+		default:
+			c.None++
+		}
+	}
+	return c
+}
+
+// Subtract removes suppressed counts from vulnerability statistics
+func (c Counts) Subtract(stats *codeclarity.VulnerabilityStats) *codeclarity.VulnerabilityStats {
+	if stats == nil {
+		return nil
+	}
+	return &codeclarity.VulnerabilityStats{
+		Total:    max(stats.Total-c.Total, 0),
+		Critical: max(stats.Critical-c.Critical, 0),
+		High:     max(stats.High-c.High, 0),
+		Medium:   max(stats.Medium-c.Medium, 0),
+		Low:      max(stats.Low-c.Low, 0),
+		None:     max(stats.None-c.None, 0),
+	}
+}
@@ -0,0 +1,172 @@
+package suppress
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// loadRules writes a suppression file and loads it as of now
+func loadRules(t *testing.T, yaml string, now time.Time) *Set {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	set, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	set.now = now
+	return set
+}
+
+func vulnerability(id string, affected ...codeclarity.AffectedVuln) codeclarity.Vulnerability {
+	return codeclarity.Vulnerability{ID: id, Affected: affected}
+}
+
+func entry(id, dependency, version string) codeclarity.AffectedVuln {
+	return codeclarity.AffectedVuln{VulnerabilityId: id, AffectedDependency: dependency, AffectedVersion: version}
+}
+
+func TestMatchScope(t *testing.T) {
+	// The merged vulnerability carries the GHSA ID, its entries the CVE and GHSA
+	merged := vulnerability("GHSA-xvch-5gv4-984h",
+		entry("CVE-2021-44906", "minimist", "1.2.5"),
+		entry("GHSA-xvch-5gv4-984h", "minimist", "0.0.8"),
+	)
+	tests := []struct {
+		name  string
+		rule  string
+		vuln  codeclarity.Vulnerability
+		match bool
+	}{
+		{"id", "id: CVE-2022-24999", vulnerability("CVE-2022-24999", entry("CVE-2022-24999", "qs", "6.5.2")), true},
+		{"id case-insensitive", "id: cve-2022-24999", vulnerability("CVE-2022-24999"), true},
+		{"entry id", "id: CVE-2021-44906", merged, true},
+		{"other id", "id: CVE-2022-24999", merged, false},
+		{"package", "id: CVE-2022-24999, package: qs", vulnerability("CVE-2022-24999", entry("CVE-2022-24999", "qs", "6.5.2")), true},
+		{"other package", "id: CVE-2022-24999, package: lodash", vulnerability("CVE-2022-24999", entry("CVE-2022-24999", "qs", "6.5.2")), false},
+		{"version", "id: CVE-2022-24999, package: qs, version: 6.5.2", vulnerability("CVE-2022-24999", entry("CVE-2022-24999", "qs", "6.5.2")), true},
+		{"other version", "id: CVE-2022-24999, package: qs, version: 6.5.3", vulnerability("CVE-2022-24999", entry("CVE-2022-24999", "qs", "6.5.2")), false},
+		// The package scope applies to the entry carrying the ID, not any entry
+		{"entry id in scope", "id: CVE-2021-44906, package: minimist, version: 1.2.5", merged, true},
+		{"entry id out of scope", "id: CVE-2021-44906, package: minimist, version: 0.0.8", merged, false},
+		{"merged id in scope", "id: GHSA-xvch-5gv4-984h, package: minimist, version: 0.0.8", merged, true},
+		{"merged id out of scope", "id: GHSA-xvch-5gv4-984h, package: minimist, version: 1.2.5", merged, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := loadRules(t, "suppressions:\n  - {"+tt.rule+", justification: accepted}\n", time.Now())
+			if got := set.Match(&tt.vuln) != nil; got != tt.match {
+				t.Errorf("Match() = %v, want %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	const rules = `suppressions:
+  - id: CVE-1
+    justification: accepted
+    expires: 2026-03-10
+  - id: CVE-2
+    justification: accepted
+    expires: 2026-03-20
+  - id: CVE-3
+    justification: accepted
+`
+	day := func(d int, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		now      time.Time
+		expired  []string
+		expiring []string
+		match    bool
+	}{
+		{"well before", day(1, 12), nil, []string{"CVE-1"}, true},
+		{"expiry day", day(10, 23), nil, []string{"CVE-1", "CVE-2"}, true},
+		{"day after", day(11, 0), []string{"CVE-1"}, []string{"CVE-2"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := loadRules(t, rules, tt.now)
+			if got := ids(set.Expired()); !slices.Equal(got, tt.expired) {
+				t.Errorf("Expired() = %v, want %v", got, tt.expired)
+			}
+			if got := ids(set.ExpiringSoon()); !slices.Equal(got, tt.expiring) {
+				t.Errorf("ExpiringSoon() = %v, want %v", got, tt.expiring)
+			}
+			v := vulnerability("CVE-1")
+			if got := set.Match(&v) != nil; got != tt.match {
+				t.Errorf("Match() = %v, want %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestUnused(t *testing.T) {
+	const rules = `suppressions:
+  - id: CVE-1
+    justification: accepted
+  - id: CVE-2
+    justification: accepted
+  - id: CVE-3
+    justification: accepted
+    expires: 2020-01-01
+`
+	set := loadRules(t, rules, time.Now())
+	if got := ids(set.Unused()); !slices.Equal(got, []string{"CVE-1", "CVE-2"}) {
+		t.Errorf("Unused() before matching = %v", got)
+	}
+
+	kept, suppressed := set.Filter([]codeclarity.Vulnerability{vulnerability("CVE-1"), vulnerability("CVE-3"), vulnerability("CVE-4")})
+	if len(kept) != 2 || len(suppressed) != 1 || suppressed[0].ID != "CVE-1" {
+		t.Errorf("Filter() kept %d, suppressed %v", len(kept), suppressed)
+	}
+	// Expired rules are reported as expired rather than unused
+	if got := ids(set.Unused()); !slices.Equal(got, []string{"CVE-2"}) {
+		t.Errorf("Unused() = %v, want [CVE-2]", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{"no id", "suppressions:\n  - justification: accepted\n"},
+		{"no justification", "suppressions:\n  - id: CVE-1\n"},
+		{"version without package", "suppressions:\n  - {id: CVE-1, version: 1.0.0, justification: accepted}\n"},
+		{"bad expiry", "suppressions:\n  - {id: CVE-1, expires: 31/12/2026, justification: accepted}\n"},
+		{"bad yaml", "suppressions: [\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFileName)
+			if err := os.WriteFile(path, []byte(tt.rules), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil {
+				t.Error("Load() succeeded, want an error")
+			}
+		})
+	}
+
+	set, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || !set.Empty() {
+		t.Errorf("Load(missing) = %v, %v, want an empty set", set, err)
+	}
+}
+
+func ids(rules []Rule) []string {
+	var out []string
+	for _, r := range rules {
+		out = append(out, r.ID)
+	}
+	return out
+}
@@ -0,0 +1,382 @@
+package vex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Context is the OpenVEX specification version produced and accepted
+	Context = "https://openvex.dev/ns/v0.2.0"
+
+	// Tooling identifies the generator in produced documents
+	Tooling = "codeclarity-cli"
+)
+
+// Status represents the impact status of a vulnerability on a product
+type Status string
+
+const (
+	StatusNotAffected        Status = "not_affected"
+	StatusAffected           Status = "affected"
+	StatusFixed              Status = "fixed"
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Valid reports whether the status is defined by the OpenVEX specification
+func (s Status) Valid() bool {
+	switch s {
+	case StatusNotAffected, StatusAffected, StatusFixed, StatusUnderInvestigation:
+		return true
+	}
+	return false
+}
+
+// Justification explains why a product is not affected
+type Justification string
+
+const (
+	JustificationComponentNotPresent                         Justification = "component_not_present"
+	JustificationVulnerableCodeNotPresent                    Justification = "vulnerable_code_not_present"
+	JustificationVulnerableCodeNotInExecutePath              Justification = "vulnerable_code_not_in_execute_path"
+	JustificationVulnerableCodeCannotBeControlledByAdversary Justification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	JustificationInlineMitigationsAlreadyExist               Justification = "inline_mitigations_already_exist"
+)
+
+// Valid reports whether the justification is defined by the OpenVEX specification
+func (j Justification) Valid() bool {
+	switch j {
+	case "", JustificationComponentNotPresent, JustificationVulnerableCodeNotPresent,
+		JustificationVulnerableCodeNotInExecutePath, JustificationVulnerableCodeCannotBeControlledByAdversary,
+		JustificationInlineMitigationsAlreadyExist:
+		return true
+	}
+	return false
+}
+
+// Document represents an OpenVEX document
+type Document struct {
+	Context    string      `json:"@context"`
+	ID         string      `json:"@id"`
+	Author     string      `json:"author"`
+	Role       string      `json:"role,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Version    int         `json:"version"`
+	Tooling    string      `json:"tooling,omitempty"`
+	Statements []Statement `json:"statements"`
+}
+
+// Statement represents a VEX statement about a vulnerability
+type Statement struct {
+	Vulnerability   Vulnerability `json:"vulnerability"`
+	Products        []Product     `json:"products,omitempty"`
+	Status          Status        `json:"status"`
+	Justification   Justification `json:"justification,omitempty"`
+	ImpactStatement string        `json:"impact_statement,omitempty"`
+	ActionStatement string        `json:"action_statement,omitempty"`
+	StatusNotes     string        `json:"status_notes,omitempty"`
+	Timestamp       *time.Time    `json:"timestamp,omitempty"`
+}
+
+// Vulnerability identifies the vulnerability a statement refers to
+type Vulnerability struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// Product identifies the software a statement applies to
+type Product struct {
+	ID            string      `json:"@id"`
+	Subcomponents []Component `json:"subcomponents,omitempty"`
+}
+
+// Component identifies a subcomponent of a product
+type Component struct {
+	ID string `json:"@id"`
+}
+
+// Names returns the vulnerability name followed by its aliases
+func (v Vulnerability) Names() []string {
+	return append([]string{v.Name}, v.Aliases...)
+}
+
+// PackageURL builds a package URL for a dependency.
+// Scoped npm packages start with "@" while Composer packages are "vendor/name",
+// which is enough to tell the two ecosystems the analyzers support apart.
+func PackageURL(name, version string) string {
+	purlType := "npm"
+	purlName := name
+	if strings.HasPrefix(name, "@") {
+		purlName = "%40" + name[1:]
+	} else if strings.Contains(name, "/") {
+		purlType = "composer"
+	}
+	if version == "" {
+		return fmt.Sprintf("pkg:%s/%s", purlType, purlName)
+	}
+	return fmt.Sprintf("pkg:%s/%s@%s", purlType, purlName, version)
+}
+
+// Triage represents manual triage decisions used when generating documents
+type Triage struct {
+	Entries []TriageEntry `yaml:"triage" json:"triage"`
+}
+
+// TriageEntry records a decision about a vulnerability, optionally scoped to a package
+type TriageEntry struct {
+	ID              string        `yaml:"id" json:"id"`
+	Package         string        `yaml:"package,omitempty" json:"package,omitempty"`
+	Status          Status        `yaml:"status" json:"status"`
+	Justification   Justification `yaml:"justification,omitempty" json:"justification,omitempty"`
+	ImpactStatement string        `yaml:"impact_statement,omitempty" json:"impact_statement,omitempty"`
+	ActionStatement string        `yaml:"action_statement,omitempty" json:"action_statement,omitempty"`
+}
+
+// LoadTriage reads triage decisions from a YAML or JSON file
+func LoadTriage(path string) (*Triage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var triage Triage
+	if err := yaml.Unmarshal(data, &triage); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for _, e := range triage.Entries {
+		if e.ID == "" {
+			return nil, fmt.Errorf("%s: triage entry has no id", path)
+		}
+		if !e.Status.Valid() {
+			return nil, fmt.Errorf("%s: triage entry %s has invalid status %q", path, e.ID, e.Status)
+		}
+		if !e.Justification.Valid() {
+			return nil, fmt.Errorf("%s: triage entry %s has invalid justification %q", path, e.ID, e.Justification)
+		}
+	}
+
+	return &triage, nil
+}
+
+// Lookup returns the triage entry for a vulnerability, known by any of its
+// names, and a package, or nil. An entry scoped to the package wins over an
+// unscoped one.
+func (t *Triage) Lookup(v Vulnerability, pkg string) *TriageEntry {
+	if t == nil {
+		return nil
+	}
+	var match *TriageEntry
+	for i := range t.Entries {
+		e := &t.Entries[i]
+		if !e.names(v) {
+			continue
+		}
+		if e.Package == pkg {
+			return e
+		}
+		if e.Package == "" {
+			match = e
+		}
+	}
+	return match
+}
+
+// names checks whether the entry ID is one of the vulnerability names
+func (e *TriageEntry) names(v Vulnerability) bool {
+	for _, name := range v.Names() {
+		if strings.EqualFold(e.ID, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDocument creates an empty document. Its ID is set by Finalize.
+func NewDocument(author string) *Document {
+	return &Document{
+		Context:   Context,
+		Author:    author,
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+		Version:   1,
+		Tooling:   Tooling,
+	}
+}
+
+// Finalize sorts statements and derives the document ID from their content
+func (d *Document) Finalize() {
+	sort.SliceStable(d.Statements, func(i, j int) bool {
+		return d.Statements[i].Vulnerability.Name < d.Statements[j].Vulnerability.Name
+	})
+
+	data, _ := json.Marshal(d.Statements)
+	sum := sha256.Sum256(data)
+	d.ID = "https://openvex.dev/docs/public/vex-" + hex.EncodeToString(sum[:])
+}
+
+// Load reads an OpenVEX document from disk
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if !strings.HasPrefix(doc.Context, "https://openvex.dev/ns") {
+		return nil, fmt.Errorf("%s is not an OpenVEX document (@context %q)", path, doc.Context)
+	}
+	for _, s := range doc.Statements {
+		if !s.Status.Valid() {
+			return nil, fmt.Errorf("%s: statement for %s has invalid status %q", path, s.Vulnerability.Name, s.Status)
+		}
+	}
+
+	return &doc, nil
+}
+
+// Index resolves the effective status of vulnerabilities in a product from one
+// or more documents
+type Index struct {
+	// identifiers the product is known by, as statement product @ids
+	products map[string]bool
+	// statements by upper-cased vulnerability name, in document order
+	byName map[string][]indexed
+}
+
+// indexed is a statement with its position across all indexed documents
+type indexed struct {
+	seq int
+	*Statement
+}
+
+// NewIndex builds an index over documents for a product known by any of the
+// given identifiers. Later statements take precedence.
+func NewIndex(products []string, docs ...*Document) *Index {
+	idx := &Index{products: make(map[string]bool), byName: make(map[string][]indexed)}
+	for _, p := range products {
+		idx.products[p] = true
+	}
+	seq := 0
+	for _, doc := range docs {
+		for i := range doc.Statements {
+			s := &doc.Statements[i]
+			for _, name := range s.Vulnerability.Names() {
+				key := strings.ToUpper(name)
+				idx.byName[key] = append(idx.byName[key], indexed{seq, s})
+			}
+			seq++
+		}
+	}
+	return idx
+}
+
+// appliesTo checks whether a statement covers a dependency of the indexed
+// product. A product entry without subcomponents covers all dependencies.
+func (idx *Index) appliesTo(s *Statement, pkg, version string) bool {
+	for _, p := range s.Products {
+		if !idx.products[p.ID] {
+			continue
+		}
+		if len(p.Subcomponents) == 0 {
+			return true
+		}
+		for _, c := range p.Subcomponents {
+			if c.ID == PackageURL(pkg, version) || c.ID == PackageURL(pkg, "") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// latest returns the status of the last statement about one of names that
+// applies, or "" if none does
+func (idx *Index) latest(names []string, applies func(s *Statement) bool) Status {
+	var result Status
+	last := -1
+	for _, name := range names {
+		for _, s := range idx.byName[strings.ToUpper(name)] {
+			if s.seq > last && applies(s.Statement) {
+				result, last = s.Status, s.seq
+			}
+		}
+	}
+	return result
+}
+
+// DependencyStatus returns the effective status of a vulnerability for one of
+// its affected dependencies, or "" if no statement applies
+func (idx *Index) DependencyStatus(v *codeclarity.Vulnerability, a *codeclarity.AffectedVuln) Status {
+	return idx.latest([]string{v.ID, a.VulnerabilityId}, func(s *Statement) bool {
+		return idx.appliesTo(s, a.AffectedDependency, a.AffectedVersion)
+	})
+}
+
+// Status returns the effective status of a vulnerability: the status shared
+// by all its affected dependencies, or "" if they differ or some have none
+func (idx *Index) Status(v *codeclarity.Vulnerability) Status {
+	if len(v.Affected) == 0 {
+		return idx.latest([]string{v.ID}, func(s *Statement) bool { return idx.appliesTo(s, "", "") })
+	}
+	var result Status
+	for i := range v.Affected {
+		status := idx.DependencyStatus(v, &v.Affected[i])
+		if status == "" || (i > 0 && status != result) {
+			return ""
+		}
+		result = status
+	}
+	return result
+}
+
+// resolved reports whether a status hides a finding
+func resolved(s Status) bool {
+	return s == StatusNotAffected || s == StatusFixed
+}
+
+// Filter splits vulnerabilities into visible ones and those resolved as
+// not_affected or fixed by a VEX statement. Dependencies resolved by a
+// statement are removed from visible vulnerabilities; a vulnerability is
+// only hidden when all of its dependencies are.
+func (idx *Index) Filter(vulns []codeclarity.Vulnerability) (kept, hidden []codeclarity.Vulnerability) {
+	for _, v := range vulns {
+		if len(v.Affected) == 0 {
+			if resolved(idx.Status(&v)) {
+				hidden = append(hidden, v)
+			} else {
+				kept = append(kept, v)
+			}
+			continue
+		}
+
+		var affected []codeclarity.AffectedVuln
+		for i := range v.Affected {
+			if !resolved(idx.DependencyStatus(&v, &v.Affected[i])) {
+				affected = append(affected, v.Affected[i])
+			}
+		}
+		switch len(affected) {
+		case 0:
+			hidden = append(hidden, v)
+		case len(v.Affected):
+			kept = append(kept, v)
+		default:
+			v.Affected = affected
+			kept = append(kept, v)
+		}
+	}
+	return kept, hidden
+}
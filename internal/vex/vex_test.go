@@ -0,0 +1,68 @@
+package vex
+
+import (
+	"testing"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+func TestIndexProducts(t *testing.T) {
+	const product = "https://github.com/example/web"
+	statement := func(productID string, status Status, subcomponents ...string) Statement {
+		p := Product{ID: productID}
+		for _, c := range subcomponents {
+			p.Subcomponents = append(p.Subcomponents, Component{ID: c})
+		}
+		return Statement{Vulnerability: Vulnerability{Name: "CVE-2022-24999"}, Products: []Product{p}, Status: status}
+	}
+	qs := codeclarity.Vulnerability{ID: "CVE-2022-24999", Affected: []codeclarity.AffectedVuln{{AffectedDependency: "qs", AffectedVersion: "6.5.2"}}}
+
+	tests := []struct {
+		name       string
+		statements []Statement
+		want       Status
+	}{
+		{"whole product", []Statement{statement(product, StatusNotAffected)}, StatusNotAffected},
+		{"subcomponent", []Statement{statement(product, StatusFixed, "pkg:npm/qs@6.5.2")}, StatusFixed},
+		{"unversioned subcomponent", []Statement{statement(product, StatusFixed, "pkg:npm/qs")}, StatusFixed},
+		{"other subcomponent", []Statement{statement(product, StatusFixed, "pkg:npm/qs@6.5.3")}, ""},
+		{"other product", []Statement{statement("pkg:generic/other", StatusNotAffected)}, ""},
+		{"subcomponent of other product", []Statement{statement("pkg:generic/other", StatusNotAffected, "pkg:npm/qs@6.5.2")}, ""},
+		{"later statement wins", []Statement{statement(product, StatusNotAffected), statement(product, StatusAffected)}, StatusAffected},
+		{"later statement for other product ignored", []Statement{statement(product, StatusNotAffected), statement("pkg:generic/other", StatusAffected)}, StatusNotAffected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := NewIndex([]string{"pkg:generic/web", product}, &Document{Statements: tt.statements})
+			if got := idx.Status(&qs); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriageLookup(t *testing.T) {
+	triage := &Triage{Entries: []TriageEntry{
+		{ID: "GHSA-hrpp-h998-j3pp", Status: StatusNotAffected},
+		{ID: "CVE-2022-24999", Package: "qs", Status: StatusFixed},
+	}}
+	vuln := Vulnerability{Name: "CVE-2022-24999", Aliases: []string{"GHSA-hrpp-h998-j3pp"}}
+
+	tests := []struct {
+		pkg  string
+		want Status
+	}{
+		{"qs", StatusFixed},
+		// The unscoped entry matches the vulnerability by its alias
+		{"express", StatusNotAffected},
+	}
+	for _, tt := range tests {
+		entry := triage.Lookup(vuln, tt.pkg)
+		if entry == nil || entry.Status != tt.want {
+			t.Errorf("Lookup(%s) = %+v, want status %s", tt.pkg, entry, tt.want)
+		}
+	}
+	if entry := triage.Lookup(Vulnerability{Name: "CVE-2021-44906"}, "minimist"); entry != nil {
+		t.Errorf("Lookup(unrelated) = %+v, want nil", entry)
+	}
+}
@@ -0,0 +1,56 @@
+package codeclarity
+
+import "context"
+
+// AnalysesService covers the /org/{org}/projects/{project}/analyses endpoints
+type AnalysesService struct {
+	client *Client
+}
+
+func analysesPath(orgID, projectID string) string {
+	return "/org/" + orgID + "/projects/" + projectID + "/analyses"
+}
+
+// List lists analyses for a project
+func (s *AnalysesService) List(ctx context.Context, orgID, projectID string, opts *ListOptions) (*PaginatedResponse[Analysis], error) {
+	var resp PaginatedResponse[Analysis]
+	if err := s.client.do(ctx, "GET", analysesPath(orgID, projectID), opts.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// All fetches every page of analyses for a project
+func (s *AnalysesService) All(ctx context.Context, orgID, projectID string) ([]Analysis, error) {
+	var all []Analysis
+	opts := &ListOptions{PerPage: 100}
+	for {
+		resp, err := s.List(ctx, orgID, projectID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Data...)
+		if len(resp.Data) == 0 || opts.Page+1 >= resp.TotalPages {
+			return all, nil
+		}
+		opts.Page++
+	}
+}
+
+// Get gets an analysis by ID
+func (s *AnalysesService) Get(ctx context.Context, orgID, projectID, analysisID string) (*Analysis, error) {
+	var resp SingleResponse[Analysis]
+	if err := s.client.do(ctx, "GET", analysesPath(orgID, projectID)+"/"+analysisID, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// Start starts an analysis and returns its ID
+func (s *AnalysesService) Start(ctx context.Context, orgID, projectID string, req AnalysisCreateRequest) (string, error) {
+	var resp CreatedResponse
+	if err := s.client.do(ctx, "POST", analysesPath(orgID, projectID), nil, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
@@ -0,0 +1,35 @@
+package codeclarity
+
+import "context"
+
+// AnalyzersService covers the /org/{org}/analyzers endpoints
+type AnalyzersService struct {
+	client *Client
+}
+
+// List lists analyzers for an organization
+func (s *AnalyzersService) List(ctx context.Context, orgID string, opts *ListOptions) (*PaginatedResponse[Analyzer], error) {
+	var resp PaginatedResponse[Analyzer]
+	if err := s.client.do(ctx, "GET", "/org/"+orgID+"/analyzers", opts.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Get gets an analyzer by ID
+func (s *AnalyzersService) Get(ctx context.Context, orgID, analyzerID string) (*Analyzer, error) {
+	var resp SingleResponse[Analyzer]
+	if err := s.client.do(ctx, "GET", "/org/"+orgID+"/analyzers/"+analyzerID, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// Create creates an analyzer and returns its ID
+func (s *AnalyzersService) Create(ctx context.Context, orgID string, req AnalyzerCreateRequest) (string, error) {
+	var resp CreatedResponse
+	if err := s.client.do(ctx, "POST", "/org/"+orgID+"/analyzers", nil, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
@@ -0,0 +1,326 @@
+package codeclarity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is used when no base URL option is given
+	DefaultBaseURL = "https://localhost/api"
+	// DefaultUserAgent identifies requests made through the SDK
+	DefaultUserAgent = "codeclarity-go"
+	// DefaultTimeout bounds requests made with the default HTTP client
+	DefaultTimeout = 30 * time.Second
+)
+
+// Client is a CodeClarity API client. Endpoints are grouped into services,
+// e.g. client.Projects.List(ctx, orgID, nil).
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	tokenSource TokenSource
+	userAgent   string
+	retry       RetryPolicy
+
+	Auth      *AuthService
+	Projects  *ProjectsService
+	Analyses  *AnalysesService
+	Analyzers *AnalyzersService
+	Results   *ResultsService
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the API base URL, e.g. https://codeclarity.example.com/api
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithToken authenticates every request with a fixed bearer token
+func WithToken(token string) Option {
+	return WithTokenSource(StaticToken(token))
+}
+
+// WithTokenSource authenticates requests with tokens from ts
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// RetryPolicy controls how requests rejected with 429 Too Many Requests or a
+// 5xx status are retried. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried after the first attempt
+	MaxRetries int
+	// Backoff is the wait before the first retry of a response without a
+	// Retry-After header; it doubles on every retry
+	Backoff time.Duration
+	// MaxWait caps the wait before a retry. A response asking to wait longer
+	// through Retry-After is returned as an error instead.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy retries a request twice within a few seconds
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	Backoff:    250 * time.Millisecond,
+	MaxWait:    5 * time.Second,
+}
+
+// WithRetries retries requests rejected with 429, and idempotent requests
+// failing with 500, 502, 503 or 504, following p. Retry-After is honored.
+func WithRetries(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// New creates a client configured by opts
+func New(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Auth = &AuthService{client: c}
+	c.Projects = &ProjectsService{client: c}
+	c.Analyses = &AnalysesService{client: c}
+	c.Analyzers = &AnalyzersService{client: c}
+	c.Results = &ResultsService{client: c}
+	return c
+}
+
+// BaseURL returns the API base URL the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// apiResponseWrapper is used to unwrap the standard API response format
+type apiResponseWrapper struct {
+	StatusCode int             `json:"status_code"`
+	Status     string          `json:"status"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// do performs an API request, encoding body as JSON and decoding the
+// response into result
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(jsonBody)
+	}
+	return c.send(ctx, method, path, query, bodyReader, "application/json", result)
+}
+
+// send performs an API request with a body of the given content type and
+// decodes the response into result, retrying as the client's RetryPolicy allows
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string, result interface{}) error {
+	reqURL, err := url.JoinPath(c.baseURL, path)
+	if err != nil {
+		return fmt.Errorf("failed to build URL: %w", err)
+	}
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	// The body is buffered so that it can be sent again on retries
+	var payload []byte
+	if body != nil {
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	for retries := 0; ; retries++ {
+		resp, respBody, err := c.attempt(ctx, method, reqURL, payload, body != nil, contentType)
+		if err != nil {
+			return err
+		}
+
+		// Redirects are followed by the HTTP client, so any other status,
+		// such as 304 without a cached copy to serve, is a failure
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			wait, ok := c.retry.wait(method, resp, retries)
+			if !ok {
+				return newError(resp.StatusCode, respBody)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return fmt.Errorf("request failed: %w", err)
+			}
+			continue
+		}
+
+		if result != nil && len(respBody) > 0 {
+			return decodeResponse(respBody, result)
+		}
+		return nil
+	}
+}
+
+// attempt sends a request once and reads the whole response
+func (c *Client) attempt(ctx context.Context, method, reqURL string, payload []byte, hasBody bool, contentType string) (*http.Response, []byte, error) {
+	var body io.Reader
+	if hasBody {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	if c.tokenSource != nil {
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, respBody, nil
+}
+
+// wait returns how long to wait before retrying a failed response, or false
+// when it must not be retried
+func (p RetryPolicy) wait(method string, resp *http.Response, retries int) (time.Duration, bool) {
+	if retries >= p.MaxRetries {
+		return 0, false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		// Retrying a request that may have been applied must not repeat it
+		if !idempotent(method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxWait > 0 && after > p.MaxWait {
+			return 0, false
+		}
+		return after, true
+	}
+	wait := p.Backoff << retries
+	if p.MaxWait > 0 && wait > p.MaxWait {
+		wait = p.MaxWait
+	}
+	return wait, true
+}
+
+// idempotent reports whether repeating a request has no further effect
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done. Tests replace it to check waits
+// without slowing down.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// decodeResponse parses a response body, unwrapping the standard
+// {"status_code":..., "data":...} envelope when present
+func decodeResponse(respBody []byte, result interface{}) error {
+	var wrapper apiResponseWrapper
+	if err := json.Unmarshal(respBody, &wrapper); err == nil && wrapper.Data != nil {
+		// For paginated responses the wrapper fields sit at the same level as
+		// the pagination fields; for single items data holds the actual item
+		trimmed := bytes.TrimSpace(wrapper.Data)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(respBody, result); err != nil {
+				return fmt.Errorf("failed to parse paginated response: %w", err)
+			}
+			return nil
+		}
+		if err := json.Unmarshal(wrapper.Data, result); err != nil {
+			return fmt.Errorf("failed to parse response data: %w", err)
+		}
+		return nil
+	}
+
+	// Responses without the wrapper are parsed directly
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package codeclarity_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+	"codeclarity.io/pkg/codeclarity/codeclaritytest"
+)
+
+// testRetryPolicy retries without slowing the tests down
+var testRetryPolicy = codeclarity.RetryPolicy{
+	MaxRetries: 2,
+	Backoff:    time.Millisecond,
+	MaxWait:    time.Second,
+}
+
+func TestRetries(t *testing.T) {
+	projectPath := "/org/" + codeclaritytest.OrgID + "/projects"
+
+	getProject := func(ctx context.Context, c *codeclarity.Client) error {
+		_, err := c.Projects.Get(ctx, codeclaritytest.OrgID, codeclaritytest.ProjectID)
+		return err
+	}
+	importProject := func(ctx context.Context, c *codeclarity.Client) error {
+		_, err := c.Projects.Import(ctx, codeclaritytest.OrgID, codeclarity.ProjectImportRequest{
+			IntegrationID: "integration-1",
+			URL:           "https://github.com/example/api",
+		})
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		policy   codeclarity.RetryPolicy
+		fault    codeclaritytest.Fault
+		call     func(context.Context, *codeclarity.Client) error
+		wantErr  error
+		requests int
+	}{
+		{
+			name:     "rate limited then served",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.RateLimited(projectPath, 2, 0),
+			call:     getProject,
+			requests: 3,
+		},
+		{
+			name:     "rate limited beyond max retries",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.RateLimited(projectPath, 3, 0),
+			call:     getProject,
+			wantErr:  codeclarity.ErrRateLimited,
+			requests: 3,
+		},
+		{
+			name:     "retry-after beyond max wait",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.RateLimited(projectPath, 1, 60),
+			call:     getProject,
+			wantErr:  codeclarity.ErrRateLimited,
+			requests: 1,
+		},
+		{
+			name:     "rate limited write retried",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.RateLimited(projectPath, 1, 0),
+			call:     importProject,
+			requests: 2,
+		},
+		{
+			name:     "server error on read retried",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.ServerError(projectPath, http.StatusServiceUnavailable, 1),
+			call:     getProject,
+			requests: 2,
+		},
+		{
+			name:     "server error on write not retried",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.ServerError(projectPath, http.StatusBadGateway, 1),
+			call:     importProject,
+			wantErr:  codeclarity.ErrServer,
+			requests: 1,
+		},
+		{
+			name:     "not implemented not retried",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.ServerError(projectPath, http.StatusNotImplemented, 1),
+			call:     getProject,
+			wantErr:  codeclarity.ErrServer,
+			requests: 1,
+		},
+		{
+			name:     "unauthorized not retried",
+			policy:   testRetryPolicy,
+			fault:    codeclaritytest.Unauthorized(projectPath),
+			call:     getProject,
+			wantErr:  codeclarity.ErrUnauthorized,
+			requests: 1,
+		},
+		{
+			name:     "retries disabled",
+			fault:    codeclaritytest.RateLimited(projectPath, 1, 0),
+			call:     getProject,
+			wantErr:  codeclarity.ErrRateLimited,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := codeclaritytest.NewServer(codeclaritytest.DefaultFixtures())
+			defer srv.Close()
+			srv.Inject(tt.fault)
+
+			err := tt.call(context.Background(), srv.Client(codeclarity.WithRetries(tt.policy)))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := len(srv.Requests()); got != tt.requests {
+				t.Errorf("server received %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	srv := codeclaritytest.NewServer(codeclaritytest.DefaultFixtures())
+	defer srv.Close()
+	srv.Inject(codeclaritytest.RateLimited("/org/"+codeclaritytest.OrgID+"/projects", 1, 0))
+
+	client := srv.Client(codeclarity.WithRetries(testRetryPolicy))
+	id, err := client.Projects.Import(context.Background(), codeclaritytest.OrgID, codeclarity.ProjectImportRequest{
+		IntegrationID: "integration-1",
+		URL:           "https://github.com/example/api",
+		Name:          "example/api",
+	})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	for _, p := range srv.Projects() {
+		if p.ID == id {
+			if p.Name != "example/api" {
+				t.Errorf("imported project name = %q, want example/api", p.Name)
+			}
+			return
+		}
+	}
+	t.Fatalf("project %s was not imported", id)
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv := codeclaritytest.NewServer(codeclaritytest.DefaultFixtures())
+	defer srv.Close()
+	srv.Inject(codeclaritytest.RateLimited("/org/"+codeclaritytest.OrgID+"/projects", 1, 1))
+
+	waits := codeclarity.RecordWaits(t)
+	client := srv.Client(codeclarity.WithRetries(codeclarity.RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond, MaxWait: 2 * time.Second}))
+	if _, err := client.Projects.Get(context.Background(), codeclaritytest.OrgID, codeclaritytest.ProjectID); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != time.Second {
+		t.Errorf("waited %v before retrying, want the 1s Retry-After", *waits)
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	srv := codeclaritytest.NewServer(codeclaritytest.DefaultFixtures())
+	defer srv.Close()
+	srv.Inject(codeclaritytest.RateLimited("/org/"+codeclaritytest.OrgID+"/projects", 0, 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := srv.Client(codeclarity.WithRetries(codeclarity.RetryPolicy{MaxRetries: 5, Backoff: time.Minute}))
+	_, err := client.Projects.Get(ctx, codeclaritytest.OrgID, codeclaritytest.ProjectID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestNotModifiedIsAnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	client := codeclarity.New(codeclarity.WithBaseURL(srv.URL))
+	project, err := client.Projects.Get(context.Background(), codeclaritytest.OrgID, codeclaritytest.ProjectID)
+	var apiErr *codeclarity.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotModified {
+		t.Fatalf("Get = %+v, %v; want a 304 *Error", project, err)
+	}
+}
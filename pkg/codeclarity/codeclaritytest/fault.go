@@ -0,0 +1,94 @@
+package codeclaritytest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault alters the response to requests it matches. A fault with only
+// Latency delays the request and then serves it normally; a fault with a
+// Status replaces the response.
+type Fault struct {
+	// Method matches the request method; empty matches any
+	Method string
+	// Path matches requests whose path, relative to BasePath, starts with
+	// it; empty matches any
+	Path string
+	// Times limits how many requests the fault applies to; 0 means always
+	Times int
+
+	Latency time.Duration
+	Status  int
+	// RetryAfter is sent as the Retry-After header, in seconds
+	RetryAfter int
+
+	hits int
+}
+
+// Latency delays every matching request by d
+func Latency(path string, d time.Duration) Fault {
+	return Fault{Path: path, Latency: d}
+}
+
+// Unauthorized makes matching requests fail with 401
+func Unauthorized(path string) Fault {
+	return Fault{Path: path, Status: http.StatusUnauthorized}
+}
+
+// RateLimited makes the next times matching requests fail with 429 and a
+// Retry-After of retryAfter seconds, or none when retryAfter is 0
+func RateLimited(path string, times, retryAfter int) Fault {
+	return Fault{Path: path, Times: times, Status: http.StatusTooManyRequests, RetryAfter: retryAfter}
+}
+
+// ServerError makes the next times matching requests fail with status,
+// which should be a 5xx code
+func ServerError(path string, status, times int) Fault {
+	return Fault{Path: path, Times: times, Status: status}
+}
+
+// Inject adds a fault. Faults are checked in the order they were added and
+// the first match applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the first fault applying to r and counts the hit.
+// Callers hold s.mu.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	path := strings.TrimPrefix(r.URL.Path, BasePath)
+	for _, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		f.hits++
+		copied := *f
+		return &copied
+	}
+	return nil
+}
+
+func (f *Fault) write(w http.ResponseWriter) {
+	if f.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+	}
+	code := strings.ReplaceAll(http.StatusText(f.Status), " ", "")
+	writeError(w, f.Status, code, "injected fault")
+}
@@ -0,0 +1,166 @@
+package codeclaritytest
+
+import (
+	"strings"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// Default identifiers used by DefaultFixtures
+const (
+	OrgID      = "org-1"
+	ProjectID  = "project-1"
+	AnalyzerID = "analyzer-1"
+	AnalysisID = "analysis-1"
+	Email      = "dev@example.com"
+	Password   = "password"
+	Token      = "test-token"
+)
+
+// Fixtures is the data a Server starts with. Everything belongs to OrgID.
+type Fixtures struct {
+	OrgID     string
+	User      codeclarity.User
+	Password  string
+	Projects  []codeclarity.Project
+	Analyzers []codeclarity.Analyzer
+	Analyses  []codeclarity.Analysis
+	// Results are keyed by analysis ID. Vulnerability stats are computed
+	// from Vulnerabilities.
+	Vulnerabilities map[string][]codeclarity.Vulnerability
+	SBOM            map[string][]codeclarity.Dependency
+	Licenses        map[string][]codeclarity.License
+	SBOMStats       map[string]codeclarity.SBOMStats
+	LicenseStats    map[string]codeclarity.LicenseStats
+}
+
+// DefaultFixtures returns one project with a finished analysis holding a
+// critical, a high and a low vulnerability
+func DefaultFixtures() Fixtures {
+	created := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ended := created.Add(5 * time.Minute)
+
+	return Fixtures{
+		OrgID: OrgID,
+		User: codeclarity.User{
+			ID:         "user-1",
+			Email:      Email,
+			Handle:     "dev",
+			FirstName:  "Dev",
+			LastName:   "Eloper",
+			DefaultOrg: &codeclarity.DefaultOrg{ID: OrgID, Name: "Example"},
+			Activated:  true,
+		},
+		Password: Password,
+		Projects: []codeclarity.Project{{
+			ID:                  ProjectID,
+			Name:                "example/web",
+			Description:         "Example web application",
+			URL:                 "https://github.com/example/web",
+			Type:                "VCS",
+			IntegrationProvider: "GITHUB",
+			IntegrationType:     "VCS",
+			DefaultBranch:       "main",
+			Downloaded:          true,
+			AddedOn:             created,
+		}},
+		Analyzers: []codeclarity.Analyzer{{
+			ID:          AnalyzerID,
+			Name:        "JS analyzer",
+			Description: "SBOM, vulnerabilities and licenses for npm projects",
+			CreatedOn:   created,
+			Steps: [][]codeclarity.Stage{
+				{{Name: "js-sbom", Version: "1.0.0"}},
+				{{Name: "vuln-finder", Version: "1.0.0"}, {Name: "license-finder", Version: "1.0.0"}},
+			},
+			SupportedLanguages: []string{"javascript"},
+			Global:             true,
+		}},
+		Analyses: []codeclarity.Analysis{{
+			ID:             AnalysisID,
+			AnalyzerID:     AnalyzerID,
+			ProjectID:      ProjectID,
+			OrganizationID: OrgID,
+			Status:         codeclarity.StatusSuccess,
+			Stage:          2,
+			Branch:         "main",
+			CommitHash:     "0123456789abcdef",
+			CreatedOn:      created,
+			StartedOn:      &created,
+			EndedOn:        &ended,
+			IsActive:       true,
+		}},
+		Vulnerabilities: map[string][]codeclarity.Vulnerability{
+			AnalysisID: {
+				vulnerability("CVE-2021-44906", "minimist", "1.2.5", 9.8, "CRITICAL", "Prototype pollution in minimist"),
+				vulnerability("CVE-2022-24999", "qs", "6.5.2", 7.5, "HIGH", "qs vulnerable to prototype pollution"),
+				vulnerability("CVE-2017-16137", "debug", "2.6.8", 3.7, "LOW", "Regular expression denial of service in debug"),
+			},
+		},
+		SBOM: map[string][]codeclarity.Dependency{
+			AnalysisID: {
+				{Name: "debug", Version: "2.6.8", Direct: true, Dev: true, Licenses: []string{"MIT"}},
+				{Name: "minimist", Version: "1.2.5", Transitive: true, Licenses: []string{"MIT"}},
+				{Name: "ms", Version: "2.0.0", Transitive: true, Dev: true, Licenses: []string{"MIT"}},
+				{Name: "qs", Version: "6.5.2", Direct: true, Licenses: []string{"BSD-3-Clause"}},
+			},
+		},
+		Licenses: map[string][]codeclarity.License{
+			AnalysisID: {
+				{ID: "BSD-3-Clause", Name: "BSD 3-Clause \"New\" or \"Revised\" License", Dependencies: []string{"qs@6.5.2"}},
+				{ID: "MIT", Name: "MIT License", Dependencies: []string{"debug@2.6.8", "minimist@1.2.5", "ms@2.0.0"}},
+			},
+		},
+		SBOMStats: map[string]codeclarity.SBOMStats{
+			AnalysisID: {TotalDependencies: 120, DirectDependencies: 12, TransitiveDependencies: 108},
+		},
+		LicenseStats: map[string]codeclarity.LicenseStats{
+			AnalysisID: {
+				Total:        120,
+				ByLicense:    map[string]int{"MIT": 100, "ISC": 15, "Apache-2.0": 5},
+				ByCompliance: map[string]int{"compliant": 120},
+			},
+		},
+	}
+}
+
+func vulnerability(id, pkg, version string, score float64, class, description string) codeclarity.Vulnerability {
+	severity := codeclarity.Severity{
+		Severity:      score,
+		SeverityClass: class,
+		SeverityType:  "CVSS31",
+		Vector:        "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+	}
+	return codeclarity.Vulnerability{
+		ID: id,
+		Affected: []codeclarity.AffectedVuln{{
+			AffectedDependency: pkg,
+			AffectedVersion:    version,
+			VulnerabilityId:    id,
+			Severity:           severity,
+		}},
+		Severity:    severity,
+		Description: description,
+	}
+}
+
+// vulnerabilityStats counts vulnerabilities by severity class
+func vulnerabilityStats(vulns []codeclarity.Vulnerability) codeclarity.VulnerabilityStats {
+	stats := codeclarity.VulnerabilityStats{Total: len(vulns)}
+	for _, v := range vulns {
+		switch strings.ToLower(v.Severity.SeverityClass) {
+		case "critical":
+			stats.Critical++
+		case "high":
+			stats.High++
+		case "medium":
+			stats.Medium++
+		case "low":
+			stats.Low++
+		default:
+			stats.None++
+		}
+	}
+	return stats
+}
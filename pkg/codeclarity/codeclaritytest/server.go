@@ -0,0 +1,878 @@
+// Package codeclaritytest provides an in-process fake CodeClarity API for
+// testing code built on the codeclarity SDK or the CLI.
+//
+//	srv := codeclaritytest.NewServer(codeclaritytest.DefaultFixtures())
+//	defer srv.Close()
+//	client := srv.Client()
+//	projects, err := client.Projects.List(ctx, codeclaritytest.OrgID, nil)
+//
+// GET responses carry an ETag and honor If-None-Match. Started analyses
+// move through Server.Transitions one step each time they are fetched,
+// refreshed projects report downloaded after Server.DownloadPolls fetches, and
+// faults such as latency, 401, 429 and 5xx responses can be injected per
+// endpoint with Server.Inject. Uploaded snapshots can be inspected with
+// Server.Snapshot.
+package codeclaritytest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"codeclarity.io/pkg/codeclarity"
+)
+
+// BasePath is the path the API is served under, matching the CLI's default
+// https://<host>/api base URL
+const BasePath = "/api"
+
+// DefaultPerPage is the page size used when a request does not give one
+const DefaultPerPage = 10
+
+// Server is a fake CodeClarity API backed by in-memory fixtures.
+// It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the bearer token accepted on authenticated endpoints and
+	// returned by /auth/authenticate. An empty Token disables auth checks.
+	Token string
+	// Transitions lists the statuses a started analysis reports on
+	// successive fetches; the last one sticks
+	Transitions []codeclarity.AnalysisStatus
+	// DownloadPolls is how many fetches a refreshed project reports as
+	// downloading before it is downloaded again
+	DownloadPolls int
+	// StalePolls is how many fetches after a refresh still report the
+	// download state from before it, like a server that has not picked up
+	// the request yet
+	StalePolls int
+
+	mu        sync.Mutex
+	fixtures  Fixtures
+	progress  map[string][]codeclarity.AnalysisStatus
+	downloads map[string]download
+	snapshots map[string]Snapshot
+	faults    []*Fault
+	requests  []Request
+	nextID    int
+}
+
+// Snapshot is an uploaded archive of project files
+type Snapshot struct {
+	ID        string
+	ProjectID string
+	// Files maps slash-separated paths to their content
+	Files map[string][]byte
+}
+
+// Request records a call received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// NewServer starts a fake server seeded with fixtures. Call Close when done.
+func NewServer(fixtures Fixtures) *Server {
+	s := &Server{
+		Token: Token,
+		Transitions: []codeclarity.AnalysisStatus{
+			codeclarity.StatusRequested,
+			codeclarity.StatusStarted,
+			codeclarity.StatusSuccess,
+		},
+		DownloadPolls: 2,
+		fixtures:      fixtures,
+		progress:      map[string][]codeclarity.AnalysisStatus{},
+		downloads:     map[string]download{},
+		snapshots:     map[string]Snapshot{},
+	}
+	if s.fixtures.Vulnerabilities == nil {
+		s.fixtures.Vulnerabilities = map[string][]codeclarity.Vulnerability{}
+	}
+	if s.fixtures.SBOM == nil {
+		s.fixtures.SBOM = map[string][]codeclarity.Dependency{}
+	}
+	if s.fixtures.Licenses == nil {
+		s.fixtures.Licenses = map[string][]codeclarity.License{}
+	}
+	if s.fixtures.SBOMStats == nil {
+		s.fixtures.SBOMStats = map[string]codeclarity.SBOMStats{}
+	}
+	if s.fixtures.LicenseStats == nil {
+		s.fixtures.LicenseStats = map[string]codeclarity.LicenseStats{}
+	}
+
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// BaseURL returns the API base URL to configure clients with
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
+
+// Client returns an SDK client authenticated against the server. Extra
+// options are applied last.
+func (s *Server) Client(opts ...codeclarity.Option) *codeclarity.Client {
+	base := []codeclarity.Option{
+		codeclarity.WithBaseURL(s.BaseURL()),
+		codeclarity.WithToken(s.Token),
+	}
+	return codeclarity.New(append(base, opts...)...)
+}
+
+// Requests returns the calls received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Snapshot returns an uploaded snapshot by ID
+func (s *Server) Snapshot(id string) (Snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[id]
+	return snap, ok
+}
+
+// SetAnalysisStatus forces an analysis into status, cancelling any pending
+// transitions
+func (s *Server) SetAnalysisStatus(analysisID string, status codeclarity.AnalysisStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.progress, analysisID)
+	if a := s.analysis(analysisID); a != nil {
+		a.Status = status
+	}
+}
+
+// SetVulnerabilities replaces the vulnerabilities reported for an analysis
+func (s *Server) SetVulnerabilities(analysisID string, vulns []codeclarity.Vulnerability) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures.Vulnerabilities[analysisID] = vulns
+}
+
+// SetSBOM replaces the dependencies reported for an analysis
+func (s *Server) SetSBOM(analysisID string, deps []codeclarity.Dependency) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures.SBOM[analysisID] = deps
+}
+
+// Projects returns a copy of the current projects, including imported ones
+func (s *Server) Projects() []codeclarity.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]codeclarity.Project(nil), s.fixtures.Projects...)
+}
+
+// Analyses returns a copy of the current analyses, including started ones
+func (s *Server) Analyses() []codeclarity.Analysis {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]codeclarity.Analysis(nil), s.fixtures.Analyses...)
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, authenticated bool, h http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		mux.Handle(method+" "+BasePath+path, s.middleware(authenticated, h))
+	}
+
+	handle("POST /auth/authenticate", false, s.authenticate)
+	handle("POST /auth/refresh", false, s.refresh)
+	handle("GET /auth/user", true, s.currentUser)
+
+	handle("GET /org/{org}/projects", true, s.listProjects)
+	handle("POST /org/{org}/projects", true, s.importProject)
+	handle("GET /org/{org}/projects/{project}", true, s.getProject)
+	handle("PATCH /org/{org}/projects/{project}", true, s.updateProject)
+	handle("DELETE /org/{org}/projects/{project}", true, s.deleteProject)
+	handle("POST /org/{org}/projects/{project}/refresh", true, s.refreshProject)
+	handle("POST /org/{org}/projects/{project}/snapshots", true, s.uploadSnapshot)
+
+	handle("GET /org/{org}/analyzers", true, s.listAnalyzers)
+	handle("POST /org/{org}/analyzers", true, s.createAnalyzer)
+	handle("GET /org/{org}/analyzers/{analyzer}", true, s.getAnalyzer)
+
+	handle("GET /org/{org}/projects/{project}/analyses", true, s.listAnalyses)
+	handle("POST /org/{org}/projects/{project}/analyses", true, s.startAnalysis)
+	handle("GET /org/{org}/projects/{project}/analyses/{analysis}", true, s.getAnalysis)
+
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/vulnerabilities", true, s.listVulnerabilities)
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/vulnerabilities/stats", true, s.vulnerabilityStats)
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/sbom", true, s.listDependencies)
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/sbom/stats", true, s.sbomStats)
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/licenses", true, s.listLicenses)
+	handle("GET /org/{org}/projects/{project}/analysis/{analysis}/licenses/stats", true, s.licenseStats)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NotFound", "no route for "+r.Method+" "+r.URL.Path)
+	})
+	return etagHandler(mux)
+}
+
+// middleware records the request, applies injected faults, then checks the
+// bearer token and organization before calling h
+func (s *Server) middleware(authenticated bool, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery})
+		fault := s.matchFault(r)
+		token := s.Token
+		orgID := s.fixtures.OrgID
+		s.mu.Unlock()
+
+		if fault != nil {
+			if fault.Latency > 0 {
+				select {
+				case <-time.After(fault.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.Status != 0 {
+				fault.write(w)
+				return
+			}
+		}
+
+		if authenticated && token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			writeError(w, http.StatusUnauthorized, "NotAuthenticated", "missing or invalid bearer token")
+			return
+		}
+		if org := r.PathValue("org"); org != "" && org != orgID {
+			writeError(w, http.StatusForbidden, "NotAMemberOfOrg", "not a member of organization "+org)
+			return
+		}
+		h(w, r)
+	})
+}
+
+// newID returns a unique identifier with the given prefix. Callers hold s.mu.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-new-%d", prefix, s.nextID)
+}
+
+// Auth
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.AuthRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	ok := req.Email == s.fixtures.User.Email && req.Password == s.fixtures.Password
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "WrongCredentials", "wrong email or password")
+		return
+	}
+	writeData(w, http.StatusOK, s.tokens())
+}
+
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.RefreshRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.RefreshToken != "refresh-"+s.Token {
+		writeError(w, http.StatusUnauthorized, "InvalidRefreshToken", "invalid refresh token")
+		return
+	}
+	writeData(w, http.StatusOK, s.tokens())
+}
+
+func (s *Server) tokens() codeclarity.AuthResponse {
+	now := time.Now().UTC()
+	return codeclarity.AuthResponse{
+		Token:              s.Token,
+		RefreshToken:       "refresh-" + s.Token,
+		TokenExpiry:        now.Add(time.Hour),
+		RefreshTokenExpiry: now.Add(24 * time.Hour),
+	}
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeData(w, http.StatusOK, s.fixtures.User)
+}
+
+// Projects
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search := strings.ToLower(r.URL.Query().Get("search_key"))
+	var projects []codeclarity.Project
+	for _, p := range s.fixtures.Projects {
+		if search == "" || strings.Contains(strings.ToLower(p.Name), search) {
+			projects = append(projects, p)
+		}
+	}
+	writePage(w, r, projects)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(r.PathValue("project"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	if d, ok := s.downloads[p.ID]; ok {
+		switch {
+		case d.stale > 0:
+			d.stale--
+			s.downloads[p.ID] = d
+		case d.polls > 0:
+			p.Downloaded, p.Invalid = false, false
+			d.polls--
+			s.downloads[p.ID] = d
+		default:
+			p.Downloaded, p.Invalid = true, false
+			delete(s.downloads, p.ID)
+		}
+	}
+	writeSingle(w, *p)
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.ProjectUpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(r.PathValue("project"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	if req.Name != nil {
+		p.Name = *req.Name
+	}
+	if req.Description != nil {
+		p.Description = *req.Description
+	}
+	if req.DefaultBranch != nil {
+		p.DefaultBranch = *req.DefaultBranch
+	}
+	writeSingle(w, *p)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("project")
+	if s.project(id) == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	s.fixtures.Projects = slices.DeleteFunc(s.fixtures.Projects, func(p codeclarity.Project) bool {
+		return p.ID == id
+	})
+	s.fixtures.Analyses = slices.DeleteFunc(s.fixtures.Analyses, func(a codeclarity.Analysis) bool {
+		return a.ProjectID == id
+	})
+	delete(s.downloads, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// download counts the fetches a refreshed project has left in each phase
+type download struct {
+	stale int
+	polls int
+}
+
+// refreshProject marks the project as downloading, after StalePolls fetches
+// when set; it reports downloaded again after DownloadPolls more fetches
+func (s *Server) refreshProject(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.project(r.PathValue("project"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	if s.StalePolls == 0 {
+		p.Downloaded = false
+		p.Invalid = false
+	}
+	s.downloads[p.ID] = download{stale: s.StalePolls, polls: s.DownloadPolls}
+	writeData(w, http.StatusAccepted, map[string]string{"id": p.ID})
+}
+
+// uploadSnapshot stores a gzipped tar of project files
+func (s *Server) uploadSnapshot(w http.ResponseWriter, r *http.Request) {
+	files, err := readSnapshot(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidSnapshot", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projectID := r.PathValue("project")
+	if s.project(projectID) == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	snap := Snapshot{ID: s.newID("snapshot"), ProjectID: projectID, Files: files}
+	s.snapshots[snap.ID] = snap
+	writeData(w, http.StatusCreated, codeclarity.CreatedResponse{ID: snap.ID})
+}
+
+func readSnapshot(body io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%s is not a regular file", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[hdr.Name] = data
+	}
+	if len(files) == 0 {
+		return nil, errors.New("snapshot is empty")
+	}
+	return files, nil
+}
+
+func (s *Server) importProject(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.ProjectImportRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "ValidationFailed", "url is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.fixtures.Projects {
+		if p.URL == req.URL {
+			writeError(w, http.StatusConflict, "ProjectAlreadyExists", "project already imported")
+			return
+		}
+	}
+
+	name := req.Name
+	if name == "" {
+		name = strings.TrimSuffix(strings.TrimPrefix(req.URL, "https://github.com/"), ".git")
+	}
+	project := codeclarity.Project{
+		ID:                  s.newID("project"),
+		Name:                name,
+		Description:         req.Description,
+		URL:                 req.URL,
+		Type:                "VCS",
+		IntegrationProvider: "GITHUB",
+		IntegrationType:     "VCS",
+		DefaultBranch:       "main",
+		AddedOn:             time.Now().UTC(),
+	}
+	s.fixtures.Projects = append(s.fixtures.Projects, project)
+	writeData(w, http.StatusCreated, codeclarity.CreatedResponse{ID: project.ID})
+}
+
+func (s *Server) project(id string) *codeclarity.Project {
+	for i := range s.fixtures.Projects {
+		if s.fixtures.Projects[i].ID == id {
+			return &s.fixtures.Projects[i]
+		}
+	}
+	return nil
+}
+
+// Analyzers
+
+func (s *Server) listAnalyzers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writePage(w, r, s.fixtures.Analyzers)
+}
+
+func (s *Server) getAnalyzer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.analyzer(r.PathValue("analyzer"))
+	if a == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "analyzer not found")
+		return
+	}
+	writeSingle(w, *a)
+}
+
+func (s *Server) createAnalyzer(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.AnalyzerCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "ValidationFailed", "name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	analyzer := codeclarity.Analyzer{
+		ID:                 s.newID("analyzer"),
+		Name:               req.Name,
+		Description:        req.Description,
+		CreatedOn:          time.Now().UTC(),
+		Steps:              req.Steps,
+		SupportedLanguages: req.SupportedLanguages,
+		LanguageConfig:     req.LanguageConfig,
+		Logo:               req.Logo,
+	}
+	s.fixtures.Analyzers = append(s.fixtures.Analyzers, analyzer)
+	writeData(w, http.StatusCreated, codeclarity.CreatedResponse{ID: analyzer.ID})
+}
+
+func (s *Server) analyzer(id string) *codeclarity.Analyzer {
+	for i := range s.fixtures.Analyzers {
+		if s.fixtures.Analyzers[i].ID == id {
+			return &s.fixtures.Analyzers[i]
+		}
+	}
+	return nil
+}
+
+// Analyses
+
+func (s *Server) listAnalyses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projectID := r.PathValue("project")
+	if s.project(projectID) == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	var analyses []codeclarity.Analysis
+	for _, a := range s.fixtures.Analyses {
+		if a.ProjectID == projectID {
+			analyses = append(analyses, a)
+		}
+	}
+	writePage(w, r, analyses)
+}
+
+func (s *Server) getAnalysis(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.analysis(r.PathValue("analysis"))
+	if a == nil || a.ProjectID != r.PathValue("project") {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "analysis not found")
+		return
+	}
+	current := *a
+	s.advance(a)
+	writeSingle(w, current)
+}
+
+func (s *Server) startAnalysis(w http.ResponseWriter, r *http.Request) {
+	var req codeclarity.AnalysisCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projectID := r.PathValue("project")
+	if s.project(projectID) == nil {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "project not found")
+		return
+	}
+	analyzer := s.analyzer(req.AnalyzerID)
+	if analyzer == nil {
+		writeError(w, http.StatusBadRequest, "AnalyzerNotFound", "analyzer not found")
+		return
+	}
+	if req.SnapshotID != "" {
+		if snap, ok := s.snapshots[req.SnapshotID]; !ok || snap.ProjectID != projectID {
+			writeError(w, http.StatusBadRequest, "SnapshotNotFound", "snapshot not found")
+			return
+		}
+	}
+
+	var steps [][]codeclarity.AnalysisStep
+	for _, stage := range analyzer.Steps {
+		var group []codeclarity.AnalysisStep
+		for _, step := range stage {
+			group = append(group, codeclarity.AnalysisStep{Name: step.Name, Version: step.Version, Status: "pending"})
+		}
+		steps = append(steps, group)
+	}
+
+	config := map[string]any{}
+	for k, v := range req.Config {
+		config[k] = v
+	}
+	analysis := codeclarity.Analysis{
+		ID:             s.newID("analysis"),
+		AnalyzerID:     req.AnalyzerID,
+		ProjectID:      projectID,
+		OrganizationID: s.fixtures.OrgID,
+		Steps:          steps,
+		Branch:         req.Branch,
+		Tag:            req.Tag,
+		CommitHash:     req.CommitHash,
+		Config:         config,
+		CreatedOn:      time.Now().UTC(),
+		ScheduleType:   req.ScheduleType,
+		IsActive:       req.IsActive,
+	}
+	if len(s.Transitions) > 0 {
+		analysis.Status = s.Transitions[0]
+		s.progress[analysis.ID] = append([]codeclarity.AnalysisStatus(nil), s.Transitions[1:]...)
+	}
+	s.fixtures.Analyses = append(s.fixtures.Analyses, analysis)
+	writeData(w, http.StatusCreated, codeclarity.CreatedResponse{ID: analysis.ID})
+}
+
+// advance moves a started analysis to the status its next fetch reports
+func (s *Server) advance(a *codeclarity.Analysis) {
+	pending := s.progress[a.ID]
+	if len(pending) == 0 {
+		return
+	}
+	a.Status = pending[0]
+	s.progress[a.ID] = pending[1:]
+
+	now := time.Now().UTC()
+	switch a.Status {
+	case codeclarity.StatusStarted:
+		a.StartedOn = &now
+	case codeclarity.StatusSuccess, codeclarity.StatusCompleted, codeclarity.StatusFinished, codeclarity.StatusFailed:
+		if a.StartedOn == nil {
+			a.StartedOn = &now
+		}
+		a.EndedOn = &now
+		a.Stage = len(a.Steps)
+	}
+}
+
+func (s *Server) analysis(id string) *codeclarity.Analysis {
+	for i := range s.fixtures.Analyses {
+		if s.fixtures.Analyses[i].ID == id {
+			return &s.fixtures.Analyses[i]
+		}
+	}
+	return nil
+}
+
+// Results
+
+// resultAnalysis looks up the analysis a results request refers to,
+// writing a 404 when it does not exist. Callers hold s.mu.
+func (s *Server) resultAnalysis(w http.ResponseWriter, r *http.Request) (string, bool) {
+	a := s.analysis(r.PathValue("analysis"))
+	if a == nil || a.ProjectID != r.PathValue("project") {
+		writeError(w, http.StatusNotFound, "EntityNotFound", "analysis not found")
+		return "", false
+	}
+	return a.ID, true
+}
+
+func (s *Server) listVulnerabilities(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writePage(w, r, s.fixtures.Vulnerabilities[id])
+	}
+}
+
+func (s *Server) vulnerabilityStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writeData(w, http.StatusOK, vulnerabilityStats(s.fixtures.Vulnerabilities[id]))
+	}
+}
+
+func (s *Server) listDependencies(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writePage(w, r, s.fixtures.SBOM[id])
+	}
+}
+
+func (s *Server) listLicenses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writePage(w, r, s.fixtures.Licenses[id])
+	}
+}
+
+func (s *Server) sbomStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writeData(w, http.StatusOK, s.fixtures.SBOMStats[id])
+	}
+}
+
+func (s *Server) licenseStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.resultAnalysis(w, r); ok {
+		writeSingle(w, s.fixtures.LicenseStats[id])
+	}
+}
+
+// Responses
+
+// decode parses a JSON request body, writing a 400 on failure
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidBody", err.Error())
+		return false
+	}
+	return true
+}
+
+// writeData writes v in the standard {"status_code":..., "data":...} envelope
+func writeData(w http.ResponseWriter, status int, v interface{}) {
+	writeJSON(w, status, map[string]interface{}{
+		"status_code": status,
+		"status":      "ok",
+		"data":        v,
+	})
+}
+
+// writeSingle writes a single entity the way the API does for get
+// endpoints: nested in a second "data" object inside the envelope
+func writeSingle(w http.ResponseWriter, v interface{}) {
+	writeData(w, http.StatusOK, map[string]interface{}{"data": v})
+}
+
+// writePage writes the requested page of items with pagination fields
+// alongside the envelope
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("entries_per_page"))
+	if page < 0 {
+		page = 0
+	}
+	if perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+
+	start := min(page*perPage, len(items))
+	end := min(start+perPage, len(items))
+	data := items[start:end]
+	if data == nil {
+		data = []T{}
+	}
+
+	writeJSON(w, http.StatusOK, codeclarity.PaginatedResponse[T]{
+		StatusCode:     http.StatusOK,
+		Status:         "ok",
+		Data:           data,
+		Page:           page,
+		EntryCount:     len(data),
+		EntriesPerPage: perPage,
+		TotalEntries:   len(items),
+		TotalPages:     (len(items) + perPage - 1) / perPage,
+		MatchingCount:  len(items),
+	})
+}
+
+// writeError writes an API error document
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, codeclarity.Error{
+		StatusCode: status,
+		Status:     "error",
+		ErrorCode:  code,
+		Message:    message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// etagHandler adds an ETag to successful GET responses and answers
+// matching If-None-Match requests with 304 Not Modified
+func etagHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		if rec.Code != http.StatusOK {
+			w.WriteHeader(rec.Code)
+			w.Write(rec.Body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(rec.Body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(rec.Body.Bytes())
+	})
+}
@@ -0,0 +1,21 @@
+// Package codeclarity is a Go client for the CodeClarity API.
+//
+// Create a client with functional options and call endpoints through its
+// service sub-clients:
+//
+//	client := codeclarity.New(
+//		codeclarity.WithBaseURL("https://codeclarity.example.com/api"),
+//		codeclarity.WithToken(os.Getenv("CODECLARITY_API_KEY")),
+//	)
+//	projects, err := client.Projects.List(ctx, orgID, &codeclarity.ProjectListOptions{
+//		ListOptions: codeclarity.ListOptions{PerPage: 50},
+//	})
+//	if errors.Is(err, codeclarity.ErrUnauthorized) {
+//		// token expired or revoked
+//	}
+//
+// Every method takes a context.Context that bounds the underlying request.
+// Failed responses are returned as *Error, which matches the Err* sentinels
+// through errors.Is. WithRetries makes the client retry rate-limited requests,
+// and idempotent requests failing with a server error, honoring Retry-After.
+package codeclarity
@@ -0,0 +1,81 @@
+package codeclarity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by *Error through errors.Is
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// Error is returned for API responses without a 2xx status, usually 4xx or 5xx.
+// Use errors.As to inspect it, or errors.Is with the sentinel errors above:
+//
+//	if errors.Is(err, codeclarity.ErrNotFound) { ... }
+type Error struct {
+	StatusCode int    `json:"status_code"`
+	Status     string `json:"status"`
+	ErrorCode  string `json:"error_code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	// Body holds the raw response when it was not a JSON error document
+	Body string `json:"-"`
+}
+
+// newError builds an *Error from a failed response
+func newError(statusCode int, body []byte) *Error {
+	apiErr := &Error{}
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		apiErr.Body = string(body)
+	}
+	apiErr.StatusCode = statusCode
+	return apiErr
+}
+
+func (e *Error) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Body)
+	}
+	return "API error: " + e.String()
+}
+
+// String describes the error without the "API error" prefix
+func (e *Error) String() string {
+	if e.ErrorCode != "" {
+		if e.Message != "" {
+			return fmt.Sprintf("%s: %s", e.ErrorCode, e.Message)
+		}
+		return e.ErrorCode
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("HTTP %d", e.StatusCode)
+}
+
+// Is reports whether the error's status code belongs to target's class
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package codeclarity
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// RecordWaits replaces the wait between retries for the rest of the test,
+// returning the durations requested instead of sleeping
+func RecordWaits(t *testing.T) *[]time.Duration {
+	waits := &[]time.Duration{}
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = orig })
+	return waits
+}
@@ -0,0 +1,101 @@
+package codeclarity
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// ProjectsService covers the /org/{org}/projects endpoints
+type ProjectsService struct {
+	client *Client
+}
+
+// ProjectListOptions filters a project listing
+type ProjectListOptions struct {
+	ListOptions
+	Search string
+}
+
+// List lists projects for an organization
+func (s *ProjectsService) List(ctx context.Context, orgID string, opts *ProjectListOptions) (*PaginatedResponse[Project], error) {
+	q := url.Values{}
+	if opts != nil {
+		q = opts.values()
+		if opts.Search != "" {
+			q.Set("search_key", opts.Search)
+		}
+	}
+
+	var resp PaginatedResponse[Project]
+	if err := s.client.do(ctx, "GET", "/org/"+orgID+"/projects", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// All fetches every page of projects for an organization
+func (s *ProjectsService) All(ctx context.Context, orgID string) ([]Project, error) {
+	var all []Project
+	opts := &ProjectListOptions{ListOptions: ListOptions{PerPage: 100}}
+	for {
+		resp, err := s.List(ctx, orgID, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Data...)
+		if len(resp.Data) == 0 || opts.Page+1 >= resp.TotalPages {
+			return all, nil
+		}
+		opts.Page++
+	}
+}
+
+// Get gets a project by ID
+func (s *ProjectsService) Get(ctx context.Context, orgID, projectID string) (*Project, error) {
+	var resp SingleResponse[Project]
+	if err := s.client.do(ctx, "GET", "/org/"+orgID+"/projects/"+projectID, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// Import imports a project from an integration and returns its ID
+func (s *ProjectsService) Import(ctx context.Context, orgID string, req ProjectImportRequest) (string, error) {
+	var resp CreatedResponse
+	if err := s.client.do(ctx, "POST", "/org/"+orgID+"/projects", nil, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// Update changes the given fields of a project and returns the result
+func (s *ProjectsService) Update(ctx context.Context, orgID, projectID string, req ProjectUpdateRequest) (*Project, error) {
+	var resp SingleResponse[Project]
+	if err := s.client.do(ctx, "PATCH", "/org/"+orgID+"/projects/"+projectID, nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// Delete deletes a project together with its analyses
+func (s *ProjectsService) Delete(ctx context.Context, orgID, projectID string) error {
+	return s.client.do(ctx, "DELETE", "/org/"+orgID+"/projects/"+projectID, nil, nil, nil)
+}
+
+// Refresh asks the server to download the project's repository again.
+// Progress is reported by the Downloaded and Invalid fields of the project.
+func (s *ProjectsService) Refresh(ctx context.Context, orgID, projectID string) error {
+	return s.client.do(ctx, "POST", "/org/"+orgID+"/projects/"+projectID+"/refresh", nil, nil, nil)
+}
+
+// UploadSnapshot uploads a gzipped tar archive of project files and returns
+// the snapshot ID, which can be analyzed instead of the repository by
+// setting AnalysisCreateRequest.SnapshotID
+func (s *ProjectsService) UploadSnapshot(ctx context.Context, orgID, projectID string, archive io.Reader) (string, error) {
+	var resp CreatedResponse
+	if err := s.client.send(ctx, "POST", "/org/"+orgID+"/projects/"+projectID+"/snapshots", nil, archive, "application/gzip", &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
@@ -0,0 +1,158 @@
+package codeclarity
+
+import (
+	"context"
+	"net/url"
+)
+
+// ResultsService covers the analysis result endpoints
+// (/org/{org}/projects/{project}/analysis/{analysis}/...)
+type ResultsService struct {
+	client *Client
+}
+
+// ResultListOptions selects a page of analysis results in a workspace
+type ResultListOptions struct {
+	ListOptions
+	Workspace string
+}
+
+// VulnerabilityListOptions selects a page of vulnerabilities
+type VulnerabilityListOptions = ResultListOptions
+
+func resultsPath(orgID, projectID, analysisID, endpoint string) string {
+	return "/org/" + orgID + "/projects/" + projectID + "/analysis/" + analysisID + "/" + endpoint
+}
+
+// workspaceQuery selects a workspace, defaulting to the root workspace "."
+func workspaceQuery(workspace string) url.Values {
+	if workspace == "" {
+		workspace = "."
+	}
+	return url.Values{"workspace": {workspace}}
+}
+
+// VulnerabilityStats gets vulnerability statistics for an analysis
+func (s *ResultsService) VulnerabilityStats(ctx context.Context, orgID, projectID, analysisID, workspace string) (*VulnerabilityStats, error) {
+	var resp VulnerabilityStats
+	if err := s.client.do(ctx, "GET", resultsPath(orgID, projectID, analysisID, "vulnerabilities/stats"), workspaceQuery(workspace), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SBOMStats gets SBOM statistics for an analysis
+func (s *ResultsService) SBOMStats(ctx context.Context, orgID, projectID, analysisID, workspace string) (*SBOMStats, error) {
+	var resp SBOMStats
+	if err := s.client.do(ctx, "GET", resultsPath(orgID, projectID, analysisID, "sbom/stats"), workspaceQuery(workspace), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LicenseStats gets license statistics for an analysis
+func (s *ResultsService) LicenseStats(ctx context.Context, orgID, projectID, analysisID, workspace string) (*LicenseStats, error) {
+	var q url.Values
+	if workspace != "" {
+		q = url.Values{"workspace": {workspace}}
+	}
+
+	var resp SingleResponse[LicenseStats]
+	if err := s.client.do(ctx, "GET", resultsPath(orgID, projectID, analysisID, "licenses/stats"), q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// Vulnerabilities gets a page of vulnerabilities for an analysis
+func (s *ResultsService) Vulnerabilities(ctx context.Context, orgID, projectID, analysisID string, opts *VulnerabilityListOptions) (*PaginatedResponse[Vulnerability], error) {
+	return listResults[Vulnerability](ctx, s.client, resultsPath(orgID, projectID, analysisID, "vulnerabilities"), opts)
+}
+
+// EachVulnerabilityPage fetches vulnerabilities page by page, calling fn as
+// each page arrives so results can be processed incrementally
+func (s *ResultsService) EachVulnerabilityPage(ctx context.Context, orgID, projectID, analysisID string, opts *VulnerabilityListOptions, fn func(*PaginatedResponse[Vulnerability]) error) error {
+	pageOpts := VulnerabilityListOptions{}
+	if opts != nil {
+		pageOpts = *opts
+	}
+	for pageOpts.Page = 0; ; pageOpts.Page++ {
+		resp, err := s.Vulnerabilities(ctx, orgID, projectID, analysisID, &pageOpts)
+		if err != nil {
+			return err
+		}
+		if err := fn(resp); err != nil {
+			return err
+		}
+		if len(resp.Data) == 0 || pageOpts.Page+1 >= resp.TotalPages {
+			return nil
+		}
+	}
+}
+
+// AllVulnerabilities fetches every page of vulnerabilities for an analysis
+func (s *ResultsService) AllVulnerabilities(ctx context.Context, orgID, projectID, analysisID, workspace string) ([]Vulnerability, error) {
+	var all []Vulnerability
+	opts := &VulnerabilityListOptions{ListOptions: ListOptions{PerPage: 100}, Workspace: workspace}
+	err := s.EachVulnerabilityPage(ctx, orgID, projectID, analysisID, opts, func(resp *PaginatedResponse[Vulnerability]) error {
+		all = append(all, resp.Data...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Dependencies gets a page of the SBOM of an analysis
+func (s *ResultsService) Dependencies(ctx context.Context, orgID, projectID, analysisID string, opts *ResultListOptions) (*PaginatedResponse[Dependency], error) {
+	return listResults[Dependency](ctx, s.client, resultsPath(orgID, projectID, analysisID, "sbom"), opts)
+}
+
+// AllDependencies fetches the whole SBOM of an analysis
+func (s *ResultsService) AllDependencies(ctx context.Context, orgID, projectID, analysisID, workspace string) ([]Dependency, error) {
+	return allResults[Dependency](ctx, s.client, resultsPath(orgID, projectID, analysisID, "sbom"), workspace)
+}
+
+// Licenses gets a page of the licenses found by an analysis
+func (s *ResultsService) Licenses(ctx context.Context, orgID, projectID, analysisID string, opts *ResultListOptions) (*PaginatedResponse[License], error) {
+	return listResults[License](ctx, s.client, resultsPath(orgID, projectID, analysisID, "licenses"), opts)
+}
+
+// AllLicenses fetches every license found by an analysis
+func (s *ResultsService) AllLicenses(ctx context.Context, orgID, projectID, analysisID, workspace string) ([]License, error) {
+	return allResults[License](ctx, s.client, resultsPath(orgID, projectID, analysisID, "licenses"), workspace)
+}
+
+// listResults gets a page of a paginated result endpoint
+func listResults[T any](ctx context.Context, c *Client, path string, opts *ResultListOptions) (*PaginatedResponse[T], error) {
+	q := url.Values{}
+	if opts != nil {
+		q = opts.values()
+		if opts.Workspace != "" {
+			q.Set("workspace", opts.Workspace)
+		}
+	}
+
+	var resp PaginatedResponse[T]
+	if err := c.do(ctx, "GET", path, q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// allResults fetches every page of a paginated result endpoint
+func allResults[T any](ctx context.Context, c *Client, path, workspace string) ([]T, error) {
+	all := []T{}
+	opts := &ResultListOptions{ListOptions: ListOptions{PerPage: 100}, Workspace: workspace}
+	for ; ; opts.Page++ {
+		resp, err := listResults[T](ctx, c, path, opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Data...)
+		if len(resp.Data) == 0 || opts.Page+1 >= resp.TotalPages {
+			return all, nil
+		}
+	}
+}
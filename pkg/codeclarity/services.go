@@ -0,0 +1,67 @@
+package codeclarity
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions selects a page of a paginated listing. Pages are 0-indexed;
+// a zero PerPage uses the server default.
+type ListOptions struct {
+	Page    int
+	PerPage int
+}
+
+func (o *ListOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	q.Set("page", strconv.Itoa(o.Page))
+	if o.PerPage > 0 {
+		q.Set("entries_per_page", strconv.Itoa(o.PerPage))
+	}
+	return q
+}
+
+// AuthService covers the /auth endpoints
+type AuthService struct {
+	client *Client
+}
+
+// Authenticate exchanges an email and password for tokens
+func (s *AuthService) Authenticate(ctx context.Context, email, password string) (*AuthResponse, error) {
+	req := AuthRequest{
+		Email:    email,
+		Password: password,
+	}
+
+	var resp AuthResponse
+	if err := s.client.do(ctx, "POST", "/auth/authenticate", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*AuthResponse, error) {
+	req := RefreshRequest{
+		RefreshToken: refreshToken,
+	}
+
+	var resp AuthResponse
+	if err := s.client.do(ctx, "POST", "/auth/refresh", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CurrentUser returns the authenticated user
+func (s *AuthService) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	if err := s.client.do(ctx, "GET", "/auth/user", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,27 @@
+package codeclarity
+
+import "context"
+
+// TokenSource supplies the bearer token sent with each request.
+// Implementations may refresh expired tokens and must be safe for
+// concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token, such as
+// an API key
+type StaticToken string
+
+// Token returns the token itself
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// TokenSourceFunc adapts an ordinary function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f(ctx)
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
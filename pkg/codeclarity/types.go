@@ -1,9 +1,6 @@
-package api
+package codeclarity
 
-import (
-	"fmt"
-	"time"
-)
+import "time"
 
 // AuthRequest represents authentication credentials
 type AuthRequest struct {
@@ -59,15 +56,15 @@ type Organization struct {
 
 // Analyzer represents an analyzer configuration
 type Analyzer struct {
-	ID                 string     `json:"id"`
-	Name               string     `json:"name"`
-	Description        string     `json:"description"`
-	CreatedOn          time.Time  `json:"created_on"`
-	Steps              [][]Stage  `json:"steps"`
-	SupportedLanguages []string   `json:"supported_languages,omitempty"`
-	LanguageConfig     any        `json:"language_config,omitempty"`
-	Logo               string     `json:"logo,omitempty"`
-	Global             bool       `json:"global"`
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Description        string    `json:"description"`
+	CreatedOn          time.Time `json:"created_on"`
+	Steps              [][]Stage `json:"steps"`
+	SupportedLanguages []string  `json:"supported_languages,omitempty"`
+	LanguageConfig     any       `json:"language_config,omitempty"`
+	Logo               string    `json:"logo,omitempty"`
+	Global             bool      `json:"global"`
 }
 
 // Stage represents an analysis stage
@@ -79,12 +76,12 @@ type Stage struct {
 
 // AnalyzerCreateRequest represents the request to create an analyzer
 type AnalyzerCreateRequest struct {
-	Name               string     `json:"name"`
-	Description        string     `json:"description"`
-	Steps              [][]Stage  `json:"steps"`
-	SupportedLanguages []string   `json:"supported_languages,omitempty"`
-	LanguageConfig     any        `json:"language_config,omitempty"`
-	Logo               string     `json:"logo,omitempty"`
+	Name               string    `json:"name"`
+	Description        string    `json:"description"`
+	Steps              [][]Stage `json:"steps"`
+	SupportedLanguages []string  `json:"supported_languages,omitempty"`
+	LanguageConfig     any       `json:"language_config,omitempty"`
+	Logo               string    `json:"logo,omitempty"`
 }
 
 // Project represents a CodeClarity project
@@ -110,6 +107,13 @@ type ProjectImportRequest struct {
 	Description   string `json:"description,omitempty"`
 }
 
+// ProjectUpdateRequest changes the fields of a project that are set
+type ProjectUpdateRequest struct {
+	Name          *string `json:"name,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+}
+
 // Analysis represents an analysis run
 type Analysis struct {
 	ID               string           `json:"id"`
@@ -144,6 +148,16 @@ const (
 	StatusSuccess   AnalysisStatus = "success"
 )
 
+// Succeeded reports whether an analysis with this status finished with
+// results that will not change
+func (s AnalysisStatus) Succeeded() bool {
+	switch s {
+	case StatusSuccess, StatusCompleted, StatusFinished:
+		return true
+	}
+	return false
+}
+
 // AnalysisStep represents a step in an analysis
 type AnalysisStep struct {
 	Name    string `json:"name"`
@@ -162,6 +176,8 @@ type AnalysisCreateRequest struct {
 	ScheduleType     string                    `json:"schedule_type,omitempty"`
 	NextScheduledRun string                    `json:"next_scheduled_run,omitempty"`
 	IsActive         bool                      `json:"is_active"`
+	// SnapshotID analyzes an uploaded snapshot instead of the repository
+	SnapshotID string `json:"snapshot_id,omitempty"`
 }
 
 // VulnerabilityStats represents vulnerability statistics
@@ -188,13 +204,30 @@ type LicenseStats struct {
 	ByCompliance map[string]int `json:"by_compliance,omitempty"`
 }
 
+// Dependency is a package in the SBOM of an analysis
+type Dependency struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Direct     bool     `json:"is_direct"`
+	Transitive bool     `json:"is_transitive"`
+	Dev        bool     `json:"dev"`
+	Licenses   []string `json:"licenses,omitempty"`
+}
+
+// License is a license found by an analysis with the dependencies using it
+type License struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name,omitempty"`
+	Dependencies []string `json:"deps_using_license"`
+}
+
 // Vulnerability represents a merged vulnerability from analysis results
 type Vulnerability struct {
-	ID          string           `json:"Id"`
-	Affected    []AffectedVuln   `json:"Affected"`
-	Severity    Severity         `json:"Severity"`
-	Description string           `json:"Description"`
-	EPSS        *EPSS            `json:"EPSS,omitempty"`
+	ID          string         `json:"Id"`
+	Affected    []AffectedVuln `json:"Affected"`
+	Severity    Severity       `json:"Severity"`
+	Description string         `json:"Description"`
+	EPSS        *EPSS          `json:"EPSS,omitempty"`
 }
 
 // AffectedVuln represents an affected dependency
@@ -251,24 +284,3 @@ type SingleResponse[T any] struct {
 type CreatedResponse struct {
 	ID string `json:"id"`
 }
-
-// APIError represents an API error response
-type APIError struct {
-	StatusCode int    `json:"status_code"`
-	Status     string `json:"status"`
-	ErrorCode  string `json:"error_code,omitempty"`
-	Message    string `json:"message,omitempty"`
-}
-
-func (e *APIError) String() string {
-	if e.ErrorCode != "" {
-		if e.Message != "" {
-			return fmt.Sprintf("%s: %s", e.ErrorCode, e.Message)
-		}
-		return e.ErrorCode
-	}
-	if e.Message != "" {
-		return e.Message
-	}
-	return fmt.Sprintf("HTTP %d", e.StatusCode)
-}
@@ -0,0 +1,527 @@
+package semver
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Options changes how versions and ranges are interpreted
+type Options struct {
+	// Loose accepts the malformed versions npm's loose mode does
+	Loose bool
+	// IncludePrerelease lets prerelease versions satisfy ranges whose
+	// comparators have no prerelease on the same major.minor.patch tuple
+	IncludePrerelease bool
+}
+
+// Range is a parsed range: a union of comparator sets, each of which a
+// version must entirely satisfy
+type Range struct {
+	set  [][]*comparator
+	opts Options
+}
+
+// comparator is a primitive comparison such as ">=1.2.3". A comparator
+// without a version matches everything.
+type comparator struct {
+	op      string
+	version *Version
+	value   string
+}
+
+// ParseRange parses a range, desugaring hyphen, x-, tilde and caret ranges
+// into primitive comparators
+func ParseRange(r string, opts Options) (*Range, error) {
+	raw := strings.Join(strings.Fields(r), " ")
+	rng := &Range{opts: opts}
+	for _, part := range strings.Split(raw, "||") {
+		comps, err := parseComparatorSet(strings.TrimSpace(part), opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(comps) > 0 {
+			rng.set = append(rng.set, comps)
+		}
+	}
+	if len(rng.set) == 0 {
+		return nil, fmt.Errorf("invalid range: %s", raw)
+	}
+
+	// Sets that match nothing are dropped unless nothing else is left, and
+	// a set that matches everything makes the others redundant
+	if len(rng.set) > 1 {
+		first := rng.set[0]
+		var set [][]*comparator
+		for _, comps := range rng.set {
+			if !comps[0].isNullSet() {
+				set = append(set, comps)
+			}
+		}
+		switch {
+		case len(set) == 0:
+			set = [][]*comparator{first}
+		case len(set) > 1:
+			for _, comps := range set {
+				if len(comps) == 1 && comps[0].isAny() {
+					set = [][]*comparator{comps}
+					break
+				}
+			}
+		}
+		rng.set = set
+	}
+	return rng, nil
+}
+
+// MustParseRange is like ParseRange but panics on invalid ranges
+func MustParseRange(r string, opts Options) *Range {
+	rng, err := ParseRange(r, opts)
+	if err != nil {
+		panic(err)
+	}
+	return rng
+}
+
+// String returns the range desugared into primitive comparators, as npm
+// formats it
+func (r *Range) String() string {
+	sets := make([]string, len(r.set))
+	for i, comps := range r.set {
+		values := make([]string, len(comps))
+		for j, c := range comps {
+			values[j] = c.value
+		}
+		sets[i] = strings.TrimSpace(strings.Join(values, " "))
+	}
+	return strings.TrimSpace(strings.Join(sets, "||"))
+}
+
+// Test reports whether v satisfies the range
+func (r *Range) Test(v *Version) bool {
+	for _, comps := range r.set {
+		if testSet(comps, v, r.opts) {
+			return true
+		}
+	}
+	return false
+}
+
+func testSet(comps []*comparator, v *Version, opts Options) bool {
+	for _, c := range comps {
+		if !c.test(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 || opts.IncludePrerelease {
+		return true
+	}
+	// A prerelease only satisfies the set when a comparator opts into
+	// prereleases of the same tuple, so "^1.2.3-beta.1" matches
+	// "1.2.3-beta.2" but not "1.3.0-beta"
+	for _, c := range comps {
+		if c.version == nil || len(c.version.Prerelease) == 0 {
+			continue
+		}
+		if c.version.compareMain(v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies reports whether version satisfies rng. Invalid versions and
+// ranges satisfy nothing.
+func Satisfies(version, rng string, opts Options) bool {
+	r, err := ParseRange(rng, opts)
+	if err != nil {
+		return false
+	}
+	v, err := parse(version, opts.Loose)
+	if err != nil {
+		return false
+	}
+	return r.Test(v)
+}
+
+// MaxSatisfying returns the highest of versions satisfying rng, or "" if
+// none does. Invalid versions are skipped.
+func MaxSatisfying(versions []string, rng string, opts Options) string {
+	return bestSatisfying(versions, rng, opts, 1)
+}
+
+// MinSatisfying returns the lowest of versions satisfying rng, or "" if
+// none does. Invalid versions are skipped.
+func MinSatisfying(versions []string, rng string, opts Options) string {
+	return bestSatisfying(versions, rng, opts, -1)
+}
+
+// bestSatisfying returns the satisfying version that compares as dir
+// against all others, keeping the first of equal versions
+func bestSatisfying(versions []string, rng string, opts Options, dir int) string {
+	r, err := ParseRange(rng, opts)
+	if err != nil {
+		return ""
+	}
+	var best *Version
+	var result string
+	for _, s := range versions {
+		v, err := parse(s, opts.Loose)
+		if err != nil || !r.Test(v) {
+			continue
+		}
+		if best == nil || v.Compare(best) == dir {
+			best, result = v, s
+		}
+	}
+	return result
+}
+
+func newComparator(comp string, loose bool) (*comparator, error) {
+	comp = strings.Join(strings.Fields(comp), " ")
+	re := reComparator
+	if loose {
+		re = reComparatorLoose
+	}
+	m := re.FindStringSubmatch(comp)
+	if m == nil {
+		return nil, fmt.Errorf("invalid comparator: %s", comp)
+	}
+	c := &comparator{op: m[1]}
+	if c.op == "=" {
+		c.op = ""
+	}
+	if m[2] == "" {
+		return c, nil
+	}
+	v, err := parse(m[2], loose)
+	if err != nil {
+		return nil, err
+	}
+	c.version = v
+	c.value = c.op + v.String()
+	return c, nil
+}
+
+func (c *comparator) test(v *Version) bool {
+	if c.version == nil {
+		return true
+	}
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp == 0
+}
+
+func (c *comparator) isAny() bool {
+	return c.value == ""
+}
+
+// isNullSet reports whether the comparator is the one npm uses for ranges
+// nothing satisfies
+func (c *comparator) isNullSet() bool {
+	return c.value == "<0.0.0-0"
+}
+
+// parseComparatorSet turns one side of a "||" into primitive comparators
+func parseComparatorSet(rng string, opts Options) ([]*comparator, error) {
+	hyphen := reHyphenRange
+	if opts.Loose {
+		hyphen = reHyphenRangeLoose
+	}
+	if m := hyphen.FindStringSubmatch(rng); m != nil {
+		rng = replaceHyphen(m, opts.IncludePrerelease)
+	}
+	rng = reComparatorTrim.ReplaceAllString(rng, "${1}${2}${3}")
+	rng = reTildeTrim.ReplaceAllString(rng, "${1}~")
+	rng = reCaretTrim.ReplaceAllString(rng, "${1}^")
+
+	parts := strings.Split(rng, " ")
+	for i, part := range parts {
+		parts[i] = desugar(part, opts)
+	}
+	var list []string
+	for _, part := range reSpaces.Split(strings.Join(parts, " "), -1) {
+		part = replaceGTE0(part, opts)
+		if opts.Loose && !reComparatorLoose.MatchString(part) {
+			continue
+		}
+		list = append(list, part)
+	}
+
+	var comps []*comparator
+	seen := map[string]int{}
+	for _, part := range list {
+		c, err := newComparator(part, opts.Loose)
+		if err != nil {
+			return nil, err
+		}
+		if c.isNullSet() {
+			return []*comparator{c}, nil
+		}
+		if i, ok := seen[c.value]; ok {
+			comps[i] = c
+			continue
+		}
+		seen[c.value] = len(comps)
+		comps = append(comps, c)
+	}
+	if len(comps) > 1 {
+		if i, ok := seen[""]; ok {
+			comps = append(comps[:i], comps[i+1:]...)
+		}
+	}
+	return comps, nil
+}
+
+// desugar expands the caret, tilde, x-range and star forms of a comparator
+func desugar(comp string, opts Options) string {
+	comp = replaceEach(strings.Fields(comp), func(c string) string { return replaceCaret(c, opts) })
+	comp = replaceEach(strings.Fields(comp), func(c string) string { return replaceTilde(c, opts) })
+	comp = replaceEach(reSpaces.Split(comp, -1), func(c string) string { return replaceXRange(c, opts) })
+	return replaceStar(comp)
+}
+
+func replaceEach(comps []string, replace func(string) string) string {
+	for i, c := range comps {
+		comps[i] = replace(c)
+	}
+	return strings.Join(comps, " ")
+}
+
+// replaceCaret expands caret ranges, which allow changes that do not modify
+// the left-most non-zero element:
+//
+//	^1.2.3 => >=1.2.3 <2.0.0-0
+//	^0.2.3 => >=0.2.3 <0.3.0-0
+//	^0.0.3 => >=0.0.3 <0.0.4-0
+func replaceCaret(comp string, opts Options) string {
+	re := reCaret
+	if opts.Loose {
+		re = reCaretLoose
+	}
+	m := re.FindStringSubmatch(comp)
+	if m == nil {
+		return comp
+	}
+	major, minor, patch, pr := m[1], m[2], m[3], m[4]
+	z := ""
+	if opts.IncludePrerelease {
+		z = "-0"
+	}
+	if pr != "" {
+		pr = "-" + pr
+	}
+
+	switch {
+	case isX(major):
+		return ""
+	case isX(minor):
+		return fmt.Sprintf(">=%s.0.0%s <%s.0.0-0", major, z, inc(major))
+	case isX(patch):
+		if major == "0" {
+			return fmt.Sprintf(">=%s.%s.0%s <%s.%s.0-0", major, minor, z, major, inc(minor))
+		}
+		return fmt.Sprintf(">=%s.%s.0%s <%s.0.0-0", major, minor, z, inc(major))
+	}
+	low := fmt.Sprintf(">=%s.%s.%s", major, minor, patch)
+	if pr != "" {
+		low += pr
+	} else if major == "0" {
+		low += z
+	}
+	switch {
+	case major != "0":
+		return fmt.Sprintf("%s <%s.0.0-0", low, inc(major))
+	case minor != "0":
+		return fmt.Sprintf("%s <%s.%s.0-0", low, major, inc(minor))
+	}
+	return fmt.Sprintf("%s <%s.%s.%s-0", low, major, minor, inc(patch))
+}
+
+// replaceTilde expands tilde ranges, which allow patch-level changes when a
+// minor version is given and minor-level changes otherwise:
+//
+//	~1.2.3 => >=1.2.3 <1.3.0-0
+//	~1    => >=1.0.0 <2.0.0-0
+func replaceTilde(comp string, opts Options) string {
+	re := reTilde
+	if opts.Loose {
+		re = reTildeLoose
+	}
+	m := re.FindStringSubmatch(comp)
+	if m == nil {
+		return comp
+	}
+	major, minor, patch, pr := m[1], m[2], m[3], m[4]
+
+	switch {
+	case isX(major):
+		return ""
+	case isX(minor):
+		return fmt.Sprintf(">=%s.0.0 <%s.0.0-0", major, inc(major))
+	case isX(patch):
+		return fmt.Sprintf(">=%s.%s.0 <%s.%s.0-0", major, minor, major, inc(minor))
+	case pr != "":
+		return fmt.Sprintf(">=%s.%s.%s-%s <%s.%s.0-0", major, minor, patch, pr, major, inc(minor))
+	}
+	return fmt.Sprintf(">=%s.%s.%s <%s.%s.0-0", major, minor, patch, major, inc(minor))
+}
+
+// replaceXRange expands partial versions, with or without an operator:
+//
+//	1.2.x  => >=1.2.0 <1.3.0-0
+//	>1.2   => >=1.3.0
+//	<=1    => <2.0.0-0
+func replaceXRange(comp string, opts Options) string {
+	comp = strings.TrimSpace(comp)
+	re := reXRange
+	if opts.Loose {
+		re = reXRangeLoose
+	}
+	m := re.FindStringSubmatch(comp)
+	if m == nil {
+		return comp
+	}
+	op, major, minor, patch := m[1], m[2], m[3], m[4]
+	xMajor := isX(major)
+	xMinor := xMajor || isX(minor)
+	anyX := xMinor || isX(patch)
+	if op == "=" && anyX {
+		op = ""
+	}
+	pr := ""
+	if opts.IncludePrerelease {
+		pr = "-0"
+	}
+
+	switch {
+	case xMajor:
+		if op == ">" || op == "<" {
+			// Nothing is allowed
+			return "<0.0.0-0"
+		}
+		return "*"
+	case op != "" && anyX:
+		if xMinor {
+			minor = "0"
+		}
+		patch = "0"
+		switch op {
+		case ">":
+			// >1 => >=2.0.0, >1.2 => >=1.3.0
+			op = ">="
+			if xMinor {
+				major = inc(major)
+				minor = "0"
+			} else {
+				minor = inc(minor)
+			}
+		case "<=":
+			// <=0.7.x is <0.8.0-0, <=1 is <2.0.0-0
+			op = "<"
+			if xMinor {
+				major = inc(major)
+			} else {
+				minor = inc(minor)
+			}
+		}
+		if op == "<" {
+			pr = "-0"
+		}
+		return fmt.Sprintf("%s%s.%s.%s%s", op, major, minor, patch, pr)
+	case xMinor:
+		return fmt.Sprintf(">=%s.0.0%s <%s.0.0-0", major, pr, inc(major))
+	case anyX:
+		return fmt.Sprintf(">=%s.%s.0%s <%s.%s.0-0", major, minor, pr, major, inc(minor))
+	}
+	return comp
+}
+
+// replaceStar drops the first "*" and the operator before it, since the
+// empty comparator already matches everything
+func replaceStar(comp string) string {
+	comp = strings.TrimSpace(comp)
+	if loc := reStar.FindStringIndex(comp); loc != nil {
+		comp = comp[:loc[0]] + comp[loc[1]:]
+	}
+	return comp
+}
+
+// replaceGTE0 turns ">=0.0.0" into the empty comparator, or ">=0.0.0-0"
+// when prereleases are included
+func replaceGTE0(comp string, opts Options) string {
+	re := reGTE0
+	if opts.IncludePrerelease {
+		re = reGTE0Pre
+	}
+	comp = strings.TrimSpace(comp)
+	if re.MatchString(comp) {
+		return ""
+	}
+	return comp
+}
+
+// replaceHyphen expands hyphen ranges, whose partial upper bounds are
+// x-ranges:
+//
+//	1.2 - 3.4.5 => >=1.2.0 <=3.4.5
+//	1.2.3 - 3.4 => >=1.2.3 <3.5.0-0
+func replaceHyphen(m []string, includePrerelease bool) string {
+	from, fMajor, fMinor, fPatch, fPr := m[1], m[2], m[3], m[4], m[5]
+	to, tMajor, tMinor, tPatch, tPr := m[7], m[8], m[9], m[10], m[11]
+	z := ""
+	if includePrerelease {
+		z = "-0"
+	}
+
+	switch {
+	case isX(fMajor):
+		from = ""
+	case isX(fMinor):
+		from = fmt.Sprintf(">=%s.0.0%s", fMajor, z)
+	case isX(fPatch):
+		from = fmt.Sprintf(">=%s.%s.0%s", fMajor, fMinor, z)
+	case fPr != "":
+		from = ">=" + from
+	default:
+		from = ">=" + from + z
+	}
+
+	switch {
+	case isX(tMajor):
+		to = ""
+	case isX(tMinor):
+		to = fmt.Sprintf("<%s.0.0-0", inc(tMajor))
+	case isX(tPatch):
+		to = fmt.Sprintf("<%s.%s.0-0", tMajor, inc(tMinor))
+	case tPr != "":
+		to = fmt.Sprintf("<=%s.%s.%s-%s", tMajor, tMinor, tPatch, tPr)
+	case includePrerelease:
+		to = fmt.Sprintf("<%s.%s.%s-0", tMajor, tMinor, inc(tPatch))
+	default:
+		to = "<=" + to
+	}
+	return strings.TrimSpace(from + " " + to)
+}
+
+func isX(id string) bool {
+	return id == "" || id == "x" || id == "X" || id == "*"
+}
+
+// inc increments a numeric version part, dropping its leading zeros
+func inc(n string) string {
+	i, ok := new(big.Int).SetString(n, 10)
+	if !ok {
+		return n
+	}
+	return i.Add(i, big.NewInt(1)).String()
+}
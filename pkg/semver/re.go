@@ -0,0 +1,82 @@
+package semver
+
+import "regexp"
+
+// The expressions below follow node-semver's internal/re.js token by token,
+// so versions and ranges are accepted exactly when npm accepts them.
+const (
+	numericIdentifier      = `0|[1-9]\d*`
+	numericIdentifierLoose = `\d+`
+	nonNumericIdentifier   = `\d*[a-zA-Z-][a-zA-Z0-9-]*`
+
+	mainVersion = `(` + numericIdentifier + `)\.` +
+		`(` + numericIdentifier + `)\.` +
+		`(` + numericIdentifier + `)`
+	mainVersionLoose = `(` + numericIdentifierLoose + `)\.` +
+		`(` + numericIdentifierLoose + `)\.` +
+		`(` + numericIdentifierLoose + `)`
+
+	prereleaseIdentifier      = `(?:` + numericIdentifier + `|` + nonNumericIdentifier + `)`
+	prereleaseIdentifierLoose = `(?:` + numericIdentifierLoose + `|` + nonNumericIdentifier + `)`
+
+	prerelease      = `(?:-(` + prereleaseIdentifier + `(?:\.` + prereleaseIdentifier + `)*))`
+	prereleaseLoose = `(?:-?(` + prereleaseIdentifierLoose + `(?:\.` + prereleaseIdentifierLoose + `)*))`
+
+	buildIdentifier = `[a-zA-Z0-9-]+`
+	build           = `(?:\+(` + buildIdentifier + `(?:\.` + buildIdentifier + `)*))`
+
+	fullPlain = `v?` + mainVersion + prerelease + `?` + build + `?`
+	// loosePlain also accepts "=1.2.3" and "1.2.3beta", which are common in
+	// the npm registry
+	loosePlain = `[v=\s]*` + mainVersionLoose + prereleaseLoose + `?` + build + `?`
+
+	gtlt = `((?:<|>)?=?)`
+
+	xRangeIdentifier      = numericIdentifier + `|x|X|\*`
+	xRangeIdentifierLoose = numericIdentifierLoose + `|x|X|\*`
+
+	xRangePlain = `[v=\s]*(` + xRangeIdentifier + `)` +
+		`(?:\.(` + xRangeIdentifier + `)` +
+		`(?:\.(` + xRangeIdentifier + `)` +
+		`(?:` + prerelease + `)?` + build + `?` +
+		`)?)?`
+	xRangePlainLoose = `[v=\s]*(` + xRangeIdentifierLoose + `)` +
+		`(?:\.(` + xRangeIdentifierLoose + `)` +
+		`(?:\.(` + xRangeIdentifierLoose + `)` +
+		`(?:` + prereleaseLoose + `)?` + build + `?` +
+		`)?)?`
+
+	loneTilde = `(?:~>?)`
+	loneCaret = `(?:\^)`
+)
+
+var (
+	reFull  = regexp.MustCompile(`^` + fullPlain + `$`)
+	reLoose = regexp.MustCompile(`^` + loosePlain + `$`)
+
+	reXRange      = regexp.MustCompile(`^` + gtlt + `\s*` + xRangePlain + `$`)
+	reXRangeLoose = regexp.MustCompile(`^` + gtlt + `\s*` + xRangePlainLoose + `$`)
+
+	reTildeTrim  = regexp.MustCompile(`(\s*)` + loneTilde + `\s+`)
+	reTilde      = regexp.MustCompile(`^` + loneTilde + xRangePlain + `$`)
+	reTildeLoose = regexp.MustCompile(`^` + loneTilde + xRangePlainLoose + `$`)
+
+	reCaretTrim  = regexp.MustCompile(`(\s*)` + loneCaret + `\s+`)
+	reCaret      = regexp.MustCompile(`^` + loneCaret + xRangePlain + `$`)
+	reCaretLoose = regexp.MustCompile(`^` + loneCaret + xRangePlainLoose + `$`)
+
+	reComparator      = regexp.MustCompile(`^` + gtlt + `\s*(` + fullPlain + `)$|^$`)
+	reComparatorLoose = regexp.MustCompile(`^` + gtlt + `\s*(` + loosePlain + `)$|^$`)
+	// reComparatorTrim strips the whitespace between an operator and its
+	// version, so "> 1.2.3" becomes ">1.2.3"
+	reComparatorTrim = regexp.MustCompile(`(\s*)` + gtlt + `\s*(` + loosePlain + `|` + xRangePlain + `)`)
+
+	reHyphenRange      = regexp.MustCompile(`^\s*(` + xRangePlain + `)\s+-\s+(` + xRangePlain + `)\s*$`)
+	reHyphenRangeLoose = regexp.MustCompile(`^\s*(` + xRangePlainLoose + `)\s+-\s+(` + xRangePlainLoose + `)\s*$`)
+
+	reStar    = regexp.MustCompile(`(<|>)?=?\s*\*`)
+	reGTE0    = regexp.MustCompile(`^\s*>=\s*0\.0\.0\s*$`)
+	reGTE0Pre = regexp.MustCompile(`^\s*>=\s*0\.0\.0-0\s*$`)
+
+	reSpaces = regexp.MustCompile(`\s+`)
+)
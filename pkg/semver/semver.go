@@ -0,0 +1,209 @@
+// Package semver implements npm's flavor of semantic versioning, as defined
+// by the node-semver library.
+//
+// Versions are parsed strictly by Parse or, like npm's loose mode, by
+// ParseLoose. Ranges support everything npm does: primitive comparators,
+// hyphen ranges ("1.2.3 - 2.3.4"), x-ranges ("1.2.x", "*"), tilde and caret
+// ranges and unions joined by "||". Prerelease versions only satisfy a range
+// when one of its comparators has a prerelease on the same
+// major.minor.patch tuple, unless Options.IncludePrerelease is set.
+//
+//	semver.Satisfies("1.2.3", "^1.0.0", semver.Options{})            // true
+//	semver.MaxSatisfying([]string{"1.2.3", "1.9.0"}, "~1.2", semver.Options{}) // "1.2.3"
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxLength bounds the length of a version string
+	maxLength = 256
+	// maxSafeInteger is the largest version number npm accepts, the largest
+	// integer a JavaScript number holds exactly
+	maxSafeInteger = 1<<53 - 1
+)
+
+// Version is a parsed semantic version
+type Version struct {
+	Major uint64
+	Minor uint64
+	Patch uint64
+	// Prerelease holds the dot-separated prerelease identifiers. Numeric
+	// identifiers are stored without leading zeros.
+	Prerelease []string
+	// Build holds the build metadata identifiers, which are ignored when
+	// comparing versions
+	Build []string
+}
+
+// Parse parses a version in strict mode. A leading "v" and surrounding
+// whitespace are allowed.
+func Parse(v string) (*Version, error) {
+	return parse(v, false)
+}
+
+// ParseLoose parses a version in npm's loose mode, which also accepts
+// leading "=" signs, leading zeros and prereleases without a hyphen such as
+// "1.2.3beta"
+func ParseLoose(v string) (*Version, error) {
+	return parse(v, true)
+}
+
+// MustParse is like Parse but panics on invalid versions
+func MustParse(v string) *Version {
+	ver, err := Parse(v)
+	if err != nil {
+		panic(err)
+	}
+	return ver
+}
+
+func parse(v string, loose bool) (*Version, error) {
+	if len(v) > maxLength {
+		return nil, fmt.Errorf("version is longer than %d characters", maxLength)
+	}
+	re := reFull
+	if loose {
+		re = reLoose
+	}
+	m := re.FindStringSubmatch(strings.TrimSpace(v))
+	if m == nil {
+		return nil, fmt.Errorf("invalid version: %s", v)
+	}
+
+	ver := &Version{}
+	parts := []struct {
+		name string
+		dst  *uint64
+	}{{"major", &ver.Major}, {"minor", &ver.Minor}, {"patch", &ver.Patch}}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil || n > maxSafeInteger {
+			return nil, fmt.Errorf("invalid %s version: %s", part.name, v)
+		}
+		*part.dst = n
+	}
+	if m[4] != "" {
+		for _, id := range strings.Split(m[4], ".") {
+			ver.Prerelease = append(ver.Prerelease, normalizeIdentifier(id))
+		}
+	}
+	if m[5] != "" {
+		ver.Build = strings.Split(m[5], ".")
+	}
+	return ver, nil
+}
+
+// normalizeIdentifier drops the leading zeros of numeric prerelease
+// identifiers small enough to be numbers, as npm does
+func normalizeIdentifier(id string) string {
+	if !isNumeric(id) {
+		return id
+	}
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil || n >= maxSafeInteger {
+		return id
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+// String formats the version without its build metadata
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than
+// o. Build metadata is ignored.
+func (v *Version) Compare(o *Version) int {
+	if c := v.compareMain(o); c != 0 {
+		return c
+	}
+	return v.comparePre(o)
+}
+
+// LessThan reports whether v is lower than o
+func (v *Version) LessThan(o *Version) bool {
+	return v.Compare(o) < 0
+}
+
+// Equal reports whether v and o are equal, ignoring build metadata
+func (v *Version) Equal(o *Version) bool {
+	return v.Compare(o) == 0
+}
+
+func (v *Version) compareMain(o *Version) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		switch {
+		case pair[0] < pair[1]:
+			return -1
+		case pair[0] > pair[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparePre orders prereleases, a version without one being greater than
+// any with one
+func (v *Version) comparePre(o *Version) int {
+	switch {
+	case len(v.Prerelease) > 0 && len(o.Prerelease) == 0:
+		return -1
+	case len(v.Prerelease) == 0 && len(o.Prerelease) > 0:
+		return 1
+	}
+	for i := 0; ; i++ {
+		switch {
+		case i == len(v.Prerelease) && i == len(o.Prerelease):
+			return 0
+		case i == len(o.Prerelease):
+			return 1
+		case i == len(v.Prerelease):
+			return -1
+		}
+		if c := compareIdentifiers(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+}
+
+// compareIdentifiers compares prerelease identifiers: numeric ones
+// numerically and lower than alphanumeric ones, which compare as strings
+func compareIdentifiers(a, b string) int {
+	anum, bnum := isNumeric(a), isNumeric(b)
+	switch {
+	case anum && bnum:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case anum:
+		return -1
+	case bnum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package semver_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codeclarity.io/pkg/semver"
+)
+
+// The fixtures in testdata are ported from node-semver's test suite and
+// were checked against npm's semver 7.6.2 when generated
+
+// load decodes testdata/name into v
+func load(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("invalid %s: %v", name, err)
+	}
+}
+
+func parse(t *testing.T, v string, loose bool) *semver.Version {
+	t.Helper()
+	parse := semver.Parse
+	if loose {
+		parse = semver.ParseLoose
+	}
+	version, err := parse(v)
+	if err != nil {
+		t.Fatalf("parse %q (loose %v): %v", v, loose, err)
+	}
+	return version
+}
+
+type rangeCase struct {
+	Range   string         `json:"range"`
+	Version string         `json:"version"`
+	Options semver.Options `json:"options"`
+}
+
+func TestRangeInclude(t *testing.T) {
+	var cases []rangeCase
+	load(t, "range_include.json", &cases)
+	for _, tt := range cases {
+		if !semver.Satisfies(tt.Version, tt.Range, tt.Options) {
+			t.Errorf("Satisfies(%q, %q, %+v) = false, want true", tt.Version, tt.Range, tt.Options)
+		}
+	}
+}
+
+func TestRangeExclude(t *testing.T) {
+	var cases []rangeCase
+	load(t, "range_exclude.json", &cases)
+	for _, tt := range cases {
+		if semver.Satisfies(tt.Version, tt.Range, tt.Options) {
+			t.Errorf("Satisfies(%q, %q, %+v) = true, want false", tt.Version, tt.Range, tt.Options)
+		}
+	}
+}
+
+func TestRangeParse(t *testing.T) {
+	var cases []struct {
+		Range   string         `json:"range"`
+		Want    *string        `json:"want"`
+		Options semver.Options `json:"options"`
+	}
+	load(t, "range_parse.json", &cases)
+	for _, tt := range cases {
+		r, err := semver.ParseRange(tt.Range, tt.Options)
+		switch {
+		case tt.Want == nil && err == nil:
+			t.Errorf("ParseRange(%q, %+v) = %q, want an error", tt.Range, tt.Options, r)
+		case tt.Want != nil && err != nil:
+			t.Errorf("ParseRange(%q, %+v): %v", tt.Range, tt.Options, err)
+		case tt.Want != nil && r.String() != *tt.Want:
+			t.Errorf("ParseRange(%q, %+v) = %q, want %q", tt.Range, tt.Options, r, *tt.Want)
+		}
+	}
+}
+
+func TestComparisons(t *testing.T) {
+	var cases []struct {
+		Greater string `json:"greater"`
+		Lesser  string `json:"lesser"`
+		Loose   bool   `json:"loose"`
+	}
+	load(t, "comparisons.json", &cases)
+	for _, tt := range cases {
+		a, b := parse(t, tt.Greater, tt.Loose), parse(t, tt.Lesser, tt.Loose)
+		if got := a.Compare(b); got != 1 {
+			t.Errorf("Compare(%q, %q) = %d, want 1", tt.Greater, tt.Lesser, got)
+		}
+		if got := b.Compare(a); got != -1 {
+			t.Errorf("Compare(%q, %q) = %d, want -1", tt.Lesser, tt.Greater, got)
+		}
+		if !b.LessThan(a) || a.Equal(b) {
+			t.Errorf("%q is not lower than %q", tt.Lesser, tt.Greater)
+		}
+	}
+}
+
+func TestEquality(t *testing.T) {
+	var cases []struct {
+		Version string `json:"version"`
+		Equal   string `json:"equal"`
+		Loose   bool   `json:"loose"`
+	}
+	load(t, "equality.json", &cases)
+	for _, tt := range cases {
+		a, b := parse(t, tt.Version, tt.Loose), parse(t, tt.Equal, tt.Loose)
+		if got := a.Compare(b); got != 0 || !a.Equal(b) {
+			t.Errorf("Compare(%q, %q) = %d, want 0", tt.Version, tt.Equal, got)
+		}
+	}
+}
+
+func TestInvalidVersions(t *testing.T) {
+	var cases []struct {
+		Version string `json:"version"`
+		Reason  string `json:"reason"`
+		Loose   bool   `json:"loose"`
+	}
+	load(t, "invalid_versions.json", &cases)
+	for _, tt := range cases {
+		parse := semver.Parse
+		if tt.Loose {
+			parse = semver.ParseLoose
+		}
+		if v, err := parse(tt.Version); err == nil {
+			t.Errorf("parse %q (loose %v) = %s, want an error: %s", tt.Version, tt.Loose, v, tt.Reason)
+		}
+	}
+}
+
+type satisfyingCase struct {
+	Versions []string       `json:"versions"`
+	Range    string         `json:"range"`
+	Want     string         `json:"want"`
+	Options  semver.Options `json:"options"`
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	var cases []satisfyingCase
+	load(t, "max_satisfying.json", &cases)
+	for _, tt := range cases {
+		if got := semver.MaxSatisfying(tt.Versions, tt.Range, tt.Options); got != tt.Want {
+			t.Errorf("MaxSatisfying(%q, %q, %+v) = %q, want %q", tt.Versions, tt.Range, tt.Options, got, tt.Want)
+		}
+	}
+}
+
+func TestMinSatisfying(t *testing.T) {
+	var cases []satisfyingCase
+	load(t, "min_satisfying.json", &cases)
+	for _, tt := range cases {
+		if got := semver.MinSatisfying(tt.Versions, tt.Range, tt.Options); got != tt.Want {
+			t.Errorf("MinSatisfying(%q, %q, %+v) = %q, want %q", tt.Versions, tt.Range, tt.Options, got, tt.Want)
+		}
+	}
+}